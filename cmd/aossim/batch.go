@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+	"github.com/jruiznavarro/wargamestactics/internal/simulation"
+)
+
+// runBatch implements `aossim batch`. It plays many AI-vs-AI games between
+// two rosters in parallel and prints the matchup statistics.
+func runBatch(args []string) int {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	dataDir := fs.String("data", "data/factions", "Path to faction data directory")
+	roster1 := fs.String("p1roster", "", "Player 1 roster file, JSON or .txt army list")
+	roster2 := fs.String("p2roster", "", "Player 2 roster file, JSON or .txt army list")
+	games := fs.Int("n", 1000, "Number of games to play")
+	seed := fs.Int64("seed", 0, "Master RNG seed (0 = use current time)")
+	workers := fs.Int("workers", 0, "Games played in parallel (0 = one per CPU)")
+	rounds := fs.Int("rounds", 5, "Maximum battle rounds")
+	plan := fs.Int("battleplan", 1, "Battleplan to play (1-6 on General's Handbook table 1)")
+	confidence := fs.Float64("confidence", 0.95, "Confidence level of the reported intervals")
+	units := fs.String("units", "", "Also print a points efficiency table by warscroll: md or csv")
+	precision := fs.Float64("precision", 0, "Stop early once both win rate intervals are this narrow, e.g. 0.05 (0 = play all -n games)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: aossim batch -p1roster <file> -p2roster <file> [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *roster1 == "" || *roster2 == "" || *games < 1 || *confidence <= 0 || *confidence >= 1 || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if *units != "" && *units != "md" && *units != "csv" {
+		fmt.Fprintf(os.Stderr, "batch: unknown units table format '%s'\n", *units)
+		return 2
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(*dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, "batch: %v\n", err)
+		return 1
+	}
+	var rosters [2]*army.ArmyRoster
+	for i, path := range []string{*roster1, *roster2} {
+		r, err := loadRoster(path, registry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "batch: %v\n", err)
+			return 1
+		}
+		rosters[i] = r
+	}
+	m, err := simulation.NewMatchup(registry, rosters[0], rosters[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "batch: %v\n", err)
+		return 1
+	}
+	m.Rounds = *rounds
+	if m.Battleplan = board.GetBattleplan(board.BattleplanTable1, *plan); m.Battleplan == nil {
+		fmt.Fprintf(os.Stderr, "batch: unknown battleplan %d\n", *plan)
+		return 1
+	}
+
+	fmt.Printf("Playing %d games on %s | Seed: %d\n", *games, m.Battleplan.Name, *seed)
+	start := time.Now()
+	var stats *simulation.MatchupStats
+	if *precision > 0 {
+		stats, err = m.RunUntil(*games, simulation.Precision{Width: *precision, Confidence: *confidence}, *seed, *workers)
+	} else {
+		stats, err = m.Run(*games, *seed, *workers)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "batch: %v\n", err)
+		return 1
+	}
+	elapsed := time.Since(start)
+
+	fmt.Println(stats.Summary())
+	printIntervals(stats, *confidence)
+	if *units != "" {
+		table := simulation.UnitTableOf(stats.Results)
+		fmt.Println()
+		write := table.WriteMarkdown
+		if *units == "csv" {
+			write = table.WriteCSV
+		}
+		if err := write(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "batch: %v\n", err)
+			return 1
+		}
+	}
+	fmt.Printf("\n%d games in %s (%.0f games/s)\n", stats.TotalGames, elapsed.Round(time.Millisecond),
+		float64(stats.TotalGames)/elapsed.Seconds())
+	return 0
+}
+
+// printIntervals prints the win rates and VP margin of a batch with their
+// confidence intervals.
+func printIntervals(stats *simulation.MatchupStats, confidence float64) {
+	fmt.Printf("\n%.0f%% confidence intervals:\n", 100*confidence)
+	for _, p := range []struct {
+		id   int
+		name string
+	}{{stats.Player1ID, stats.Player1Name}, {stats.Player2ID, stats.Player2Name}} {
+		w := stats.WinRateInterval(p.id, confidence)
+		e := stats.WinRateExact(p.id, confidence)
+		fmt.Printf("  %s win rate: %.1f%% (Wilson %.1f-%.1f%%, exact %.1f-%.1f%%)\n",
+			p.name, 100*w.Estimate, 100*w.Low, 100*w.High, 100*e.Low, 100*e.High)
+	}
+	vp := stats.VPMarginInterval(confidence)
+	fmt.Printf("  %s VP lead: %+.2f (bootstrap %+.2f to %+.2f)\n", stats.Player1Name, vp.Estimate, vp.Low, vp.High)
+}
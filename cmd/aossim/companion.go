@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game"
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+	"github.com/jruiznavarro/wargamestactics/internal/ui"
+	"github.com/jruiznavarro/wargamestactics/pkg/dice"
+)
+
+// runCompanion implements `aossim companion`: the engine keeps score for a
+// game played on a physical table. Both players take turns at one terminal,
+// type in the dice they roll and declare where units stand instead of
+// giving coordinates. The game log is printed as it happens, with a
+// reminder of the rules that modify each roll before it is made.
+func runCompanion(args []string) int {
+	fs := flag.NewFlagSet("companion", flag.ContinueOnError)
+	dataDir := fs.String("data", "data/factions", "Path to faction data directory")
+	roster1 := fs.String("p1roster", "", "Player 1 roster file, JSON or .txt army list")
+	roster2 := fs.String("p2roster", "", "Player 2 roster file, JSON or .txt army list")
+	name1 := fs.String("p1name", "Player 1", "Player 1 name")
+	name2 := fs.String("p2name", "Player 2", "Player 2 name")
+	rounds := fs.Int("rounds", 5, "Battle rounds")
+	plan := fs.Int("battleplan", 1, "Battleplan number (1-6) from table 1")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *roster1 == "" || *roster2 == "" {
+		fmt.Fprintln(os.Stderr, "companion: -p1roster and -p2roster are required")
+		return 2
+	}
+
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(*dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, "companion: %v\n", err)
+		return 1
+	}
+	bp := board.GetBattleplan(board.BattleplanTable1, *plan)
+	if bp == nil {
+		fmt.Fprintf(os.Stderr, "companion: no battleplan %d\n", *plan)
+		return 2
+	}
+	g := game.NewGameFromBattleplan(0, bp)
+
+	in := bufio.NewReader(os.Stdin)
+	g.Roller = dice.NewRollerFromSource(dice.NewManual(in, os.Stdout))
+	g.LogOutput = os.Stdout
+	g.AddPlayer(ui.NewCompanionPlayer(1, *name1, in, os.Stdout))
+	g.AddPlayer(ui.NewCompanionPlayer(2, *name2, in, os.Stdout))
+
+	fmt.Printf("=== Companion: %s ===\n", bp.Name)
+	for i, path := range []string{*roster1, *roster2} {
+		roster, err := loadRoster(path, registry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "companion: %v\n", err)
+			return 1
+		}
+		f := registry.GetFaction(roster.FactionID)
+		if f == nil {
+			fmt.Fprintf(os.Stderr, "companion: %s: unknown faction '%s'\n", path, roster.FactionID)
+			return 1
+		}
+		checkRoster(f, roster, path)
+		points := deployRoster(g, f, roster, i+1)
+		fmt.Printf("P%d: %s (%d pts)\n", i+1, f.Name, points)
+	}
+	g.RegisterTerrainRules()
+
+	if err := runGame(g, *rounds); err != nil {
+		fmt.Fprintf(os.Stderr, "Game stopped: %v\n", err)
+		return 1
+	}
+	fmt.Printf("\nFinal score: %s %d VP, %s %d VP\n", *name1, g.VictoryPoints[1], *name2, g.VictoryPoints[2])
+	return 0
+}
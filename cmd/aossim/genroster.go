@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+)
+
+// runGenRoster implements `aossim gen-roster`. It makes random legal
+// rosters of a faction and prints them, or writes each to its own file.
+func runGenRoster(args []string) int {
+	fs := flag.NewFlagSet("gen-roster", flag.ContinueOnError)
+	dataDir := fs.String("data", "data/factions", "Path to faction data directory")
+	factionID := fs.String("faction", "", "Faction to generate rosters for (e.g. seraphon)")
+	points := fs.Int("points", army.DefaultPointsLimit, "Points limit of the rosters")
+	count := fs.Int("n", 1, "Number of rosters")
+	seed := fs.Int64("seed", 0, "RNG seed (0 = use current time)")
+	format := fs.String("format", "json", "Roster format: json or txt (army list)")
+	outDir := fs.String("o", "", "Directory to write one file per roster to (default standard output)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: aossim gen-roster -faction <id> [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *factionID == "" || *count < 1 || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if *format != "json" && *format != "txt" {
+		fmt.Fprintf(os.Stderr, "gen-roster: unknown format '%s'\n", *format)
+		return 2
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(*dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, "gen-roster: %v\n", err)
+		return 1
+	}
+	faction := registry.GetFaction(*factionID)
+	if faction == nil {
+		fmt.Fprintf(os.Stderr, "gen-roster: unknown faction '%s'\n", *factionID)
+		return 1
+	}
+
+	write := func(w io.Writer, r *army.ArmyRoster) error {
+		if *format == "txt" {
+			return army.FormatListText(w, r, faction)
+		}
+		out, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(out))
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Generating %d %s rosters of %d points | Seed: %d\n", *count, faction.Name, *points, *seed)
+	gen := army.NewRosterGenerator(*seed)
+	for i := 1; i <= *count; i++ {
+		r, err := gen.Generate(faction, *points)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gen-roster: %v\n", err)
+			return 1
+		}
+		if *outDir == "" {
+			if i > 1 {
+				fmt.Println()
+			}
+			err = write(os.Stdout, r)
+		} else {
+			err = writeRosterFile(filepath.Join(*outDir, fmt.Sprintf("%s_%03d.%s", faction.ID, i, *format)), r, write)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gen-roster: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// writeRosterFile creates path and writes the roster to it.
+func writeRosterFile(path string, r *army.ArmyRoster, write func(io.Writer, *army.ArmyRoster) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
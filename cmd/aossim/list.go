@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+)
+
+// runConvertList implements `aossim convert-list`. It converts a plain-text
+// army list to roster JSON, or roster JSON to a plain-text list, and prints
+// the result.
+func runConvertList(args []string) int {
+	fs := flag.NewFlagSet("convert-list", flag.ContinueOnError)
+	dataDir := fs.String("data", "data/factions", "Path to faction data directory")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: aossim convert-list [-data dir] <list.txt | roster.json>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(*dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, "convert-list: %v\n", err)
+		return 1
+	}
+
+	roster, err := loadRoster(path, registry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "convert-list: %v\n", err)
+		return 1
+	}
+	faction := registry.GetFaction(roster.FactionID)
+	if faction == nil {
+		fmt.Fprintf(os.Stderr, "convert-list: %s: unknown faction '%s'\n", path, roster.FactionID)
+		return 1
+	}
+
+	if isTextList(path) {
+		out, err := json.MarshalIndent(roster, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "convert-list: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(out))
+	} else if err := army.FormatListText(os.Stdout, roster, faction); err != nil {
+		fmt.Fprintf(os.Stderr, "convert-list: %v\n", err)
+		return 1
+	}
+
+	for _, e := range roster.Validate(faction) {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", e)
+	}
+	return 0
+}
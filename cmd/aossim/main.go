@@ -11,16 +11,43 @@ import (
 	"github.com/jruiznavarro/wargamestactics/internal/game/army"
 	"github.com/jruiznavarro/wargamestactics/internal/game/board"
 	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/setup"
 	"github.com/jruiznavarro/wargamestactics/internal/ui"
+	"github.com/jruiznavarro/wargamestactics/pkg/dice"
 )
 
+// subcommands maps the first CLI argument to an alternative entry point.
+// Without a recognised subcommand the simulator plays a game.
+var subcommands = map[string]func(args []string) int{
+	"validate-data": runValidateData,
+	"convert-list":  runConvertList,
+	"companion":     runCompanion,
+	"mathhammer":    runMathhammer,
+	"batch":         runBatch,
+	"matrix":        runMatrix,
+	"sweep":         runSweep,
+	"optimize":      runOptimize,
+	"points":        runPoints,
+	"gen-roster":    runGenRoster,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			os.Exit(run(os.Args[2:]))
+		}
+	}
+
 	mode := flag.String("mode", "pvai", "Game mode: pvp, pvai, aivai")
 	seed := flag.Int64("seed", 0, "RNG seed (0 = use current time)")
 	rounds := flag.Int("rounds", 5, "Maximum battle rounds")
 	dataDir := flag.String("data", "data/factions", "Path to faction data directory")
 	faction1 := flag.String("p1faction", "", "Player 1 faction (e.g. seraphon)")
 	faction2 := flag.String("p2faction", "", "Player 2 faction (e.g. tzeentch)")
+	roster1 := flag.String("p1roster", "", "Player 1 roster file, JSON or .txt army list (overrides -p1faction)")
+	roster2 := flag.String("p2roster", "", "Player 2 roster file, JSON or .txt army list (overrides -p2faction)")
+	verbose := flag.Bool("verbose", false, "Log which rules applied to each roll")
+	diceMode := flag.String("dice", "random", "Dice: random, or manual to type in the results of real dice")
 	flag.Parse()
 
 	if *seed == 0 {
@@ -43,10 +70,40 @@ func main() {
 		}
 	}
 
-	// Use battleplan if factions are loaded
-	useFactions := *faction1 != "" && *faction2 != ""
+	// Rosters name their own faction
+	var rosters [2]*army.ArmyRoster
+	for i, path := range []string{*roster1, *roster2} {
+		if path == "" {
+			continue
+		}
+		r, err := loadRoster(path, registry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		rosters[i] = r
+	}
+	factions, useFactions, err := resolveFactions([2]string{*faction1, *faction2}, rosters)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	*faction1, *faction2 = factions[0], factions[1]
 	var g *game.Game
 
+	// Reject illegal rosters before setting anything up
+	for i, path := range []string{*roster1, *roster2} {
+		if rosters[i] == nil {
+			continue
+		}
+		f := registry.GetFaction(rosters[i].FactionID)
+		if f == nil {
+			fmt.Fprintf(os.Stderr, "Roster %s: unknown faction '%s'\n", path, rosters[i].FactionID)
+			os.Exit(1)
+		}
+		checkRoster(f, rosters[i], path)
+	}
+
 	if useFactions {
 		// Set up with a random battleplan and data-driven armies
 		bp := board.GetBattleplan(board.BattleplanTable1, 1) // Default battleplan
@@ -55,6 +112,16 @@ func main() {
 	} else {
 		g = game.NewGame(*seed, 48, 24)
 	}
+	g.Verbose = *verbose
+
+	switch *diceMode {
+	case "random":
+	case "manual":
+		g.Roller = dice.NewRollerFromSource(dice.NewManual(os.Stdin, os.Stdout))
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown dice mode: %s (use random or manual)\n", *diceMode)
+		os.Exit(1)
+	}
 
 	switch *mode {
 	case "pvp":
@@ -88,31 +155,38 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Unknown faction: %s\n", *faction2)
 			os.Exit(1)
 		}
-		setupFactionArmy(g, f1, 1)
-		setupFactionArmy(g, f2, 2)
+		var points [2]int
+		for i, f := range []*army.Faction{f1, f2} {
+			ownerID := i + 1
+			if rosters[i] != nil {
+				points[i] = deployRoster(g, f, rosters[i], ownerID)
+				continue
+			}
 
-		// Register faction battle trait rules
-		army.RegisterFactionRules(g.Rules, f1, 1)
-		army.RegisterFactionRules(g.Rules, f2, 2)
+			setupFactionArmy(g, f, ownerID)
 
-		// Register battle formation rules (use first formation by default)
-		if len(f1.Formations) > 0 {
-			army.RegisterFormationRules(g.Rules, f1, 0, 1)
-			fmt.Printf("P1 Formation: %s\n", f1.Formations[0].Name)
-		}
-		if len(f2.Formations) > 0 {
-			army.RegisterFormationRules(g.Rules, f2, 0, 2)
-			fmt.Printf("P2 Formation: %s\n", f2.Formations[0].Name)
+			// Register faction battle traits
+			setup.ApplyBattleTraits(g, f, ownerID)
+
+			// Register battle formation rules (use first formation by default)
+			if len(f.Formations) > 0 {
+				army.RegisterFormationRules(g.Rules, f, 0, ownerID)
+				fmt.Printf("P%d Formation: %s\n", ownerID, f.Formations[0].Name)
+			}
+			points[i] = armyPoints(f, ownerID)
 		}
 
-		fmt.Printf("P1: %s (%d pts) | P2: %s (%d pts)\n\n", f1.Name, armyPoints(f1, 1), f2.Name, armyPoints(f2, 2))
+		fmt.Printf("P1: %s (%d pts) | P2: %s (%d pts)\n\n", f1.Name, points[0], f2.Name, points[1])
 	} else {
 		setupExampleTerrain(g)
 		setupExampleArmies(g)
 	}
 
 	g.RegisterTerrainRules()
-	g.RunGame(*rounds)
+	if err := runGame(g, *rounds); err != nil {
+		fmt.Fprintf(os.Stderr, "Game stopped: %v\n", err)
+		os.Exit(1)
+	}
 
 	fmt.Println()
 	fmt.Println("+============================================================+")
@@ -135,6 +209,14 @@ func main() {
 	}
 }
 
+// runGame plays the game, turning a die the dice source could not supply
+// (such as manual input ending) into an error.
+func runGame(g *game.Game, rounds int) (err error) {
+	defer dice.CatchRollError(&err)
+	g.RunGame(rounds)
+	return nil
+}
+
 // setupFactionArmy creates a sample army for a player from a faction.
 // Uses a selection of units up to ~1000 points for quick demonstration.
 func setupFactionArmy(g *game.Game, faction *army.Faction, ownerID int) {
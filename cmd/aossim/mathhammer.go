@@ -0,0 +1,153 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game"
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+)
+
+// runMathhammer implements `aossim mathhammer`. It prints the exact odds of
+// one warscroll's attacks against another: the distribution of damage dealt
+// and models slain, with the rules that applied.
+func runMathhammer(args []string) int {
+	fs := flag.NewFlagSet("mathhammer", flag.ContinueOnError)
+	dataDir := fs.String("data", "data/factions", "Path to faction data directory")
+	attackerName := fs.String("attacker", "", "Attacking warscroll, by name or ID")
+	defenderName := fs.String("defender", "", "Defending warscroll, by name or ID")
+	shooting := fs.Bool("shooting", false, "Use ranged weapons instead of melee weapons")
+	charged := fs.Bool("charged", false, "The attacker charged this turn")
+	reinforced := fs.Bool("reinforced", false, "The attacker is reinforced")
+	defReinforced := fs.Bool("defender-reinforced", false, "The defender is reinforced")
+	verbose := fs.Bool("v", false, "List the rules that applied to each weapon")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: aossim mathhammer -attacker <warscroll> -defender <warscroll> [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *attackerName == "" || *defenderName == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(*dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, "mathhammer: %v\n", err)
+		return 1
+	}
+	attackerFaction, attackerWS, err := findWarscroll(registry, *attackerName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mathhammer: %v\n", err)
+		return 1
+	}
+	defenderFaction, defenderWS, err := findWarscroll(registry, *defenderName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mathhammer: %v\n", err)
+		return 1
+	}
+
+	// The units are created in a game of their own so that their warscroll
+	// abilities and faction battle traits are registered as in a battle.
+	g := game.NewGame(0, 60, 44)
+	attacker := createMathhammerUnit(g, attackerFaction, attackerWS, 1, *reinforced)
+	defender := createMathhammerUnit(g, defenderFaction, defenderWS, 2, *defReinforced)
+	attacker.HasCharged = *charged
+
+	kind := "melee"
+	odds := game.CombatOdds(g.Rules, attacker, defender)
+	if *shooting {
+		kind = "shooting"
+		odds = game.ShootingOdds(g.Rules, attacker, defender)
+	}
+
+	fmt.Printf("%s (%d models) vs %s (%d models, %d wounds), %s\n",
+		attacker.Name, attacker.AliveModels(), defender.Name, defender.AliveModels(), defender.TotalCurrentWounds(), kind)
+	if len(odds.Weapons) == 0 {
+		fmt.Printf("  %s has no %s weapons\n", attacker.Name, kind)
+		return 0
+	}
+	for _, w := range odds.Weapons {
+		fmt.Printf("  %-24s %3d attacks, %5.2f damage on average\n", w.WeaponName, w.Attacks, w.Damage.Mean())
+		if *verbose && len(w.Trace) > 0 {
+			fmt.Printf("      why: %s\n", rules.FormatTrace(w.Trace))
+		}
+	}
+	printDistribution("Damage", odds.Damage)
+	printDistribution("Models slain", odds.Slain)
+	return 0
+}
+
+// findWarscroll looks a warscroll up by ID, then by name, in every faction.
+// A name may also be part of a single warscroll's name, e.g. "pink horrors".
+func findWarscroll(registry *army.FactionRegistry, name string) (*army.Faction, *army.Warscroll, error) {
+	for _, f := range registry.AllFactions() {
+		if ws := f.GetWarscroll(name); ws != nil {
+			return f, ws, nil
+		}
+	}
+	type match struct {
+		faction   *army.Faction
+		warscroll *army.Warscroll
+	}
+	var matches []match
+	for _, f := range registry.AllFactions() {
+		if ws := f.GetWarscrollByName(name); ws != nil {
+			matches = append(matches, match{f, ws})
+		}
+	}
+	if len(matches) == 0 {
+		part := strings.ToLower(name)
+		for _, f := range registry.AllFactions() {
+			for i := range f.Warscrolls {
+				if strings.Contains(strings.ToLower(f.Warscrolls[i].Name), part) {
+					matches = append(matches, match{f, &f.Warscrolls[i]})
+				}
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil, fmt.Errorf("unknown warscroll '%s'", name)
+	case 1:
+		return matches[0].faction, matches[0].warscroll, nil
+	}
+	var names []string
+	for _, m := range matches {
+		names = append(names, fmt.Sprintf("%s (%s)", m.warscroll.Name, m.warscroll.ID))
+	}
+	return nil, nil, fmt.Errorf("'%s' could be %s; use the warscroll ID", name, strings.Join(names, ", "))
+}
+
+func createMathhammerUnit(g *game.Game, faction *army.Faction, ws *army.Warscroll, ownerID int, reinforced bool) *core.Unit {
+	spec := &army.UnitSpec{Warscroll: ws, NumModels: ws.UnitSize, OwnerID: ownerID, Reinforced: reinforced}
+	if reinforced && ws.MaxSize > 0 {
+		spec.NumModels = ws.MaxSize
+	}
+	u := g.CreateUnit(spec.ToUnitParams())
+	spec.ApplyToUnit(u)
+	army.RegisterWarscrollAbilityRules(g.Rules, u, ws)
+	army.RegisterFactionRules(g.Rules, faction, ownerID)
+	return u
+}
+
+// printDistribution prints the chance of each count and of at least that
+// count, leaving out the tail below 0.05%.
+func printDistribution(title string, d game.Distribution) {
+	fmt.Printf("\n%s: %.2f on average\n", title, d.Mean())
+	fmt.Printf("  %5s %8s %8s\n", "n", "exactly", "at least")
+	for n, p := range d {
+		if atLeast := d.AtLeast(n); atLeast < 0.0005 {
+			break
+		} else if p >= 0.0005 || n == 0 {
+			fmt.Printf("  %5d %7.2f%% %7.2f%%\n", n, 100*p, 100*atLeast)
+		}
+	}
+}
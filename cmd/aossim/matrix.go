@@ -0,0 +1,152 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+	"github.com/jruiznavarro/wargamestactics/internal/simulation"
+)
+
+// runMatrix implements `aossim matrix`. It plays every pair of rosters on
+// every battleplan, from both sides, and writes the win rates as a
+// Markdown, CSV or JSON report.
+func runMatrix(args []string) int {
+	fs := flag.NewFlagSet("matrix", flag.ContinueOnError)
+	dataDir := fs.String("data", "data/factions", "Path to faction data directory")
+	rosters := fs.String("rosters", "data/rosters", "Comma-separated roster files or directories of them")
+	games := fs.Int("n", 100, "Games per pair of rosters and battleplan")
+	seed := fs.Int64("seed", 0, "Master RNG seed (0 = use current time)")
+	workers := fs.Int("workers", 0, "Games played in parallel (0 = one per CPU)")
+	rounds := fs.Int("rounds", 5, "Maximum battle rounds")
+	table := fs.Int("table", 0, "Battleplans to play: 1 or 2 for one General's Handbook table, 0 for both")
+	confidence := fs.Float64("confidence", 0.95, "Confidence level of the reported win rate intervals")
+	format := fs.String("format", "md", "Report format: md, csv or json")
+	out := fs.String("o", "", "Report file (default standard output)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: aossim matrix [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *games < 1 || *confidence <= 0 || *confidence >= 1 || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	var write func(*simulation.Matrix, io.Writer) error
+	switch *format {
+	case "md":
+		write = (*simulation.Matrix).WriteMarkdown
+	case "csv":
+		write = (*simulation.Matrix).WriteCSV
+	case "json":
+		write = (*simulation.Matrix).WriteJSON
+	default:
+		fmt.Fprintf(os.Stderr, "matrix: unknown format '%s'\n", *format)
+		return 2
+	}
+	var plans []board.Battleplan
+	switch *table {
+	case 0:
+		plans = board.AllBattleplans()
+	case 1:
+		plans = board.Table1Battleplans()
+	case 2:
+		plans = board.Table2Battleplans()
+	default:
+		fmt.Fprintf(os.Stderr, "matrix: unknown battleplan table %d\n", *table)
+		return 2
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(*dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, "matrix: %v\n", err)
+		return 1
+	}
+	paths, err := rosterFiles(*rosters)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "matrix: %v\n", err)
+		return 1
+	}
+	var contenders []simulation.Contender
+	for _, path := range paths {
+		r, err := loadRoster(path, registry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "matrix: %v\n", err)
+			return 1
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		c, err := simulation.NewContender(registry, name, r)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "matrix: %v\n", err)
+			return 1
+		}
+		contenders = append(contenders, c)
+	}
+
+	pairs := len(contenders) * (len(contenders) - 1) / 2
+	fmt.Fprintf(os.Stderr, "Playing %d games: %d pairs on %d battleplans | Seed: %d\n",
+		pairs*len(plans)**games, pairs, len(plans), *seed)
+	start := time.Now()
+	m, err := simulation.RunMatrix(contenders, plans, *games, *rounds, *seed, *workers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "matrix: %v\n", err)
+		return 1
+	}
+	m.Confidence = *confidence
+	fmt.Fprintf(os.Stderr, "Done in %s\n", time.Since(start).Round(time.Millisecond))
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "matrix: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := write(m, w); err != nil {
+		fmt.Fprintf(os.Stderr, "matrix: writing report: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// rosterFiles expands a comma-separated list of roster files and
+// directories into the roster files, JSON or .txt army lists, in order.
+func rosterFiles(list string) ([]string, error) {
+	var paths []string
+	for _, p := range strings.Split(list, ",") {
+		p = strings.TrimSpace(p)
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			paths = append(paths, p)
+			continue
+		}
+		entries, err := os.ReadDir(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			ext := strings.ToLower(filepath.Ext(e.Name()))
+			if !e.IsDir() && (ext == ".json" || ext == ".txt") {
+				paths = append(paths, filepath.Join(p, e.Name()))
+			}
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+	"github.com/jruiznavarro/wargamestactics/internal/simulation"
+)
+
+// runOptimize implements `aossim optimize`. It searches for the faction's
+// best lists against the opponent rosters and reports the finalists.
+func runOptimize(args []string) int {
+	fs := flag.NewFlagSet("optimize", flag.ContinueOnError)
+	dataDir := fs.String("data", "data/factions", "Path to faction data directory")
+	factionID := fs.String("faction", "", "Faction to build lists for (e.g. seraphon)")
+	points := fs.Int("points", army.DefaultPointsLimit, "Points limit of the lists")
+	opponents := fs.String("opponents", "", "Comma-separated opponent roster files or directories of them")
+	start := fs.String("start", "", "Comma-separated roster files to start the search from")
+	games := fs.Int("n", 20, "Games against each opponent per list tried")
+	generations := fs.Int("generations", 10, "Generations of mutation")
+	population := fs.Int("population", 6, "Lists kept each generation")
+	offspring := fs.Int("offspring", 12, "New lists tried each generation")
+	confirm := fs.Int("confirm", 200, "Games against each opponent replaying the best lists")
+	top := fs.Int("top", 3, "Best lists to report")
+	seed := fs.Int64("seed", 0, "Master RNG seed (0 = use current time)")
+	workers := fs.Int("workers", 0, "Games played in parallel (0 = one per CPU)")
+	rounds := fs.Int("rounds", 5, "Maximum battle rounds")
+	plan := fs.Int("battleplan", 1, "Battleplan to play (1-6 on General's Handbook table 1)")
+	confidence := fs.Float64("confidence", 0.95, "Confidence level of the reported intervals")
+	out := fs.String("o", "", "Report file (default standard output)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: aossim optimize -faction <id> -opponents <files> [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *factionID == "" || *opponents == "" || *games < 1 || *population < 1 || *confirm < 1 ||
+		*confidence <= 0 || *confidence >= 1 || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(*dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, "optimize: %v\n", err)
+		return 1
+	}
+	faction := registry.GetFaction(*factionID)
+	if faction == nil {
+		fmt.Fprintf(os.Stderr, "optimize: unknown faction '%s'\n", *factionID)
+		return 1
+	}
+	paths, err := rosterFiles(*opponents)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "optimize: %v\n", err)
+		return 1
+	}
+	var contenders []simulation.Contender
+	for _, path := range paths {
+		r, err := loadRoster(path, registry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "optimize: %v\n", err)
+			return 1
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		c, err := simulation.NewContender(registry, name, r)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "optimize: %v\n", err)
+			return 1
+		}
+		contenders = append(contenders, c)
+	}
+
+	o := simulation.NewOptimizer(faction, *points, contenders)
+	o.Games, o.Generations, o.Population, o.Offspring = *games, *generations, *population, *offspring
+	o.Confirm, o.Top, o.Confidence, o.Rounds, o.Workers = *confirm, *top, *confidence, *rounds, *workers
+	if o.Battleplan = board.GetBattleplan(board.BattleplanTable1, *plan); o.Battleplan == nil {
+		fmt.Fprintf(os.Stderr, "optimize: unknown battleplan %d\n", *plan)
+		return 1
+	}
+	if *start != "" {
+		for _, path := range strings.Split(*start, ",") {
+			r, err := loadRoster(strings.TrimSpace(path), registry)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "optimize: %v\n", err)
+				return 1
+			}
+			if r.FactionID != faction.ID {
+				fmt.Fprintf(os.Stderr, "optimize: %s is a %s roster, not %s\n", path, r.FactionID, faction.ID)
+				return 1
+			}
+			r.PointsLimit = *points
+			o.Start = append(o.Start, r)
+		}
+	}
+	o.Progress = func(gen int, best simulation.Candidate) {
+		fmt.Fprintf(os.Stderr, "Generation %d: best score %.3f (%d pts)\n",
+			gen, best.Score(), best.Roster.TotalPoints(faction))
+	}
+
+	fmt.Fprintf(os.Stderr, "Optimizing %s lists of %d points against %d opponents on %s | Seed: %d\n",
+		faction.Name, *points, len(contenders), o.Battleplan.Name, *seed)
+	began := time.Now()
+	res, err := o.Run(*seed)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "optimize: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Done in %s\n", time.Since(began).Round(time.Millisecond))
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "optimize: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := res.WriteMarkdown(w); err != nil {
+		fmt.Fprintf(os.Stderr, "optimize: writing report: %v\n", err)
+		return 1
+	}
+	return 0
+}
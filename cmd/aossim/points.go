@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+	"github.com/jruiznavarro/wargamestactics/internal/simulation"
+)
+
+// runPoints implements `aossim points`. It plays random lists of a faction
+// against the opponent rosters and suggests a points cost for each
+// warscroll.
+func runPoints(args []string) int {
+	fs := flag.NewFlagSet("points", flag.ContinueOnError)
+	dataDir := fs.String("data", "data/factions", "Path to faction data directory")
+	factionID := fs.String("faction", "", "Faction to price (e.g. seraphon)")
+	limit := fs.Int("points", army.DefaultPointsLimit, "Points limit of the random lists")
+	opponents := fs.String("opponents", "", "Comma-separated opponent roster files or directories of them")
+	outcome := fs.String("outcome", "vp", "Outcome to fit: vp (VP lead), score (wins and draws) or damage (damage lead)")
+	lists := fs.Int("lists", 200, "Random lists to play")
+	games := fs.Int("n", 10, "Games of each list against each opponent")
+	ridge := fs.Float64("ridge", 1, "Ridge penalty of the fit")
+	seed := fs.Int64("seed", 0, "Master RNG seed (0 = use current time)")
+	workers := fs.Int("workers", 0, "Games played in parallel (0 = one per CPU)")
+	rounds := fs.Int("rounds", 5, "Maximum battle rounds")
+	plan := fs.Int("battleplan", 1, "Battleplan to play (1-6 on General's Handbook table 1)")
+	confidence := fs.Float64("confidence", 0.95, "Confidence level of the suggested cost intervals")
+	format := fs.String("format", "md", "Report format: md or csv")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: aossim points -faction <id> -opponents <files> [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *factionID == "" || *opponents == "" || *lists < 2 || *games < 1 ||
+		*confidence <= 0 || *confidence >= 1 || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	fit, err := simulation.ParseOutcome(*outcome)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "points: %v\n", err)
+		return 2
+	}
+	var write func(*simulation.PointsEstimate, io.Writer) error
+	switch *format {
+	case "md":
+		write = (*simulation.PointsEstimate).WriteMarkdown
+	case "csv":
+		write = (*simulation.PointsEstimate).WriteCSV
+	default:
+		fmt.Fprintf(os.Stderr, "points: unknown format '%s'\n", *format)
+		return 2
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(*dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, "points: %v\n", err)
+		return 1
+	}
+	faction := registry.GetFaction(*factionID)
+	if faction == nil {
+		fmt.Fprintf(os.Stderr, "points: unknown faction '%s'\n", *factionID)
+		return 1
+	}
+	paths, err := rosterFiles(*opponents)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "points: %v\n", err)
+		return 1
+	}
+	var contenders []simulation.Contender
+	for _, path := range paths {
+		r, err := loadRoster(path, registry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "points: %v\n", err)
+			return 1
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		c, err := simulation.NewContender(registry, name, r)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "points: %v\n", err)
+			return 1
+		}
+		contenders = append(contenders, c)
+	}
+
+	e := simulation.NewPointsEstimator(faction, *limit, contenders)
+	e.Outcome, e.Lists, e.Games, e.Ridge = fit, *lists, *games, *ridge
+	e.Confidence, e.Rounds, e.Workers = *confidence, *rounds, *workers
+	if e.Battleplan = board.GetBattleplan(board.BattleplanTable1, *plan); e.Battleplan == nil {
+		fmt.Fprintf(os.Stderr, "points: unknown battleplan %d\n", *plan)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Playing %d random %s lists, %d games each against %d opponents on %s | Seed: %d\n",
+		*lists, faction.Name, *games, len(contenders), e.Battleplan.Name, *seed)
+	began := time.Now()
+	p, err := e.Run(*seed)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "points: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Done in %s\n", time.Since(began).Round(time.Millisecond))
+	if err := write(p, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "points: %v\n", err)
+		return 1
+	}
+	return 0
+}
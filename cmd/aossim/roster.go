@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game"
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/setup"
+)
+
+// loadRoster reads a roster file: a plain-text army list for .txt files,
+// otherwise roster JSON.
+func loadRoster(path string, registry *army.FactionRegistry) (*army.ArmyRoster, error) {
+	if !isTextList(path) {
+		return army.LoadRoster(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading army list %s: %w", path, err)
+	}
+	defer f.Close()
+	roster, _, err := army.ParseListText(f, registry)
+	if err != nil {
+		return nil, fmt.Errorf("importing army list %s:\n%w", path, err)
+	}
+	return roster, nil
+}
+
+// isTextList reports whether path names a plain-text army list.
+func isTextList(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".txt")
+}
+
+// resolveFactions works out each player's faction from the -pNfaction flags
+// and any rosters, which name their own faction. Armies come from faction
+// data only if both players have a faction; if neither has, the example
+// armies are used. One side alone is an error rather than being ignored.
+func resolveFactions(factions [2]string, rosters [2]*army.ArmyRoster) ([2]string, bool, error) {
+	for i, r := range rosters {
+		if r != nil {
+			factions[i] = r.FactionID
+		}
+	}
+	switch {
+	case factions[0] != "" && factions[1] != "":
+		return factions, true, nil
+	case factions[0] == "" && factions[1] == "":
+		return factions, false, nil
+	}
+	missing := 1
+	if factions[1] == "" {
+		missing = 2
+	}
+	return factions, false, fmt.Errorf("player %d needs -p%dfaction or -p%droster when the other player has an army", missing, missing, missing)
+}
+
+// checkRoster validates a roster against its faction and exits with the
+// validation errors if it is illegal.
+func checkRoster(faction *army.Faction, roster *army.ArmyRoster, path string) {
+	errs := roster.Validate(faction)
+	if len(errs) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Illegal roster %s:\n", path)
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "  - %v\n", e)
+	}
+	os.Exit(1)
+}
+
+// deployRoster deploys a validated roster for one player and reports the
+// selected formation and enhancements. Returns the points spent.
+func deployRoster(g *game.Game, faction *army.Faction, roster *army.ArmyRoster, ownerID int) int {
+	dep, err := setup.DeployRoster(g, faction, roster, ownerID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Deploying player %d roster: %v\n", ownerID, err)
+		os.Exit(1)
+	}
+
+	if dep.Formation != nil {
+		fmt.Printf("P%d Formation: %s\n", ownerID, dep.Formation.Name)
+	}
+	if dep.HeroicTrait != nil {
+		fmt.Printf("P%d Heroic Trait: %s\n", ownerID, dep.HeroicTrait.Name)
+	}
+	if dep.Artefact != nil {
+		fmt.Printf("P%d Artefact: %s\n", ownerID, dep.Artefact.Name)
+	}
+	return roster.TotalPoints(faction)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+)
+
+func TestResolveFactions(t *testing.T) {
+	seraphon := &army.ArmyRoster{FactionID: "seraphon"}
+	tests := []struct {
+		name        string
+		factions    [2]string
+		rosters     [2]*army.ArmyRoster
+		want        [2]string
+		useFactions bool
+		wantErr     bool
+	}{
+		{"none", [2]string{}, [2]*army.ArmyRoster{}, [2]string{}, false, false},
+		{"both flags", [2]string{"seraphon", "tzeentch"}, [2]*army.ArmyRoster{}, [2]string{"seraphon", "tzeentch"}, true, false},
+		{"roster overrides flag", [2]string{"tzeentch", "tzeentch"}, [2]*army.ArmyRoster{seraphon, nil}, [2]string{"seraphon", "tzeentch"}, true, false},
+		{"roster and flag", [2]string{"", "tzeentch"}, [2]*army.ArmyRoster{seraphon, nil}, [2]string{"seraphon", "tzeentch"}, true, false},
+		{"only p1 roster", [2]string{}, [2]*army.ArmyRoster{seraphon, nil}, [2]string{}, false, true},
+		{"only p2 roster", [2]string{}, [2]*army.ArmyRoster{nil, seraphon}, [2]string{}, false, true},
+		{"only p1 flag", [2]string{"seraphon", ""}, [2]*army.ArmyRoster{}, [2]string{}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, useFactions, err := resolveFactions(tt.factions, tt.rosters)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got factions %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want || useFactions != tt.useFactions {
+				t.Errorf("got %v, %v; want %v, %v", got, useFactions, tt.want, tt.useFactions)
+			}
+		})
+	}
+}
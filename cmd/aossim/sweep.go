@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+	"github.com/jruiznavarro/wargamestactics/internal/simulation"
+)
+
+// runSweep implements `aossim sweep`. It plays a matchup once for each value
+// of a warscroll field and reports how the win rate and VP shift.
+func runSweep(args []string) int {
+	fs := flag.NewFlagSet("sweep", flag.ContinueOnError)
+	dataDir := fs.String("data", "data/factions", "Path to faction data directory")
+	factionFile := fs.String("faction", "", "Faction JSON file with the warscroll, replacing the one in -data")
+	warscroll := fs.String("warscroll", "", "ID of the warscroll to change")
+	field := fs.String("field", "", "Warscroll field to sweep, e.g. stats.save, weapons[0].rend or points")
+	values := fs.String("values", "", "Values to try: a range such as 2..6 or a list such as 3,4,6")
+	roster1 := fs.String("p1roster", "", "Player 1 roster file, JSON or .txt army list")
+	roster2 := fs.String("p2roster", "", "Player 2 roster file, JSON or .txt army list")
+	games := fs.Int("n", 500, "Games per value")
+	seed := fs.Int64("seed", 0, "Master RNG seed, shared by every value (0 = use current time)")
+	workers := fs.Int("workers", 0, "Games played in parallel (0 = one per CPU)")
+	rounds := fs.Int("rounds", 5, "Maximum battle rounds")
+	plan := fs.Int("battleplan", 1, "Battleplan to play (1-6 on General's Handbook table 1)")
+	format := fs.String("format", "md", "Report format: md or csv")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: aossim sweep -warscroll <id> -field <path> -values <range> -p1roster <file> -p2roster <file> [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *warscroll == "" || *field == "" || *roster1 == "" || *roster2 == "" || *games < 1 || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	vals, err := parseValues(*values)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sweep: %v\n", err)
+		return 2
+	}
+	var write func(*simulation.Sweep, io.Writer) error
+	switch *format {
+	case "md":
+		write = (*simulation.Sweep).WriteMarkdown
+	case "csv":
+		write = (*simulation.Sweep).WriteCSV
+	default:
+		fmt.Fprintf(os.Stderr, "sweep: unknown format '%s'\n", *format)
+		return 2
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(*dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, "sweep: %v\n", err)
+		return 1
+	}
+	if *factionFile != "" {
+		if _, err := registry.LoadFaction(*factionFile); err != nil {
+			fmt.Fprintf(os.Stderr, "sweep: %v\n", err)
+			return 1
+		}
+	}
+	var rosters [2]*army.ArmyRoster
+	for i, path := range []string{*roster1, *roster2} {
+		r, err := loadRoster(path, registry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "sweep: %v\n", err)
+			return 1
+		}
+		rosters[i] = r
+	}
+	m, err := simulation.NewMatchup(registry, rosters[0], rosters[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sweep: %v\n", err)
+		return 1
+	}
+	m.Rounds = *rounds
+	if m.Battleplan = board.GetBattleplan(board.BattleplanTable1, *plan); m.Battleplan == nil {
+		fmt.Fprintf(os.Stderr, "sweep: unknown battleplan %d\n", *plan)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Sweeping %s %s over %v: %d games each on %s | Seed: %d\n",
+		*warscroll, *field, vals, *games, m.Battleplan.Name, *seed)
+	sweep, err := simulation.RunSweep(m, *warscroll, *field, vals, *games, *seed, *workers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sweep: %v\n", err)
+		return 1
+	}
+	if err := write(sweep, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "sweep: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseValues parses a range such as "2..6" or a list such as "3,4,6".
+func parseValues(s string) ([]int, error) {
+	if s == "" {
+		return nil, fmt.Errorf("no values given")
+	}
+	if from, to, ok := strings.Cut(s, ".."); ok {
+		lo, err1 := strconv.Atoi(strings.TrimSpace(from))
+		hi, err2 := strconv.Atoi(strings.TrimSpace(to))
+		if err1 != nil || err2 != nil || hi < lo {
+			return nil, fmt.Errorf("bad range '%s'", s)
+		}
+		var vals []int
+		for v := lo; v <= hi; v++ {
+			vals = append(vals, v)
+		}
+		return vals, nil
+	}
+	var vals []int
+	for _, part := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("bad value '%s'", part)
+		}
+		vals = append(vals, v)
+	}
+	return vals, nil
+}
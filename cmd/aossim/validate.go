@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+)
+
+// runValidateData implements `aossim validate-data`. It reports faction data
+// the engine ignores or cannot use, and exits non-zero when any is found.
+func runValidateData(args []string) int {
+	fs := flag.NewFlagSet("validate-data", flag.ContinueOnError)
+	dataDir := fs.String("data", "data/factions", "Path to faction data directory")
+	allowUnimpl := fs.Bool("allow-unimplemented", false, "Report unimplemented effects without failing")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	issues, err := army.ValidateDir(*dataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "validate-data: %v\n", err)
+		return 1
+	}
+
+	failing := 0
+	for _, is := range issues {
+		fmt.Println(is)
+		if is.Kind == army.IssueInvalid || !*allowUnimpl {
+			failing++
+		}
+	}
+
+	if len(issues) == 0 {
+		fmt.Printf("%s: no issues found\n", *dataDir)
+		return 0
+	}
+	fmt.Printf("\n%d issue(s), %d failing\n", len(issues), failing)
+	if failing > 0 {
+		return 1
+	}
+	return 0
+}
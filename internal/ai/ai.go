@@ -207,3 +207,39 @@ func (a *AIPlayer) distBetween(u1, u2 game.UnitView) float64 {
 	dy := u1.Position[1] - u2.Position[1]
 	return math.Sqrt(dx*dx + dy*dy)
 }
+
+// ChooseStoredDice spends stored dice on 2D6 casting and charge rolls that
+// need 7 or more, using the lowest pair that reaches the target, and on
+// single save rolls while the pool holds more than a few dice.
+func (a *AIPlayer) ChooseStoredDice(view *game.GameView, req game.DiceRequest) []int {
+	switch req.Kind {
+	case game.RollCasting, game.RollCharge:
+		if req.Dice != 2 || req.Target < 7 || req.Target > 12 {
+			return nil
+		}
+		var best []int
+		for i := range req.Pool {
+			for j := i + 1; j < len(req.Pool); j++ {
+				sum := req.Pool[i] + req.Pool[j]
+				if sum >= req.Target && (best == nil || sum < best[0]+best[1]) {
+					best = []int{req.Pool[i], req.Pool[j]}
+				}
+			}
+		}
+		return best
+	case game.RollSave:
+		if req.Dice != 1 || len(req.Pool) <= 3 {
+			return nil
+		}
+		lowest := 0
+		for _, d := range req.Pool {
+			if d > 1 && d >= req.Target && (lowest == 0 || d < lowest) {
+				lowest = d
+			}
+		}
+		if lowest > 0 {
+			return []int{lowest}
+		}
+	}
+	return nil
+}
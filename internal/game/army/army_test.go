@@ -168,6 +168,31 @@ func TestFaction_GetWarscrollByName(t *testing.T) {
 	}
 }
 
+func TestFaction_GetWarscrollByName_Fuzzy(t *testing.T) {
+	faction := &Faction{
+		Warscrolls: []Warscroll{
+			{ID: "guard", Name: "Saurus Guard"},
+			{ID: "warriors", Name: "Saurus Warriors"},
+			{ID: "oldblood", Name: "Saurus Oldblood on Carnosaur"},
+		},
+	}
+	for name, want := range map[string]string{
+		"saurus warriors":               "warriors",
+		"Saurus Oldblood on Carnosaur":  "oldblood",
+		"Saurus Oldblood, on Carnosaur": "oldblood",
+		"Saurus Warriorz":               "warriors",
+		"Saurus Gaurd":                  "guard",
+	} {
+		ws := faction.GetWarscrollByName(name)
+		if ws == nil || ws.ID != want {
+			t.Errorf("%q: expected %s, got %v", name, want, ws)
+		}
+	}
+	if ws := faction.GetWarscrollByName("Skink Starpriest"); ws != nil {
+		t.Errorf("expected no match for an unrelated name, got %s", ws.ID)
+	}
+}
+
 func TestFaction_Heroes(t *testing.T) {
 	faction := &Faction{
 		Warscrolls: []Warscroll{
@@ -207,8 +232,10 @@ func makeTestFaction() *Faction {
 func TestRoster_ValidArmy(t *testing.T) {
 	faction := makeTestFaction()
 	roster := &ArmyRoster{
-		FactionID:   "test",
-		PointsLimit: 2000,
+		FactionID:      "test",
+		PointsLimit:    2000,
+		HeroicTraitIdx: -1,
+		ArtefactIdx:    -1,
 		Entries: []RosterEntry{
 			{WarscrollID: "hero_a", IsGeneral: true},
 			{WarscrollID: "infantry"},
@@ -354,6 +381,92 @@ func TestRoster_TooManyHeroes(t *testing.T) {
 	}
 }
 
+func TestRoster_SelectionsOutOfRange(t *testing.T) {
+	faction := makeTestFaction()
+	faction.Formations = []BattleFormation{{Name: "Only"}}
+	faction.Artefacts = []Enhancement{{Name: "Trinket", Effect: "ward", Value: 6}}
+	roster := &ArmyRoster{
+		FactionID:      "other",
+		PointsLimit:    2000,
+		FormationIndex: 2,
+		ArtefactIdx:    0,
+		ArtefactUnitID: "hero_b",
+		Entries:        []RosterEntry{{WarscrollID: "hero_a", IsGeneral: true}},
+	}
+	want := map[string]bool{
+		"roster is for faction 'other', not 'test'":     false,
+		"formation index 2 out of range (0-0)":          false,
+		"artefact bearer 'hero_b' is not in the roster": false,
+	}
+	for _, e := range roster.Validate(faction) {
+		if _, ok := want[e.Error()]; ok {
+			want[e.Error()] = true
+		}
+	}
+	for msg, found := range want {
+		if !found {
+			t.Errorf("expected error %q", msg)
+		}
+	}
+}
+
+func TestRoster_EnhancementRestrictions(t *testing.T) {
+	faction := makeTestFaction()
+	faction.HeroicTraits = []Enhancement{{Name: "Brave", Effect: "ward", Value: 6}}
+	faction.Artefacts = []Enhancement{{Name: "Trinket", Effect: "ward", Value: 6}}
+
+	tests := []struct {
+		name   string
+		roster ArmyRoster
+		want   string
+	}{
+		{"both on general", ArmyRoster{Entries: []RosterEntry{{WarscrollID: "hero_a", IsGeneral: true}}},
+			"'Hero A' already has 'Brave'; a hero can have only one enhancement"},
+		{"unique general", ArmyRoster{ArtefactIdx: -1, Entries: []RosterEntry{{WarscrollID: "hero_b", IsGeneral: true}}},
+			"'Hero B' cannot take 'Brave': unique units cannot take enhancements"},
+		{"non-hero bearer", ArmyRoster{ArtefactUnitID: "infantry", Entries: []RosterEntry{{WarscrollID: "hero_a", IsGeneral: true}, {WarscrollID: "infantry"}}},
+			"'Infantry' cannot take 'Trinket': only Heroes can take enhancements"},
+		{"generic trait index", ArmyRoster{HeroicTraitIdx: 9, ArtefactIdx: -1, Entries: []RosterEntry{{WarscrollID: "hero_a", IsGeneral: true}}},
+			"heroic trait index 9 out of range (max 3)"},
+	}
+	for _, tt := range tests {
+		tt.roster.PointsLimit = 2000
+		errs := tt.roster.Validate(faction)
+		found := false
+		for _, e := range errs {
+			if e.Error() == tt.want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s: expected error %q, got %v", tt.name, tt.want, errs)
+		}
+	}
+
+	legal := &ArmyRoster{PointsLimit: 2000, HeroicTraitIdx: 3, ArtefactUnitID: "hero_a",
+		Entries: []RosterEntry{{WarscrollID: "hero_a"}, {WarscrollID: "hero_a", IsGeneral: true}}}
+	if errs := legal.Validate(faction); len(errs) > 0 {
+		t.Errorf("expected Scourge of Ghyran trait and separate artefact bearer to be legal, got %v", errs)
+	}
+}
+
+func TestRoster_ScourgeEnhancementsWithoutFactionLists(t *testing.T) {
+	faction := makeTestFaction() // No heroic traits or artefacts of its own
+
+	both := &ArmyRoster{PointsLimit: 2000, Entries: []RosterEntry{{WarscrollID: "hero_a", IsGeneral: true}}}
+	errs := both.Validate(faction)
+	if len(errs) != 1 || errs[0].Error() != "'Hero A' already has 'Battle-hardened'; a hero can have only one enhancement" {
+		t.Errorf("expected Scourge of Ghyran selections to be checked, got %v", errs)
+	}
+
+	outOfRange := &ArmyRoster{PointsLimit: 2000, HeroicTraitIdx: -1, ArtefactIdx: 3,
+		Entries: []RosterEntry{{WarscrollID: "hero_a", IsGeneral: true}}}
+	errs = outOfRange.Validate(faction)
+	if len(errs) != 1 || errs[0].Error() != "artefact index 3 out of range (max 2)" {
+		t.Errorf("expected artefact index error, got %v", errs)
+	}
+}
+
 func TestRoster_BuildUnits(t *testing.T) {
 	faction := makeTestFaction()
 	roster := &ArmyRoster{
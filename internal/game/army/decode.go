@@ -0,0 +1,407 @@
+package army
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// LoadError locates a problem in a faction data file. It renders as
+// `seraphon.json:123: warscroll "saurus_warriors": weapon 2: hit must be 2..6`.
+type LoadError struct {
+	File    string // Path of the file being loaded ("" for in-memory data)
+	Line    int    // 1-based line number (0 if unknown)
+	Context string // Enclosing element (e.g. `warscroll "saurus_warriors": weapon 2`)
+	Msg     string // What is wrong
+}
+
+func (e *LoadError) Error() string {
+	var b strings.Builder
+	if e.File != "" {
+		b.WriteString(e.File)
+		if e.Line > 0 {
+			fmt.Fprintf(&b, ":%d", e.Line)
+		}
+		b.WriteString(": ")
+	} else if e.Line > 0 {
+		fmt.Fprintf(&b, "line %d: ", e.Line)
+	}
+	if e.Context != "" {
+		b.WriteString(e.Context)
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Msg)
+	return b.String()
+}
+
+// LoadErrors returns the individual LoadErrors wrapped in err, which may be a
+// single *LoadError or an errors.Join of several.
+func LoadErrors(err error) []*LoadError {
+	var out []*LoadError
+	if multi, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range multi.Unwrap() {
+			out = append(out, LoadErrors(e)...)
+		}
+		return out
+	}
+	var le *LoadError
+	if errors.As(err, &le) {
+		out = append(out, le)
+	}
+	return out
+}
+
+// decodeFaction strictly decodes faction JSON. Unknown fields and type
+// mismatches are rejected with the line they occur on, and characteristic
+// values are range-checked. All range problems are reported together.
+func decodeFaction(file string, data []byte) (*Faction, error) {
+	w := &jsonWalker{data: data, offsets: make(map[string]int64)}
+	w.dec = json.NewDecoder(bytes.NewReader(data))
+	w.dec.UseNumber()
+
+	if err := w.walk(reflect.TypeOf(Faction{}), ""); err != nil {
+		var pe *pathError
+		if errors.As(err, &pe) {
+			// Best-effort decode so the context can name warscrolls by ID.
+			var partial Faction
+			_ = json.Unmarshal(data, &partial)
+			return nil, &LoadError{File: file, Line: w.line(pe.offset), Context: describePath(&partial, parentPath(pe.path)), Msg: pe.msg}
+		}
+		var se *json.SyntaxError
+		if errors.As(err, &se) {
+			return nil, &LoadError{File: file, Line: w.line(se.Offset), Msg: se.Error()}
+		}
+		return nil, &LoadError{File: file, Msg: err.Error()}
+	}
+	if _, err := w.dec.Token(); err != io.EOF {
+		return nil, &LoadError{File: file, Line: w.line(w.dec.InputOffset()), Msg: "unexpected data after faction object"}
+	}
+
+	var faction Faction
+	if err := json.Unmarshal(data, &faction); err != nil {
+		return nil, &LoadError{File: file, Msg: err.Error()}
+	}
+
+	var errs []error
+	for _, p := range checkFactionRanges(&faction) {
+		// Fall back to the nearest enclosing value when the field was omitted.
+		path := p.path
+		off, ok := w.offsets[path]
+		for !ok && path != "" {
+			path = parentPath(path)
+			off, ok = w.offsets[path]
+		}
+		errs = append(errs, &LoadError{File: file, Line: w.line(off), Context: describePath(&faction, parentPath(p.path)), Msg: p.msg})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return &faction, nil
+}
+
+// pathError is a problem at a JSON path such as `warscrolls[3].weapons[1].hit`.
+type pathError struct {
+	path   string
+	offset int64
+	msg    string
+}
+
+func (e *pathError) Error() string { return e.path + ": " + e.msg }
+
+// jsonWalker walks a JSON token stream alongside a Go type, rejecting
+// unknown object keys and kind mismatches and recording where each value starts.
+type jsonWalker struct {
+	dec     *json.Decoder
+	data    []byte
+	offsets map[string]int64
+}
+
+// start returns the offset of the next value, skipping separators.
+func (w *jsonWalker) start() int64 {
+	off := w.dec.InputOffset()
+	for off < int64(len(w.data)) {
+		switch w.data[off] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			off++
+			continue
+		}
+		break
+	}
+	return off
+}
+
+// line converts a byte offset into a 1-based line number.
+func (w *jsonWalker) line(off int64) int {
+	if off > int64(len(w.data)) {
+		off = int64(len(w.data))
+	}
+	return bytes.Count(w.data[:off], []byte("\n")) + 1
+}
+
+func (w *jsonWalker) walk(t reflect.Type, path string) error {
+	off := w.start()
+	w.offsets[path] = off
+	tok, err := w.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	mismatch := func(got string) error {
+		return &pathError{path: path, offset: off, msg: fmt.Sprintf("%s must be %s, got %s", fieldName(path), kindName(t), got)}
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			if t.Kind() != reflect.Struct {
+				return mismatch("object")
+			}
+			fields := jsonFields(t)
+			for w.dec.More() {
+				keyOff := w.start()
+				keyTok, err := w.dec.Token()
+				if err != nil {
+					return err
+				}
+				key := keyTok.(string)
+				ft, ok := fields[key]
+				child := key
+				if path != "" {
+					child = path + "." + key
+				}
+				if !ok {
+					return &pathError{path: child, offset: keyOff, msg: fmt.Sprintf("unknown field %q", key)}
+				}
+				if err := w.walk(ft, child); err != nil {
+					return err
+				}
+			}
+		case '[':
+			if t.Kind() != reflect.Slice {
+				return mismatch("array")
+			}
+			for i := 0; w.dec.More(); i++ {
+				if err := w.walk(t.Elem(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+		_, err := w.dec.Token() // closing delimiter
+		return err
+	case string:
+		if t.Kind() != reflect.String {
+			return mismatch("string")
+		}
+	case json.Number:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if _, err := strconv.ParseInt(v.String(), 10, 64); err != nil {
+				return mismatch("number " + v.String())
+			}
+		case reflect.Float32, reflect.Float64:
+		default:
+			return mismatch("number")
+		}
+	case bool:
+		if t.Kind() != reflect.Bool {
+			return mismatch("boolean")
+		}
+	case nil:
+		// null leaves the zero value, same as json.Unmarshal.
+	}
+	return nil
+}
+
+// jsonFields maps the JSON names of a struct's exported fields to their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func kindName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Struct:
+		return "an object"
+	case reflect.Slice:
+		return "an array"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	default:
+		return "an integer"
+	}
+}
+
+// fieldName returns the last key of a JSON path, or "value" for array elements.
+func fieldName(path string) string {
+	if path == "" {
+		return "faction"
+	}
+	if strings.HasSuffix(path, "]") {
+		return "value"
+	}
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+// parentPath strips the last key or index from a JSON path.
+func parentPath(path string) string {
+	i := strings.LastIndexAny(path, ".[")
+	if i < 0 {
+		return ""
+	}
+	return path[:i]
+}
+
+// pathLabels names the elements of faction arrays in error messages.
+var pathLabels = map[string]string{
+	"warscrolls":   "warscroll",
+	"weapons":      "weapon",
+	"spells":       "spell",
+	"prayers":      "prayer",
+	"abilities":    "ability",
+	"battleTraits": "battle trait",
+	"spellLore":    "spell lore",
+	"prayerLore":   "prayer lore",
+	"formations":   "formation",
+	"effects":      "effect",
+	"heroicTraits": "heroic trait",
+	"artefacts":    "artefact",
+}
+
+// describePath turns `warscrolls[3].weapons[1]` into
+// `warscroll "saurus_warriors": weapon 2`. Warscrolls are named by ID;
+// everything else by 1-based position.
+func describePath(f *Faction, path string) string {
+	if path == "" {
+		return ""
+	}
+	var parts []string
+	for _, seg := range strings.Split(path, ".") {
+		name, idx := seg, -1
+		if i := strings.IndexByte(seg, '['); i >= 0 {
+			name = seg[:i]
+			idx, _ = strconv.Atoi(strings.TrimSuffix(seg[i+1:], "]"))
+		}
+		if idx < 0 {
+			parts = append(parts, name)
+			continue
+		}
+		if name == "warscrolls" && f != nil && idx < len(f.Warscrolls) && f.Warscrolls[idx].ID != "" {
+			parts = append(parts, fmt.Sprintf("warscroll %q", f.Warscrolls[idx].ID))
+			continue
+		}
+		label := pathLabels[name]
+		if label == "" {
+			label = name
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", label, idx+1))
+	}
+	return strings.Join(parts, ": ")
+}
+
+// rangeProblem is an out-of-range value at a JSON path.
+type rangeProblem struct {
+	path string
+	msg  string
+}
+
+// checkFactionRanges reports characteristic values the engine cannot use.
+func checkFactionRanges(f *Faction) []rangeProblem {
+	var out []rangeProblem
+	add := func(path, format string, args ...interface{}) {
+		out = append(out, rangeProblem{path: path, msg: fmt.Sprintf(format, args...)})
+	}
+	between := func(path, field string, v, lo, hi int) {
+		if v < lo || v > hi {
+			add(path+"."+field, "%s must be %d..%d, got %d", field, lo, hi, v)
+		}
+	}
+	atLeast := func(path, field string, v, lo int) {
+		if v < lo {
+			add(path+"."+field, "%s must be at least %d, got %d", field, lo, v)
+		}
+	}
+	spell := func(p string, s *WarscrollSpell) {
+		between(p, "castingValue", s.CastingValue, minCastingValue, maxCastingValue)
+		atLeast(p, "range", s.Range, 0)
+		atLeast(p, "effectValue", s.EffectValue, 0)
+	}
+	prayer := func(p string, s *WarscrollPrayer) {
+		atLeast(p, "chantingValue", s.ChantingValue, 1)
+		atLeast(p, "range", s.Range, 0)
+		atLeast(p, "effectValue", s.EffectValue, 0)
+	}
+
+	if f.ID == "" {
+		add("id", "id must not be empty")
+	}
+	for i := range f.Warscrolls {
+		ws := &f.Warscrolls[i]
+		p := fmt.Sprintf("warscrolls[%d]", i)
+		if ws.ID == "" {
+			add(p+".id", "id must not be empty")
+		}
+		atLeast(p, "points", ws.Points, 0)
+		atLeast(p, "unitSize", ws.UnitSize, 1)
+		if ws.MaxSize != 0 && ws.MaxSize < ws.UnitSize {
+			add(p+".maxSize", "maxSize must be 0 or at least unitSize (%d), got %d", ws.UnitSize, ws.MaxSize)
+		}
+		atLeast(p, "baseSizeMM", ws.BaseSizeMM, 1)
+		atLeast(p+".stats", "move", ws.Stats.Move, 0)
+		between(p+".stats", "save", ws.Stats.Save, 2, 6)
+		atLeast(p+".stats", "control", ws.Stats.Control, 0)
+		atLeast(p+".stats", "health", ws.Stats.Health, 1)
+		if ws.WardSave != 0 {
+			between(p, "wardSave", ws.WardSave, 2, 6)
+		}
+		atLeast(p, "powerLevel", ws.PowerLevel, 0)
+		for j, w := range ws.Weapons {
+			wp := fmt.Sprintf("%s.weapons[%d]", p, j)
+			atLeast(wp, "range", w.Range, 0)
+			atLeast(wp, "attacks", w.Attacks, 1)
+			between(wp, "hit", w.ToHit, 2, 6)
+			between(wp, "wound", w.ToWound, 2, 6)
+			atLeast(wp, "rend", w.Rend, 0)
+			atLeast(wp, "damage", w.Damage, 1)
+		}
+		for j := range ws.Spells {
+			spell(fmt.Sprintf("%s.spells[%d]", p, j), &ws.Spells[j])
+		}
+		for j := range ws.Prayers {
+			prayer(fmt.Sprintf("%s.prayers[%d]", p, j), &ws.Prayers[j])
+		}
+	}
+	for i := range f.SpellLore {
+		spell(fmt.Sprintf("spellLore[%d]", i), &f.SpellLore[i])
+	}
+	for i := range f.PrayerLore {
+		prayer(fmt.Sprintf("prayerLore[%d]", i), &f.PrayerLore[i])
+	}
+	return out
+}
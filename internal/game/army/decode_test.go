@@ -0,0 +1,175 @@
+package army
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const decodeTestFaction = `{
+  "id": "mini",
+  "name": "Mini",
+  "warscrolls": [
+    {
+      "id": "mini_unit",
+      "name": "Mini Unit",
+      "unitSize": 5,
+      "baseSizeMM": 32,
+      "stats": {"move": 5, "save": 4, "control": 1, "health": 1},
+      "weapons": [
+        {"name": "Sword", "attacks": 2, "hit": 3, "wound": 4, "damage": 1},
+        {"name": "Spear", "attacks": 2, "hit": 3, "wound": 4, "damage": 1}
+      ]
+    }
+  ]
+}`
+
+func TestDecodeFaction_Valid(t *testing.T) {
+	f, err := decodeFaction("mini.json", []byte(decodeTestFaction))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(f.Warscrolls[0].Weapons) != 2 {
+		t.Errorf("expected 2 weapons, got %d", len(f.Warscrolls[0].Weapons))
+	}
+}
+
+func TestDecodeFaction_UnknownField(t *testing.T) {
+	data := strings.Replace(decodeTestFaction, `"baseSizeMM": 32,`, `"baseSizeMM": 32, "wardsave": 5,`, 1)
+	_, err := decodeFaction("mini.json", []byte(data))
+	if err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+	want := `mini.json:9: warscroll "mini_unit": unknown field "wardsave"`
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestDecodeFaction_TypeMismatch(t *testing.T) {
+	data := strings.Replace(decodeTestFaction, `"unitSize": 5`, `"unitSize": "five"`, 1)
+	_, err := decodeFaction("mini.json", []byte(data))
+	if err == nil {
+		t.Fatal("expected error for string unitSize")
+	}
+	want := `mini.json:8: warscroll "mini_unit": unitSize must be an integer, got string`
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestDecodeFaction_RangeErrors(t *testing.T) {
+	data := strings.Replace(decodeTestFaction, `{"name": "Spear", "attacks": 2, "hit": 3`, `{"name": "Spear", "attacks": 2, "hit": 7`, 1)
+	data = strings.Replace(data, `"save": 4`, `"save": 1`, 1)
+	_, err := decodeFaction("mini.json", []byte(data))
+	if err == nil {
+		t.Fatal("expected range errors")
+	}
+	errs := LoadErrors(err)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(errs), err)
+	}
+	if got := errs[0].Error(); got != `mini.json:10: warscroll "mini_unit": stats: save must be 2..6, got 1` {
+		t.Errorf("unexpected save error: %s", got)
+	}
+	if got := errs[1].Error(); got != `mini.json:13: warscroll "mini_unit": weapon 2: hit must be 2..6, got 7` {
+		t.Errorf("unexpected hit error: %s", got)
+	}
+}
+
+func TestDecodeFaction_SyntaxErrorLine(t *testing.T) {
+	data := strings.Replace(decodeTestFaction, `"unitSize": 5,`, `"unitSize": 5,,`, 1)
+	_, err := decodeFaction("mini.json", []byte(data))
+	errs := LoadErrors(err)
+	if len(errs) != 1 || errs[0].Line != 8 {
+		t.Errorf("expected a single error on line 8, got %v", err)
+	}
+}
+
+// TestFactionSchema_MatchesStructs keeps data/schema/faction.schema.json in
+// step with the json tags the strict decoder accepts.
+func TestFactionSchema_MatchesStructs(t *testing.T) {
+	path := filepath.Join("..", "..", "..", "data", "schema", "faction.schema.json")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Skipf("schema not found: %v", err)
+	}
+
+	type schemaNode struct {
+		Properties           map[string]json.RawMessage `json:"properties"`
+		AdditionalProperties *bool                      `json:"additionalProperties"`
+	}
+	var root struct {
+		schemaNode
+		Defs map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal(raw, &root); err != nil {
+		t.Fatalf("parsing schema: %v", err)
+	}
+
+	check := func(name string, node schemaNode, typ reflect.Type) {
+		if node.AdditionalProperties == nil || *node.AdditionalProperties {
+			t.Errorf("%s: schema must set additionalProperties to false", name)
+		}
+		var schemaKeys, structKeys []string
+		for k := range node.Properties {
+			schemaKeys = append(schemaKeys, k)
+		}
+		for k := range jsonFields(typ) {
+			structKeys = append(structKeys, k)
+		}
+		sort.Strings(schemaKeys)
+		sort.Strings(structKeys)
+		if !reflect.DeepEqual(schemaKeys, structKeys) {
+			t.Errorf("%s: schema properties %v do not match %s json tags %v", name, schemaKeys, typ.Name(), structKeys)
+		}
+	}
+
+	check("faction", root.schemaNode, reflect.TypeOf(Faction{}))
+	defs := map[string]reflect.Type{
+		"warscroll":             reflect.TypeOf(Warscroll{}),
+		"stats":                 reflect.TypeOf(WarscrollStats{}),
+		"weapon":                reflect.TypeOf(WarscrollWeapon{}),
+		"spell":                 reflect.TypeOf(WarscrollSpell{}),
+		"prayer":                reflect.TypeOf(WarscrollPrayer{}),
+		"ability":               reflect.TypeOf(WarscrollAbility{}),
+		"factionTrait":          reflect.TypeOf(FactionTrait{}),
+		"battleFormation":       reflect.TypeOf(BattleFormation{}),
+		"battleFormationEffect": reflect.TypeOf(BattleFormationEffect{}),
+		"enhancement":           reflect.TypeOf(Enhancement{}),
+	}
+	for name, typ := range defs {
+		var node schemaNode
+		if err := json.Unmarshal(root.Defs[name], &node); err != nil {
+			t.Errorf("schema $defs/%s: %v", name, err)
+			continue
+		}
+		check(name, node, typ)
+	}
+
+	var weapon struct {
+		Properties struct {
+			Abilities struct {
+				Items struct {
+					Enum []string `json:"enum"`
+				} `json:"items"`
+			} `json:"abilities"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(root.Defs["weapon"], &weapon); err != nil {
+		t.Fatal(err)
+	}
+	enum := weapon.Properties.Abilities.Items.Enum
+	if len(enum) != len(weaponAbilities) {
+		t.Errorf("schema lists %d weapon abilities, parser knows %d", len(enum), len(weaponAbilities))
+	}
+	for _, a := range enum {
+		if _, ok := weaponAbilities[a]; !ok {
+			t.Errorf("schema weapon ability %q is unknown to the parser", a)
+		}
+	}
+}
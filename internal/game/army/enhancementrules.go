@@ -0,0 +1,184 @@
+package army
+
+import (
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/phase"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+)
+
+// RegisterEnhancementRules compiles a heroic trait or artefact into rules
+// bound to the unit that carries it, and records the enhancement on the
+// unit. Start-of-phase effects (healStart, extraCP) act through the
+// OnPhaseStart lifecycle window; effects that act on the game queue
+// rules.Effects. Effects that change a characteristic with no rules trigger
+// (extraCast) are applied to the unit directly. Returns false if the effect
+// key has no implementation; the enhancement is still recorded on the unit.
+func RegisterEnhancementRules(engine *rules.Engine, unit *core.Unit, enh *Enhancement) bool {
+	unit.Enhancements = append(unit.Enhancements, enh.Name)
+
+	unitID := unit.ID
+	name := unit.Name + ": " + enh.Name
+	value := enh.Value
+
+	switch enh.Effect {
+	case "ward":
+		// This unit has Ward (X)+.
+		engine.AddRule(rules.Rule{
+			Name:    name,
+			Trigger: rules.BeforeWardSave,
+			Source:  rules.SourceEnhancement,
+			Condition: func(ctx *rules.Context) bool {
+				return ctx.Defender != nil && ctx.Defender.ID == unitID
+			},
+			Apply: func(ctx *rules.Context) {
+				if ctx.WardOverride == 0 || value < ctx.WardOverride {
+					ctx.WardOverride = value
+				}
+			},
+		})
+	case "extraAttacks":
+		// Add X to the Attacks characteristic of this unit's melee weapons.
+		engine.AddRule(rules.Rule{
+			Name:    name,
+			Trigger: rules.BeforeAttackCount,
+			Source:  rules.SourceEnhancement,
+			Condition: func(ctx *rules.Context) bool {
+				return ctx.Attacker != nil && ctx.Attacker.ID == unitID && !ctx.IsShooting
+			},
+			Apply: func(ctx *rules.Context) {
+				ctx.Modifiers.AttacksMod += value * ctx.Attacker.AliveModels()
+			},
+		})
+	case "extraRend":
+		// Pick 1 of this unit's melee weapons. Add X to that weapon's Rend.
+		weapon := pickedMeleeWeapon(unit)
+		engine.AddRule(rules.Rule{
+			Name:    name,
+			Trigger: rules.BeforeSaveRoll,
+			Source:  rules.SourceEnhancement,
+			Condition: func(ctx *rules.Context) bool {
+				return ctx.Attacker != nil && ctx.Attacker.ID == unitID &&
+					ctx.Weapon != nil && ctx.Weapon.Name == weapon && !ctx.IsShooting
+			},
+			Apply: func(ctx *rules.Context) {
+				ctx.Modifiers.RendMod += value
+			},
+		})
+	case "extraDamage":
+		// Pick 1 of this unit's melee weapons. Add X to that weapon's Damage.
+		weapon := pickedMeleeWeapon(unit)
+		engine.AddRule(rules.Rule{
+			Name:    name,
+			Trigger: rules.BeforeDamage,
+			Source:  rules.SourceEnhancement,
+			Condition: func(ctx *rules.Context) bool {
+				return ctx.Attacker != nil && ctx.Attacker.ID == unitID &&
+					ctx.Weapon != nil && ctx.Weapon.Name == weapon && !ctx.IsShooting
+			},
+			Apply: func(ctx *rules.Context) {
+				ctx.Modifiers.DamageMod += value
+			},
+		})
+	case "woundBonus":
+		// +X to wound rolls for this unit's melee attacks.
+		engine.AddRule(rules.Rule{
+			Name:    name,
+			Trigger: rules.BeforeWoundRoll,
+			Source:  rules.SourceEnhancement,
+			Condition: func(ctx *rules.Context) bool {
+				return ctx.Attacker != nil && ctx.Attacker.ID == unitID && !ctx.IsShooting
+			},
+			Apply: func(ctx *rules.Context) {
+				ctx.Modifiers.WoundMod += value
+			},
+		})
+	case "healStart":
+		// At the start of your hero phase, heal X wounds allocated to this unit.
+		engine.AddRule(rules.Rule{
+			Name:    name,
+			Trigger: rules.OnPhaseStart,
+			Source:  rules.SourceEnhancement,
+			Condition: func(ctx *rules.Context) bool {
+				return isOwnHeroPhaseStart(ctx, unitID)
+			},
+			Apply: func(ctx *rules.Context) {
+				ctx.HealWounds(ctx.Unit, value)
+			},
+		})
+	case "extraCP":
+		// At the start of your hero phase, if this unit is on the battlefield,
+		// you receive X extra command points.
+		engine.AddRule(rules.Rule{
+			Name:    name,
+			Trigger: rules.OnPhaseStart,
+			Source:  rules.SourceEnhancement,
+			Condition: func(ctx *rules.Context) bool {
+				return isOwnHeroPhaseStart(ctx, unitID)
+			},
+			Apply: func(ctx *rules.Context) {
+				ctx.GrantCommandPoints(ctx.Unit.OwnerID, value)
+			},
+		})
+	case "fightOnDeath":
+		// If this unit is destroyed in combat, it can fight immediately
+		// before being removed.
+		engine.AddRule(rules.Rule{
+			Name:    name,
+			Trigger: rules.OnUnitDestroyed,
+			Source:  rules.SourceEnhancement,
+			Condition: func(ctx *rules.Context) bool {
+				return ctx.Defender != nil && ctx.Defender.ID == unitID &&
+					ctx.Attacker != nil && !ctx.IsShooting
+			},
+			Apply: func(ctx *rules.Context) {
+				ctx.FightImmediately(ctx.Defender, ctx.Attacker)
+			},
+		})
+	case "cpOnEnemyCast":
+		// Each time an enemy WIZARD within 18" successfully casts a spell,
+		// roll a D6. On a 4+, you receive X command points.
+		engine.AddRule(rules.Rule{
+			Name:    name,
+			Trigger: rules.AfterCast,
+			Source:  rules.SourceEnhancement,
+			Condition: func(ctx *rules.Context) bool {
+				return ctx.Attacker != nil && ctx.Attacker.OwnerID != unit.OwnerID &&
+					!unit.IsDestroyed() && core.Distance(ctx.Attacker.Position(), unit.Position()) <= cpOnEnemyCastRange
+			},
+			Apply: func(ctx *rules.Context) {
+				ctx.AddEffect(rules.Effect{Kind: rules.EffectCommandPoints, PlayerID: unit.OwnerID, Amount: value, RollNeeded: 4})
+			},
+		})
+	case "extraCast":
+		// Can attempt X extra spells per hero phase.
+		unit.PowerLevel += value
+	default:
+		return false
+	}
+	return true
+}
+
+// cpOnEnemyCastRange is how close an enemy wizard must be to the bearer of a
+// cpOnEnemyCast enhancement when it casts.
+const cpOnEnemyCastRange = 18.0
+
+// isOwnHeroPhaseStart reports whether an OnPhaseStart window is being
+// evaluated for the given unit at the start of its owner's hero phase.
+func isOwnHeroPhaseStart(ctx *rules.Context, unitID core.UnitID) bool {
+	return ctx.Unit != nil && ctx.Unit.ID == unitID &&
+		ctx.PhaseType == string(phase.PhaseHero) && ctx.PlayerID == ctx.Unit.OwnerID
+}
+
+// pickedMeleeWeapon chooses the weapon an enhancement that says "pick 1 of
+// this unit's melee weapons" applies to: the one with the most attacks.
+func pickedMeleeWeapon(unit *core.Unit) string {
+	best := ""
+	bestAttacks := -1
+	for _, w := range unit.Weapons {
+		if w.IsMelee() && w.Attacks > bestAttacks {
+			best = w.Name
+			bestAttacks = w.Attacks
+		}
+	}
+	return best
+}
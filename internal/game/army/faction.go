@@ -1,5 +1,10 @@
 package army
 
+import (
+	"strings"
+	"unicode"
+)
+
 // Faction represents a complete army faction with its warscrolls and rules.
 type Faction struct {
 	ID            string             `json:"id"`            // Faction key (e.g. "seraphon")
@@ -34,13 +39,84 @@ func (f *Faction) GetWarscroll(id string) *Warscroll {
 }
 
 // GetWarscrollByName returns the first warscroll with the given name, or nil.
+// An exact match wins; otherwise names are compared ignoring case and
+// punctuation, and finally a single closest name within a couple of typos
+// is accepted.
 func (f *Faction) GetWarscrollByName(name string) *Warscroll {
 	for i := range f.Warscrolls {
 		if f.Warscrolls[i].Name == name {
 			return &f.Warscrolls[i]
 		}
 	}
-	return nil
+
+	key := normalizeName(name)
+	if key == "" {
+		return nil
+	}
+	for i := range f.Warscrolls {
+		if normalizeName(f.Warscrolls[i].Name) == key {
+			return &f.Warscrolls[i]
+		}
+	}
+
+	best, bestDist, ties := -1, maxNameTypos+1, 0
+	for i := range f.Warscrolls {
+		d := editDistance(normalizeName(f.Warscrolls[i].Name), key)
+		switch {
+		case d < bestDist:
+			best, bestDist, ties = i, d, 0
+		case d == bestDist:
+			ties++
+		}
+	}
+	if best < 0 || ties > 0 {
+		return nil
+	}
+	return &f.Warscrolls[best]
+}
+
+// maxNameTypos is the largest edit distance GetWarscrollByName tolerates.
+const maxNameTypos = 2
+
+// normalizeName lowercases a name and drops everything except letters and
+// digits, collapsing runs of other characters to a single space.
+func normalizeName(name string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		} else if r != '\'' && r != '’' {
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
 }
 
 // Heroes returns all Hero warscrolls in the faction.
@@ -64,3 +140,17 @@ func (f *Faction) NonHeroes() []Warscroll {
 	}
 	return units
 }
+
+// AvailableHeroicTraits returns the heroic traits a roster can select: the
+// faction's own followed by the universal Scourge of Ghyran traits.
+// ArmyRoster.HeroicTraitIdx indexes into this list.
+func (f *Faction) AvailableHeroicTraits() []Enhancement {
+	return append(append([]Enhancement{}, f.HeroicTraits...), DefaultScourgeOfGhyran().HeroicTraits...)
+}
+
+// AvailableArtefacts returns the artefacts a roster can select: the faction's
+// own followed by the universal Scourge of Ghyran artefacts.
+// ArmyRoster.ArtefactIdx indexes into this list.
+func (f *Faction) AvailableArtefacts() []Enhancement {
+	return append(append([]Enhancement{}, f.Artefacts...), DefaultScourgeOfGhyran().Artefacts...)
+}
@@ -4,37 +4,52 @@ import (
 	"math"
 
 	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/phase"
 	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
 )
 
 // RegisterFactionRules registers all battle trait rules for a faction into the rules engine.
 // This should be called once per player during game setup.
 func RegisterFactionRules(engine *rules.Engine, faction *Faction, ownerID int) {
+	registerFactionRules(engine, faction, ownerID)
+}
+
+// registerFactionRules is RegisterFactionRules, returning the battle trait
+// effect keys it implements for the faction.
+func registerFactionRules(engine *rules.Engine, faction *Faction, ownerID int) []string {
 	switch faction.ID {
 	case "seraphon":
-		registerSeraphonRules(engine, ownerID)
+		return registerSeraphonRules(engine, ownerID)
 	case "tzeentch":
-		registerTzeentchRules(engine, ownerID)
+		return registerTzeentchRules(engine, ownerID)
 	}
+	return nil
 }
 
 // RegisterFormationRules registers rules from a battle formation into the engine.
 func RegisterFormationRules(engine *rules.Engine, faction *Faction, formationIdx int, ownerID int) {
+	registerFormationRules(engine, faction, formationIdx, ownerID)
+}
+
+// registerFormationRules is RegisterFormationRules, returning the effect
+// keys it implements for the formation.
+func registerFormationRules(engine *rules.Engine, faction *Faction, formationIdx int, ownerID int) []string {
 	if formationIdx < 0 || formationIdx >= len(faction.Formations) {
-		return
+		return nil
 	}
 	formation := &faction.Formations[formationIdx]
 	switch faction.ID {
 	case "seraphon":
-		registerSeraphonFormation(engine, formation, ownerID)
+		return registerSeraphonFormation(engine, formation, ownerID)
 	case "tzeentch":
-		registerTzeentchFormation(engine, formation, ownerID)
+		return registerTzeentchFormation(engine, formation, ownerID)
 	}
+	return nil
 }
 
 // --- Seraphon Battle Traits ---
 
-func registerSeraphonRules(engine *rules.Engine, ownerID int) {
+func registerSeraphonRules(engine *rules.Engine, ownerID int) []string {
 	// Scaly Skin: SAURUS units have Ward 6+.
 	// Applied via BeforeWardSave — if defender is Saurus with no better ward, grant 6+.
 	engine.AddRule(rules.Rule{
@@ -75,50 +90,40 @@ func registerSeraphonRules(engine *rules.Engine, ownerID int) {
 	})
 
 	// Cold-blooded: Seraphon units wholly within 12" of a Seraphon Hero
-	// can ignore negative modifiers. Simplified: grant +1 to counteract -1 modifiers
-	// when near a friendly hero.
-	engine.AddRule(rules.Rule{
-		Name:    "Cold-blooded (Hit)",
-		Trigger: rules.BeforeHitRoll,
-		Source:  rules.SourceFaction,
-		Condition: func(ctx *rules.Context) bool {
-			if ctx.Attacker == nil || ctx.Attacker.OwnerID != ownerID ||
-				ctx.Attacker.FactionKeyword != "seraphon" {
-				return false
-			}
-			// Check if near a friendly Seraphon Hero
-			return isNearFriendlyHero(ctx.Attacker, ctx.AllUnits, "seraphon", 12.0)
-		},
-		Apply: func(ctx *rules.Context) {
-			// Counteract negative hit modifiers
-			if ctx.Modifiers.HitMod < 0 {
-				ctx.Modifiers.HitMod = 0
-			}
-		},
-	})
-
-	engine.AddRule(rules.Rule{
-		Name:    "Cold-blooded (Wound)",
-		Trigger: rules.BeforeWoundRoll,
-		Source:  rules.SourceFaction,
-		Condition: func(ctx *rules.Context) bool {
-			if ctx.Attacker == nil || ctx.Attacker.OwnerID != ownerID ||
-				ctx.Attacker.FactionKeyword != "seraphon" {
-				return false
-			}
-			return isNearFriendlyHero(ctx.Attacker, ctx.AllUnits, "seraphon", 12.0)
-		},
-		Apply: func(ctx *rules.Context) {
-			if ctx.Modifiers.WoundMod < 0 {
-				ctx.Modifiers.WoundMod = 0
-			}
-		},
-	})
+	// ignore negative modifiers to hit and wound rolls. The rules apply in
+	// the ignore layer, after every other modifier has been added.
+	for _, c := range []struct {
+		name    string
+		trigger rules.Trigger
+		field   rules.ModifierField
+	}{
+		{"Cold-blooded (Hit)", rules.BeforeHitRoll, rules.HitModifier},
+		{"Cold-blooded (Wound)", rules.BeforeWoundRoll, rules.WoundModifier},
+	} {
+		field := c.field
+		engine.AddRule(rules.Rule{
+			Name:    c.name,
+			Trigger: c.trigger,
+			Source:  rules.SourceFaction,
+			Layer:   rules.LayerIgnore,
+			Condition: func(ctx *rules.Context) bool {
+				if ctx.Attacker == nil || ctx.Attacker.OwnerID != ownerID ||
+					ctx.Attacker.FactionKeyword != "seraphon" {
+					return false
+				}
+				return isNearFriendlyHero(ctx.Attacker, ctx, "seraphon", 12.0)
+			},
+			Apply: func(ctx *rules.Context) {
+				ctx.IgnoreNegative(field)
+			},
+		})
+	}
+	return []string{"saurusWard", "chargeAttackBonus", "ignoreNegativeMods"}
 }
 
 // --- Seraphon Battle Formations ---
 
-func registerSeraphonFormation(engine *rules.Engine, formation *BattleFormation, ownerID int) {
+func registerSeraphonFormation(engine *rules.Engine, formation *BattleFormation, ownerID int) []string {
 	switch formation.Name {
 	case "Sunclaw Temple-host":
 		// Saurus units: +1 Rend on charge turn for melee weapons.
@@ -138,6 +143,7 @@ func registerSeraphonFormation(engine *rules.Engine, formation *BattleFormation,
 				ctx.Modifiers.RendMod++
 			},
 		})
+		return []string{"chargeRendBonus"}
 
 	case "Starborne Host":
 		// Seraphon units within 12" of a friendly Wizard have Ward 6+.
@@ -150,7 +156,7 @@ func registerSeraphonFormation(engine *rules.Engine, formation *BattleFormation,
 					ctx.Defender.FactionKeyword != "seraphon" {
 					return false
 				}
-				return isNearFriendlyWizard(ctx.Defender, ctx.AllUnits, ownerID, 12.0)
+				return isNearFriendlyWizard(ctx.Defender, ctx, ownerID, 12.0)
 			},
 			Apply: func(ctx *rules.Context) {
 				if ctx.Defender.WardSave == 0 || ctx.Defender.WardSave > 6 {
@@ -158,6 +164,7 @@ func registerSeraphonFormation(engine *rules.Engine, formation *BattleFormation,
 				}
 			},
 		})
+		return []string{"wardNearWizard"}
 
 	case "Shadowstrike Starhost":
 		// Skink units get +1 to charge rolls (simplified ambush).
@@ -175,12 +182,14 @@ func registerSeraphonFormation(engine *rules.Engine, formation *BattleFormation,
 				ctx.Modifiers.ChargeMod++
 			},
 		})
+		return []string{"chargeBonus"}
 	}
+	return nil
 }
 
 // --- Tzeentch Battle Traits ---
 
-func registerTzeentchRules(engine *rules.Engine, ownerID int) {
+func registerTzeentchRules(engine *rules.Engine, ownerID int) []string {
 	// Locus of Change: -1 to wound rolls for attacks targeting DAEMON units
 	// that are wholly within 9" of a friendly Tzeentch Hero.
 	engine.AddRule(rules.Rule{
@@ -193,22 +202,21 @@ func registerTzeentchRules(engine *rules.Engine, ownerID int) {
 				!ctx.Defender.HasTag("Daemon") {
 				return false
 			}
-			return isNearFriendlyHero(ctx.Defender, ctx.AllUnits, "tzeentch", 9.0)
+			return isNearFriendlyHero(ctx.Defender, ctx, "tzeentch", 9.0)
 		},
 		Apply: func(ctx *rules.Context) {
 			ctx.Modifiers.WoundMod--
 		},
 	})
 
-	// Masters of Destiny: Implemented at Game level via DestinyDice pool.
-	// The rules engine hook is in BeforeHitRoll/BeforeWoundRoll/BeforeSaveRoll
-	// but actual substitution is handled by the game loop. We register a marker rule.
-	// (Destiny Dice mechanics are managed in the Game struct, not the rules engine.)
+	// Masters of Destiny is not a rule: setup.ApplyBattleTraits gives the
+	// player a DestinyDicePool and the game offers it before each roll.
+	return []string{"locusWoundPenalty", "destinyDice"}
 }
 
 // --- Tzeentch Battle Formations ---
 
-func registerTzeentchFormation(engine *rules.Engine, formation *BattleFormation, ownerID int) {
+func registerTzeentchFormation(engine *rules.Engine, formation *BattleFormation, ownerID int) []string {
 	switch formation.Name {
 	case "Arcanite Cabal":
 		// Kairic Acolytes and Tzaangor units near a hero get +1 to casting.
@@ -225,12 +233,13 @@ func registerTzeentchFormation(engine *rules.Engine, formation *BattleFormation,
 				if !ctx.Attacker.HasTag("Arcanite") {
 					return false
 				}
-				return ctx.IsShooting && isNearFriendlyHero(ctx.Attacker, ctx.AllUnits, "tzeentch", 12.0)
+				return ctx.IsShooting && isNearFriendlyHero(ctx.Attacker, ctx, "tzeentch", 12.0)
 			},
 			Apply: func(ctx *rules.Context) {
 				ctx.Modifiers.HitMod++
 			},
 		})
+		return []string{"hitBonusNearHero"}
 
 	case "Wyrdflame Host":
 		// Flamers and Exalted Flamers get +1 Rend on ranged weapons.
@@ -249,11 +258,12 @@ func registerTzeentchFormation(engine *rules.Engine, formation *BattleFormation,
 				ctx.Modifiers.RendMod++
 			},
 		})
+		return []string{"rendBonus"}
 
 	case "Omniscient Oracles":
-		// Provides Destiny Dice manipulation — handled at Game level.
-		// No rules engine hook needed here.
+		// Provides Destiny Dice manipulation — not implemented yet.
 	}
+	return nil
 }
 
 // --- Warscroll Ability Rules ---
@@ -278,6 +288,12 @@ func RegisterWarscrollAbilityRules(engine *rules.Engine, unit *core.Unit, ws *Wa
 			registerHealOnKill(engine, unit, ab.Value)
 		case "minusOneToBeHit":
 			registerMinusOneToBeHit(engine, unit)
+		case "deathExplosion":
+			registerDeathExplosion(engine, unit, ab.Name, ab.Value)
+		case "flyoverMortals":
+			registerFlyoverMortals(engine, unit, ab.Name, ab.Value)
+		case "selfHeal":
+			registerSelfHeal(engine, unit, ab.Name, ab.Value)
 		}
 	}
 }
@@ -378,6 +394,87 @@ func registerMinusOneToBeHit(engine *rules.Engine, unit *core.Unit) {
 	})
 }
 
+// deathExplosionRange is how close enemy units must be to a unit with a
+// deathExplosion ability to suffer its mortal wounds.
+const deathExplosionRange = 3.0
+
+func registerDeathExplosion(engine *rules.Engine, unit *core.Unit, name string, value int) {
+	unitID := unit.ID
+	ownerID := unit.OwnerID
+	engine.AddRule(rules.Rule{
+		Name:    unit.Name + ": " + name,
+		Trigger: rules.OnUnitDestroyed,
+		Source:  rules.SourceUnitAbility,
+		Condition: func(ctx *rules.Context) bool {
+			return ctx.Defender != nil && ctx.Defender.ID == unitID
+		},
+		Apply: func(ctx *rules.Context) {
+			// The unit is already slain, so measure from where its models stood.
+			pos := lastPosition(ctx.Defender)
+			for _, other := range unitsWithin(ctx, pos, deathExplosionRange) {
+				if other.OwnerID != ownerID {
+					ctx.DealMortalWounds(other, value)
+				}
+			}
+		},
+	})
+}
+
+// flyoverReach is how close to a unit's path an enemy unit must be to have
+// been passed across.
+const flyoverReach = 1.0
+
+func registerFlyoverMortals(engine *rules.Engine, unit *core.Unit, name string, value int) {
+	unitID := unit.ID
+	ownerID := unit.OwnerID
+	engine.AddRule(rules.Rule{
+		Name:    unit.Name + ": " + name,
+		Trigger: rules.AfterMove,
+		Source:  rules.SourceUnitAbility,
+		Condition: func(ctx *rules.Context) bool {
+			return ctx.Attacker != nil && ctx.Attacker.ID == unitID
+		},
+		Apply: func(ctx *rules.Context) {
+			// Only units near the middle of the path can be near any of it.
+			half := core.Distance(ctx.Origin, ctx.Destination) / 2
+			mid := ctx.Origin.Towards(ctx.Destination, half)
+			for _, other := range unitsWithin(ctx, mid, half+flyoverReach) {
+				if other.OwnerID == ownerID {
+					continue
+				}
+				if core.DistanceToSegment(other.Position(), ctx.Origin, ctx.Destination) <= flyoverReach {
+					ctx.DealMortalWounds(other, value)
+				}
+			}
+		},
+	})
+}
+
+func registerSelfHeal(engine *rules.Engine, unit *core.Unit, name string, value int) {
+	unitID := unit.ID
+	engine.AddRule(rules.Rule{
+		Name:    unit.Name + ": " + name,
+		Trigger: rules.OnPhaseStart,
+		Source:  rules.SourceUnitAbility,
+		Condition: func(ctx *rules.Context) bool {
+			return ctx.Unit != nil && ctx.Unit.ID == unitID &&
+				ctx.PhaseType == string(phase.PhaseHero)
+		},
+		Apply: func(ctx *rules.Context) {
+			ctx.HealWounds(ctx.Unit, value)
+		},
+	})
+}
+
+// lastPosition returns where a unit stands, or where its first model stood
+// if it has been destroyed.
+func lastPosition(u *core.Unit) core.Position {
+	if !u.IsDestroyed() || len(u.Models) == 0 {
+		return u.Position()
+	}
+	return u.Models[0].Position
+}
+
 // --- Destiny Dice System (Tzeentch) ---
 
 // DestinyDicePool manages the Tzeentch Masters of Destiny mechanic.
@@ -457,6 +554,11 @@ func (p *DestinyDicePool) UseWorst() int {
 	return val
 }
 
+// Values returns a copy of the dice currently in the pool.
+func (p *DestinyDicePool) Values() []int {
+	return append([]int(nil), p.Dice...)
+}
+
 // AddDie adds a die with the given value to the pool.
 func (p *DestinyDicePool) AddDie(value int) {
 	if value >= 1 && value <= 6 {
@@ -468,18 +570,12 @@ func (p *DestinyDicePool) AddDie(value int) {
 
 // isNearFriendlyHero checks if a unit is within the given range of a friendly hero
 // with the specified faction keyword.
-func isNearFriendlyHero(unit *core.Unit, allUnits []*core.Unit, factionKW string, rangeInches float64) bool {
-	for _, other := range allUnits {
+func isNearFriendlyHero(unit *core.Unit, ctx *rules.Context, factionKW string, rangeInches float64) bool {
+	for _, other := range unitsWithin(ctx, unit.Position(), rangeInches) {
 		if other.ID == unit.ID || other.OwnerID != unit.OwnerID {
 			continue
 		}
-		if other.FactionKeyword != factionKW || !other.HasKeyword(core.KeywordHero) {
-			continue
-		}
-		if other.IsDestroyed() {
-			continue
-		}
-		if unitDistance(unit, other) <= rangeInches {
+		if other.FactionKeyword == factionKW && other.HasKeyword(core.KeywordHero) {
 			return true
 		}
 	}
@@ -487,26 +583,38 @@ func isNearFriendlyHero(unit *core.Unit, allUnits []*core.Unit, factionKW string
 }
 
 // isNearFriendlyWizard checks if a unit is within the given range of a friendly wizard.
-func isNearFriendlyWizard(unit *core.Unit, allUnits []*core.Unit, ownerID int, rangeInches float64) bool {
-	for _, other := range allUnits {
+func isNearFriendlyWizard(unit *core.Unit, ctx *rules.Context, ownerID int, rangeInches float64) bool {
+	for _, other := range unitsWithin(ctx, unit.Position(), rangeInches) {
 		if other.ID == unit.ID || other.OwnerID != ownerID {
 			continue
 		}
-		if !other.HasKeyword(core.KeywordWizard) || other.IsDestroyed() {
-			continue
-		}
-		if unitDistance(unit, other) <= rangeInches {
+		if other.HasKeyword(core.KeywordWizard) {
 			return true
 		}
 	}
 	return false
 }
 
-// unitDistance returns the distance between two units based on leader positions.
-func unitDistance(a, b *core.Unit) float64 {
-	posA := a.Position()
-	posB := b.Position()
-	dx := posA.X - posB.X
-	dy := posA.Y - posB.Y
+// unitsWithin returns the units that are not destroyed within r of p, using
+// the game's spatial grid when the context carries one and ctx.AllUnits
+// otherwise.
+func unitsWithin(ctx *rules.Context, p core.Position, r float64) []*core.Unit {
+	if ctx.Grid != nil {
+		return ctx.Grid.Within(p, r)
+	}
+	var units []*core.Unit
+	for _, u := range ctx.AllUnits {
+		if !u.IsDestroyed() && unitDistanceTo(u, p) <= r {
+			units = append(units, u)
+		}
+	}
+	return units
+}
+
+// unitDistanceTo returns the distance from a unit's leader position to p.
+func unitDistanceTo(u *core.Unit, p core.Position) float64 {
+	pos := u.Position()
+	dx := pos.X - p.X
+	dy := pos.Y - p.Y
 	return math.Sqrt(dx*dx + dy*dy)
 }
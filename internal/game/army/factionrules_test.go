@@ -1,9 +1,11 @@
 package army
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/phase"
 	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
 )
 
@@ -272,16 +274,28 @@ func TestColdBlooded_IgnoreNegativeMods(t *testing.T) {
 	hero := makeSeraphonHero(2, 1) // Within 12" of saurus
 	enemy := makeEnemyUnit(10, 2)
 
+	// Added after Cold-blooded, but the ignore layer still applies last.
+	engine.AddRule(rules.Rule{
+		Name: "Hard to Hit", Trigger: rules.BeforeHitRoll, Source: rules.SourceUnitAbility,
+		Apply: func(ctx *rules.Context) { ctx.Modifiers.HitMod -= 1 },
+	})
+	engine.AddRule(rules.Rule{
+		Name: "All-out Attack", Trigger: rules.BeforeHitRoll, Source: rules.SourceGlobal,
+		Apply: func(ctx *rules.Context) { ctx.Modifiers.HitMod += 1 },
+	})
+
 	ctx := &rules.Context{
 		Attacker: saurus,
 		Defender: enemy,
 		AllUnits: []*core.Unit{saurus, hero, enemy},
 	}
-	ctx.Modifiers.HitMod = -1 // Simulate negative modifier
 	engine.Evaluate(rules.BeforeHitRoll, ctx)
 
-	if ctx.Modifiers.HitMod != 0 {
-		t.Errorf("Expected Cold-blooded to remove negative hit mod, got %d", ctx.Modifiers.HitMod)
+	if ctx.Modifiers.HitMod != 1 {
+		t.Errorf("Expected Cold-blooded to ignore the -1 and keep the +1, got %d", ctx.Modifiers.HitMod)
+	}
+	if last := ctx.Trace[len(ctx.Trace)-1]; last.Rule != "Cold-blooded (Hit)" {
+		t.Errorf("Expected Cold-blooded to apply last, got %s", last.Rule)
 	}
 }
 
@@ -582,72 +596,102 @@ func TestDestinyDicePool_EmptyPool(t *testing.T) {
 
 // --- Enhancement System ---
 
-func TestApplyEnhancement_Ward(t *testing.T) {
+func TestRegisterEnhancementRules_Ward(t *testing.T) {
+	engine := rules.NewEngine()
 	u := makeSeraphonHero(1, 1)
-	enh := &Enhancement{
-		Name:   "Amulet of Destiny",
-		Effect: "ward",
-		Value:  5,
+	if !RegisterEnhancementRules(engine, u, &Enhancement{Name: "Amulet of Destiny", Effect: "ward", Value: 5}) {
+		t.Fatal("expected ward to be implemented")
+	}
+	if len(u.Enhancements) != 1 || u.Enhancements[0] != "Amulet of Destiny" {
+		t.Errorf("expected enhancement to be recorded on the unit, got %v", u.Enhancements)
+	}
+	if u.WardSave != 0 {
+		t.Errorf("expected base ward to be untouched, got %d", u.WardSave)
+	}
+
+	ctx := engine.Evaluate(rules.BeforeWardSave, &rules.Context{Defender: u})
+	if ctx.WardOverride != 5 {
+		t.Errorf("expected ward override 5, got %d", ctx.WardOverride)
 	}
-	ApplyEnhancement(u, enh)
-	if u.WardSave != 5 {
-		t.Errorf("Expected ward 5 after enhancement, got %d", u.WardSave)
+	other := makeSeraphonHero(2, 1)
+	ctx = engine.Evaluate(rules.BeforeWardSave, &rules.Context{Defender: other})
+	if ctx.WardOverride != 0 {
+		t.Errorf("expected no ward for another unit, got %d", ctx.WardOverride)
 	}
 }
 
-func TestApplyEnhancement_WardDoesNotOverwriteBetter(t *testing.T) {
+func TestRegisterEnhancementRules_PickedMeleeWeapon(t *testing.T) {
+	engine := rules.NewEngine()
 	u := makeSeraphonHero(1, 1)
-	u.WardSave = 4 // Already has ward 4+
-	enh := &Enhancement{
-		Name:   "Amulet of Destiny",
-		Effect: "ward",
-		Value:  5,
+	u.Weapons = []core.Weapon{
+		{Name: "Jaws", Range: 0, Attacks: 2, ToHit: 4, ToWound: 3, Damage: 1},
+		{Name: "Suntooth Maul", Range: 0, Attacks: 4, ToHit: 3, ToWound: 3, Damage: 2},
 	}
-	ApplyEnhancement(u, enh)
-	if u.WardSave != 4 {
-		t.Errorf("Expected ward to remain 4, got %d", u.WardSave)
+	RegisterEnhancementRules(engine, u, &Enhancement{Name: "Serpent God Dagger", Effect: "extraRend", Value: 1})
+
+	ctx := engine.Evaluate(rules.BeforeSaveRoll, &rules.Context{Attacker: u, Weapon: &u.Weapons[1]})
+	if ctx.Modifiers.RendMod != 1 {
+		t.Errorf("expected +1 rend on the picked weapon, got %d", ctx.Modifiers.RendMod)
+	}
+	ctx = engine.Evaluate(rules.BeforeSaveRoll, &rules.Context{Attacker: u, Weapon: &u.Weapons[0]})
+	if ctx.Modifiers.RendMod != 0 {
+		t.Errorf("expected no rend bonus on the other weapon, got %d", ctx.Modifiers.RendMod)
 	}
 }
 
-func TestApplyEnhancement_ExtraRend(t *testing.T) {
-	u := makeSeraphonSaurusUnit(1, 1)
-	originalRend := u.Weapons[0].Rend
-	enh := &Enhancement{
-		Name:   "Serpent God Dagger",
-		Effect: "extraRend",
-		Value:  1,
+func TestRegisterEnhancementRules_ExtraDamageAndCast(t *testing.T) {
+	engine := rules.NewEngine()
+	u := makeSeraphonHero(1, 1)
+	u.Weapons = []core.Weapon{{Name: "Suntooth Maul", Range: 0, Attacks: 4, ToHit: 3, ToWound: 3, Damage: 2}}
+	u.PowerLevel = 2
+	RegisterEnhancementRules(engine, u, &Enhancement{Name: "Wicked Shard", Effect: "extraDamage", Value: 1})
+	RegisterEnhancementRules(engine, u, &Enhancement{Name: "Arch-sorcerer", Effect: "extraCast", Value: 1})
+
+	ctx := engine.Evaluate(rules.BeforeDamage, &rules.Context{Attacker: u, Weapon: &u.Weapons[0]})
+	if ctx.Modifiers.DamageMod != 1 {
+		t.Errorf("expected +1 damage on the picked weapon, got %d", ctx.Modifiers.DamageMod)
 	}
-	ApplyEnhancement(u, enh)
-	if u.Weapons[0].Rend != originalRend+1 {
-		t.Errorf("Expected Rend %d, got %d", originalRend+1, u.Weapons[0].Rend)
+	if u.PowerLevel != 3 {
+		t.Errorf("expected PowerLevel 3, got %d", u.PowerLevel)
 	}
 }
 
-func TestApplyEnhancement_ExtraDamage(t *testing.T) {
-	u := makeSeraphonSaurusUnit(1, 1)
-	originalDmg := u.Weapons[0].Damage
-	enh := &Enhancement{
-		Name:   "Wicked Shard",
-		Effect: "extraDamage",
-		Value:  1,
+func TestRegisterEnhancementRules_StartOfHeroPhase(t *testing.T) {
+	engine := rules.NewEngine()
+	u := makeSeraphonHero(1, 1)
+	RegisterEnhancementRules(engine, u, &Enhancement{Name: "Itxi Grubs", Effect: "healStart", Value: 2})
+	RegisterEnhancementRules(engine, u, &Enhancement{Name: "Skilled Leader", Effect: "extraCP", Value: 1})
+
+	heroPhase := string(phase.PhaseHero)
+	ctx := engine.Evaluate(rules.OnPhaseStart, &rules.Context{Unit: u, PhaseType: heroPhase, PlayerID: 1})
+	want := []rules.Effect{
+		{Kind: rules.EffectHeal, Rule: "Saurus Oldblood: Itxi Grubs", UnitID: 1, Amount: 2},
+		{Kind: rules.EffectCommandPoints, Rule: "Saurus Oldblood: Skilled Leader", PlayerID: 1, Amount: 1},
+	}
+	if !reflect.DeepEqual(ctx.Effects, want) {
+		t.Errorf("expected %+v in own hero phase, got %+v", want, ctx.Effects)
+	}
+	ctx = engine.Evaluate(rules.OnPhaseStart, &rules.Context{Unit: u, PhaseType: heroPhase, PlayerID: 2})
+	if len(ctx.Effects) != 0 {
+		t.Errorf("expected nothing in the opponent's hero phase, got %+v", ctx.Effects)
 	}
-	ApplyEnhancement(u, enh)
-	if u.Weapons[0].Damage != originalDmg+1 {
-		t.Errorf("Expected Damage %d, got %d", originalDmg+1, u.Weapons[0].Damage)
+	ctx = engine.Evaluate(rules.OnPhaseStart, &rules.Context{Unit: u, PhaseType: string(phase.PhaseMovement), PlayerID: 1})
+	if len(ctx.Effects) != 0 {
+		t.Errorf("expected nothing in the movement phase, got %+v", ctx.Effects)
 	}
 }
 
-func TestApplyEnhancement_ExtraCast(t *testing.T) {
-	u := makeSeraphonWizard(1, 1)
-	u.PowerLevel = 2
-	enh := &Enhancement{
-		Name:   "Arch-sorcerer",
-		Effect: "extraCast",
-		Value:  1,
+func TestRegisterEnhancementRules_Unimplemented(t *testing.T) {
+	engine := rules.NewEngine()
+	u := makeSeraphonHero(1, 1)
+	if RegisterEnhancementRules(engine, u, &Enhancement{Name: "Strategic Genius", Effect: "redeploy"}) {
+		t.Error("expected unknown effect to report false")
 	}
-	ApplyEnhancement(u, enh)
-	if u.PowerLevel != 3 {
-		t.Errorf("Expected PowerLevel 3, got %d", u.PowerLevel)
+	if engine.RuleCount() != 0 {
+		t.Errorf("expected no rules, got %d", engine.RuleCount())
+	}
+	if len(u.Enhancements) != 1 {
+		t.Error("expected enhancement to be recorded even without an implementation")
 	}
 }
 
@@ -821,6 +865,92 @@ func TestRegisterMortalOnCharge(t *testing.T) {
 	}
 }
 
+func TestRegisterDeathExplosion(t *testing.T) {
+	engine := rules.NewEngine()
+	unit := makeSeraphonHero(1, 1) // at (12, 10)
+	RegisterWarscrollAbilityRules(engine, unit, &Warscroll{
+		Abilities: []WarscrollAbility{{Name: "Chotec's Wrath", Effect: "deathExplosion", Value: 2}},
+	})
+	unit.Models[0].IsAlive = false
+
+	near := makeEnemyUnit(10, 2)
+	near.Models[0].Position = core.Position{X: 14, Y: 10}
+	far := makeEnemyUnit(11, 2)
+	friend := makeSeraphonSaurusUnit(2, 1)
+	friend.Models[0].Position = core.Position{X: 13, Y: 10}
+
+	ctx := engine.Evaluate(rules.OnUnitDestroyed, &rules.Context{Defender: unit, AllUnits: []*core.Unit{near, far, friend}})
+	if len(ctx.Effects) != 1 || ctx.Effects[0].UnitID != near.ID || ctx.Effects[0].Amount != 2 {
+		t.Errorf("expected 2 mortal wounds on the nearby enemy only, got %+v", ctx.Effects)
+	}
+}
+
+func TestRegisterFlyoverMortals(t *testing.T) {
+	engine := rules.NewEngine()
+	unit := makeTzeentchDaemonUnit(1, 1)
+	RegisterWarscrollAbilityRules(engine, unit, &Warscroll{
+		Abilities: []WarscrollAbility{{Name: "Slashing Fins", Effect: "flyoverMortals", Value: 1}},
+	})
+
+	crossed := makeEnemyUnit(10, 2)
+	crossed.Models[0].Position = core.Position{X: 20, Y: 10.5}
+	beside := makeEnemyUnit(11, 2)
+	beside.Models[0].Position = core.Position{X: 20, Y: 14}
+
+	ctx := engine.Evaluate(rules.AfterMove, &rules.Context{
+		Attacker:    unit,
+		Origin:      core.Position{X: 10, Y: 10},
+		Destination: core.Position{X: 30, Y: 10},
+		AllUnits:    []*core.Unit{crossed, beside},
+	})
+	if len(ctx.Effects) != 1 || ctx.Effects[0].UnitID != crossed.ID {
+		t.Errorf("expected a mortal wound on the unit passed across only, got %+v", ctx.Effects)
+	}
+}
+
+func TestRegisterSelfHeal(t *testing.T) {
+	engine := rules.NewEngine()
+	unit := makeSeraphonHero(1, 1)
+	RegisterWarscrollAbilityRules(engine, unit, &Warscroll{
+		Abilities: []WarscrollAbility{{Name: "Regeneration", Effect: "selfHeal", Value: 2}},
+	})
+
+	// Each hero phase, including the opponent's.
+	ctx := engine.Evaluate(rules.OnPhaseStart, &rules.Context{Unit: unit, PhaseType: string(phase.PhaseHero), PlayerID: 2})
+	if len(ctx.Effects) != 1 || ctx.Effects[0].Kind != rules.EffectHeal || ctx.Effects[0].Amount != 2 {
+		t.Errorf("expected a heal of 2, got %+v", ctx.Effects)
+	}
+}
+
+func TestRegisterEnhancementRules_FightOnDeathAndCPOnEnemyCast(t *testing.T) {
+	engine := rules.NewEngine()
+	hero := makeSeraphonHero(1, 1)
+	RegisterEnhancementRules(engine, hero, &Enhancement{Name: "Disciplined Fury", Effect: "fightOnDeath"})
+	RegisterEnhancementRules(engine, hero, &Enhancement{Name: "Aetherquartz Brooch", Effect: "cpOnEnemyCast", Value: 1})
+
+	enemy := makeEnemyUnit(10, 2)
+	ctx := engine.Evaluate(rules.OnUnitDestroyed, &rules.Context{Attacker: enemy, Defender: hero})
+	if len(ctx.Effects) != 1 || ctx.Effects[0].Kind != rules.EffectFight || ctx.Effects[0].TargetID != enemy.ID {
+		t.Errorf("expected the hero to fight its killer, got %+v", ctx.Effects)
+	}
+	ctx = engine.Evaluate(rules.OnUnitDestroyed, &rules.Context{Attacker: enemy, Defender: hero, IsShooting: true})
+	if len(ctx.Effects) != 0 {
+		t.Errorf("expected no fight when shot down, got %+v", ctx.Effects)
+	}
+
+	wizard := makeEnemyUnit(11, 2)
+	wizard.Models[0].Position = core.Position{X: 28, Y: 10}
+	ctx = engine.Evaluate(rules.AfterCast, &rules.Context{Attacker: wizard})
+	if len(ctx.Effects) != 1 || ctx.Effects[0].PlayerID != 1 || ctx.Effects[0].RollNeeded != 4 {
+		t.Errorf("expected a 4+ roll for 1 CP, got %+v", ctx.Effects)
+	}
+	wizard.Models[0].Position = core.Position{X: 40, Y: 10}
+	ctx = engine.Evaluate(rules.AfterCast, &rules.Context{Attacker: wizard})
+	if len(ctx.Effects) != 0 {
+		t.Errorf("expected nothing for a wizard more than 18\" away, got %+v", ctx.Effects)
+	}
+}
+
 // --- Helper Functions ---
 
 func TestIsNearFriendlyHero(t *testing.T) {
@@ -829,13 +959,13 @@ func TestIsNearFriendlyHero(t *testing.T) {
 	allUnits := []*core.Unit{unit, hero}
 
 	// They're at (10,10) and (12,10) = 2" apart, well within 12"
-	if !isNearFriendlyHero(unit, allUnits, "seraphon", 12.0) {
+	if !isNearFriendlyHero(unit, &rules.Context{AllUnits: allUnits}, "seraphon", 12.0) {
 		t.Error("Expected unit to be near friendly hero")
 	}
 
 	// Move hero far away
 	hero.Models[0].Position = core.Position{X: 100, Y: 100}
-	if isNearFriendlyHero(unit, allUnits, "seraphon", 12.0) {
+	if isNearFriendlyHero(unit, &rules.Context{AllUnits: allUnits}, "seraphon", 12.0) {
 		t.Error("Expected unit to NOT be near friendly hero after moving far")
 	}
 }
@@ -846,9 +976,19 @@ func TestIsNearFriendlyWizard(t *testing.T) {
 	allUnits := []*core.Unit{unit, wizard}
 
 	// (10,10) and (15,10) = 5" apart, within 12"
-	if !isNearFriendlyWizard(unit, allUnits, 1, 12.0) {
+	if !isNearFriendlyWizard(unit, &rules.Context{AllUnits: allUnits}, 1, 12.0) {
 		t.Error("Expected unit to be near friendly wizard")
 	}
+	// The game's spatial grid is used when the context carries one.
+	grid := core.NewGrid(6)
+	grid.Update(unit)
+	if isNearFriendlyWizard(unit, &rules.Context{AllUnits: allUnits, Grid: grid}, 1, 12.0) {
+		t.Error("Expected only the units in the grid to be considered")
+	}
+	grid.Update(wizard)
+	if !isNearFriendlyWizard(unit, &rules.Context{Grid: grid}, 1, 12.0) {
+		t.Error("Expected the wizard to be found through the grid")
+	}
 }
 
 // --- Roster Enhancement Fields ---
@@ -0,0 +1,121 @@
+package army
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// RosterGenerator makes random legal rosters, for fuzzing and for analyses
+// that need many varied armies. A generator seeded the same way makes the
+// same rosters in the same order.
+type RosterGenerator struct {
+	rng *rand.Rand
+}
+
+// NewRosterGenerator returns a generator seeded with seed.
+func NewRosterGenerator(seed int64) *RosterGenerator {
+	return &RosterGenerator{rng: rand.New(rand.NewPCG(uint64(seed), 0x6a09e667))}
+}
+
+// Generate returns a random legal roster of faction within pointsLimit
+// (DefaultPointsLimit if 0 or less). A random hero that fits is the
+// general, and random units are added, a third of them reinforced where
+// they can be, until no more fit the points, hero, reinforcement and unique
+// limits. The formation is random, the general takes a random heroic trait
+// and a random other hero a random artefact, where they can. Returns an
+// error if no hero of the faction fits the limit.
+func (g *RosterGenerator) Generate(faction *Faction, pointsLimit int) (*ArmyRoster, error) {
+	if pointsLimit <= 0 {
+		pointsLimit = DefaultPointsLimit
+	}
+	r := &ArmyRoster{
+		FactionID:      faction.ID,
+		PointsLimit:    pointsLimit,
+		HeroicTraitIdx: -1,
+		ArtefactIdx:    -1,
+	}
+
+	var generals []*Warscroll
+	for i := range faction.Warscrolls {
+		if ws := &faction.Warscrolls[i]; ws.HasKeyword("Hero") && ws.Points <= pointsLimit {
+			generals = append(generals, ws)
+		}
+	}
+	if len(generals) == 0 {
+		return nil, fmt.Errorf("no %s hero fits in %d points", faction.Name, pointsLimit)
+	}
+	general := generals[g.rng.IntN(len(generals))]
+	r.Entries = append(r.Entries, RosterEntry{WarscrollID: general.ID, IsGeneral: true})
+
+	points, heroes, reinforced := general.Points, 1, 0
+	used := map[string]bool{general.ID: true}
+	for {
+		var fits []*Warscroll
+		for i := range faction.Warscrolls {
+			ws := &faction.Warscrolls[i]
+			hero := ws.HasKeyword("Hero")
+			if points+ws.Points > pointsLimit || (hero && heroes >= MaxHeroes) || (ws.Unique && used[ws.ID]) {
+				continue
+			}
+			fits = append(fits, ws)
+		}
+		if len(fits) == 0 {
+			break
+		}
+		ws := fits[g.rng.IntN(len(fits))]
+		entry := RosterEntry{WarscrollID: ws.ID}
+		if ws.MaxSize > 0 && reinforced < MaxReinforcements && points+2*ws.Points <= pointsLimit && g.rng.IntN(3) == 0 {
+			entry.Reinforced = true
+			reinforced++
+			points += ws.Points
+		}
+		r.Entries = append(r.Entries, entry)
+		points += ws.Points
+		used[ws.ID] = true
+		if ws.HasKeyword("Hero") {
+			heroes++
+		}
+	}
+
+	if n := len(faction.Formations); n > 0 {
+		r.FormationIndex = g.rng.IntN(n)
+	}
+	g.pickEnhancements(r, faction, general)
+
+	if errs := r.Validate(faction); len(errs) > 0 {
+		return nil, fmt.Errorf("generated an illegal roster: %v", errs[0])
+	}
+	return r, nil
+}
+
+// pickEnhancements gives the general a random heroic trait and a random
+// other hero a random artefact. Unique heroes take neither.
+func (g *RosterGenerator) pickEnhancements(r *ArmyRoster, faction *Faction, general *Warscroll) {
+	if traits := faction.AvailableHeroicTraits(); len(traits) > 0 && !general.Unique {
+		r.HeroicTraitIdx = g.rng.IntN(len(traits))
+	}
+
+	artefacts := faction.AvailableArtefacts()
+	if len(artefacts) == 0 {
+		return
+	}
+	// A bearer is named by warscroll ID, which picks the first entry of
+	// that warscroll, so only first entries can carry the artefact.
+	var bearers []int
+	first := make(map[string]bool)
+	for i, e := range r.Entries {
+		if first[e.WarscrollID] {
+			continue
+		}
+		first[e.WarscrollID] = true
+		ws := faction.GetWarscroll(e.WarscrollID)
+		if ws.HasKeyword("Hero") && !ws.Unique && !(e.IsGeneral && r.HeroicTraitIdx >= 0) {
+			bearers = append(bearers, i)
+		}
+	}
+	if len(bearers) == 0 {
+		return
+	}
+	r.ArtefactIdx = g.rng.IntN(len(artefacts))
+	r.ArtefactUnitID = r.Entries[bearers[g.rng.IntN(len(bearers))]].WarscrollID
+}
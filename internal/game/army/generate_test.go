@@ -0,0 +1,101 @@
+package army
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func generatorTestFactions(t *testing.T) *FactionRegistry {
+	t.Helper()
+	registry := NewRegistry()
+	if err := registry.LoadAllFactions(filepath.Join("..", "..", "..", "data", "factions")); err != nil {
+		t.Fatalf("loading factions: %v", err)
+	}
+	return registry
+}
+
+func TestRosterGenerator_MakesLegalRosters(t *testing.T) {
+	for _, f := range generatorTestFactions(t).AllFactions() {
+		gen := NewRosterGenerator(1)
+		var traits, artefacts, reinforced int
+		for _, limit := range []int{500, 1000, 2000, 3000} {
+			for i := 0; i < 50; i++ {
+				r, err := gen.Generate(f, limit)
+				if err != nil {
+					t.Fatalf("%s at %d: unexpected error: %v", f.ID, limit, err)
+				}
+				if errs := r.Validate(f); len(errs) > 0 {
+					t.Fatalf("%s at %d: illegal roster %+v: %v", f.ID, limit, r, errs)
+				}
+				if r.FactionID != f.ID || r.PointsLimit != limit {
+					t.Errorf("%s: roster for %s at %d points", f.ID, r.FactionID, r.PointsLimit)
+				}
+				if r.HeroicTraitIdx >= 0 {
+					traits++
+				}
+				if r.ArtefactIdx >= 0 {
+					artefacts++
+				}
+				for _, e := range r.Entries {
+					if e.Reinforced {
+						reinforced++
+					}
+				}
+			}
+		}
+		if traits == 0 || artefacts == 0 || reinforced == 0 {
+			t.Errorf("%s: expected some traits, artefacts and reinforced units, got %d, %d and %d",
+				f.ID, traits, artefacts, reinforced)
+		}
+	}
+}
+
+func TestRosterGenerator_Seeded(t *testing.T) {
+	f := generatorTestFactions(t).GetFaction("seraphon")
+	a, b := NewRosterGenerator(7), NewRosterGenerator(7)
+	for i := 0; i < 5; i++ {
+		ra, errA := a.Generate(f, 2000)
+		rb, errB := b.Generate(f, 2000)
+		if errA != nil || errB != nil {
+			t.Fatalf("unexpected errors: %v, %v", errA, errB)
+		}
+		if !reflect.DeepEqual(ra, rb) {
+			t.Fatalf("roster %d differs for the same seed:\n%+v\n%+v", i, ra, rb)
+		}
+	}
+}
+
+func TestRosterGenerator_NothingFits(t *testing.T) {
+	f := generatorTestFactions(t).GetFaction("seraphon")
+	if _, err := NewRosterGenerator(1).Generate(f, 50); err == nil {
+		t.Error("expected error when no hero fits the points limit")
+	}
+}
+
+func TestRosterGenerator_ScourgeEnhancementsWithoutFactionLists(t *testing.T) {
+	f := &Faction{
+		ID: "test",
+		Warscrolls: []Warscroll{
+			{ID: "boss", Name: "Boss", Points: 100, UnitSize: 1, Keywords: []string{"Hero", "Infantry"}},
+			{ID: "captain", Name: "Captain", Points: 80, UnitSize: 1, Keywords: []string{"Hero", "Infantry"}},
+		},
+	}
+	gen := NewRosterGenerator(1)
+	var traits, artefacts int
+	for i := 0; i < 50; i++ {
+		r, err := gen.Generate(f, 1000)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r.HeroicTraitIdx >= 0 {
+			traits++
+		}
+		if r.ArtefactIdx >= 0 {
+			artefacts++
+		}
+	}
+	if traits == 0 || artefacts == 0 {
+		t.Errorf("expected Scourge of Ghyran traits and artefacts, got %d and %d", traits, artefacts)
+	}
+}
@@ -0,0 +1,285 @@
+package army
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The plain-text list format is the one produced by the official app's text
+// export: a header naming the faction and battle formation, then one line per
+// unit with its points in brackets, each followed by bullet lines for the
+// general, reinforcement and enhancements:
+//
+//	Sunclaw Host 1970/2000 pts
+//
+//	Seraphon
+//	Sunclaw Temple-host
+//
+//	General's Regiment
+//	Saurus Oldblood on Carnosaur (270)
+//	• General
+//	• Heroic Trait: Vengeful Defender
+//	Saurus Warriors (420)
+//	• Reinforced
+//
+// Regiment headings, lores, drop counts and the app footer are ignored.
+
+var (
+	listPointsLine = regexp.MustCompile(`(?i)(\d+)\s*/\s*(\d+)\s*pts?\b`)
+	listUnitLine   = regexp.MustCompile(`^(.+?)\s*\((\d+)\s*(?:pts?)?\)$`)
+	listBullet     = regexp.MustCompile(`^[•·*\-–]\s*`)
+)
+
+// listIgnoredPrefixes are lowercase prefixes of header lines that carry no
+// roster information.
+var listIgnoredPrefixes = []string{
+	"general's regiment", "regiment", "auxiliary", "regiments of renown",
+	"drops", "wounds", "spell lore", "prayer lore", "manifestation lore",
+	"battle tactic", "faction terrain", "created with", "app:", "data:", "version",
+}
+
+// ListLineError reports a line of a text army list that could not be matched
+// against the faction's data.
+type ListLineError struct {
+	Line int    // 1-based line number
+	Text string // The line as written
+	Msg  string
+}
+
+func (e *ListLineError) Error() string {
+	return fmt.Sprintf("line %d: %q: %s", e.Line, e.Text, e.Msg)
+}
+
+// ParseListText reads an army list in the app's text format and builds a
+// roster for the faction it names. Unit names are matched with
+// Faction.GetWarscrollByName. Lines that cannot be matched are returned as
+// *ListLineError values joined into the error; the roster built from the
+// remaining lines is returned alongside them. The faction is nil only if no
+// line names a faction in the registry.
+func ParseListText(r io.Reader, registry *FactionRegistry) (*ArmyRoster, *Faction, error) {
+	roster := &ArmyRoster{PointsLimit: DefaultPointsLimit, HeroicTraitIdx: -1, ArtefactIdx: -1}
+	var faction *Faction
+	var errs []error
+	current := -1 // Entry the bullet lines below refer to
+	traitEntry, traitLine, traitText := -1, 0, ""
+
+	fail := func(line int, text, format string, args ...any) {
+		errs = append(errs, &ListLineError{Line: line, Text: text, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimSpace(scanner.Text())
+		if strings.Trim(text, "-=_ ") == "" {
+			continue
+		}
+
+		if m := listPointsLine.FindStringSubmatch(text); m != nil {
+			roster.PointsLimit, _ = strconv.Atoi(m[2])
+			continue
+		}
+
+		if listBullet.MatchString(text) {
+			detail := listBullet.ReplaceAllString(text, "")
+			if current < 0 {
+				fail(n, text, "no unit above this line")
+				continue
+			}
+			before := traitEntry
+			if msg := applyListDetail(roster, faction, current, detail, &traitEntry); msg != "" {
+				fail(n, text, "%s", msg)
+			} else if traitEntry != before {
+				traitLine, traitText = n, text
+			}
+			continue
+		}
+
+		if m := listUnitLine.FindStringSubmatch(text); m != nil {
+			if faction == nil {
+				fail(n, text, "unit listed before the faction")
+				current = -1
+				continue
+			}
+			ws := faction.GetWarscrollByName(m[1])
+			if ws == nil {
+				fail(n, text, "no warscroll named '%s' in %s", m[1], faction.Name)
+				current = -1
+				continue
+			}
+			roster.Entries = append(roster.Entries, RosterEntry{WarscrollID: ws.ID})
+			current = len(roster.Entries) - 1
+			continue
+		}
+
+		if isIgnoredListLine(text) {
+			continue
+		}
+		name := stripListLabel(text, "faction", "army")
+		if faction == nil {
+			if f := findFactionByName(registry, name); f != nil {
+				faction = f
+				roster.FactionID = f.ID
+				continue
+			}
+		}
+		if faction != nil {
+			name = stripListLabel(text, "battle formation", "formation")
+			if idx := findFormation(faction, name); idx >= 0 {
+				roster.FormationIndex = idx
+				continue
+			}
+		}
+		// The first line of an export is usually the list's own name.
+		if n == 1 {
+			continue
+		}
+		fail(n, text, "not a faction, formation or unit")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("reading army list: %w", err)
+	}
+	if traitEntry >= 0 && !roster.Entries[traitEntry].IsGeneral {
+		fail(traitLine, traitText, "heroic traits can only be taken by the general")
+	}
+	if faction == nil {
+		errs = append(errs, fmt.Errorf("army list does not name a known faction"))
+	}
+	return roster, faction, errors.Join(errs...)
+}
+
+// applyListDetail applies a bullet line to roster entry idx, recording in
+// traitEntry which entry took a heroic trait. Returns a message if the line
+// is not understood.
+func applyListDetail(roster *ArmyRoster, faction *Faction, idx int, detail string, traitEntry *int) string {
+	entry := &roster.Entries[idx]
+	switch strings.ToLower(detail) {
+	case "general":
+		entry.IsGeneral = true
+		return ""
+	case "reinforced":
+		entry.Reinforced = true
+		return ""
+	}
+
+	name := stripListLabel(detail, "heroic trait", "trait")
+	if i := findEnhancement(faction.AvailableHeroicTraits(), name); i >= 0 {
+		roster.HeroicTraitIdx = i
+		*traitEntry = idx
+		return ""
+	}
+
+	name = stripListLabel(detail, "artefact of power", "artefact", "artifact")
+	if i := findEnhancement(faction.AvailableArtefacts(), name); i >= 0 {
+		roster.ArtefactIdx = i
+		roster.ArtefactUnitID = entry.WarscrollID
+		return ""
+	}
+	return fmt.Sprintf("no heroic trait or artefact named '%s' in %s", name, faction.Name)
+}
+
+// isIgnoredListLine reports whether a header line carries no roster data.
+func isIgnoredListLine(text string) bool {
+	lower := strings.ToLower(text)
+	for _, prefix := range listIgnoredPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripListLabel removes a leading "Label:" or "Label -" from text.
+func stripListLabel(text string, labels ...string) string {
+	lower := strings.ToLower(text)
+	for _, label := range labels {
+		if !strings.HasPrefix(lower, label) {
+			continue
+		}
+		rest := strings.TrimSpace(text[len(label):])
+		if strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "-") {
+			return strings.TrimSpace(rest[1:])
+		}
+	}
+	return text
+}
+
+func findFactionByName(registry *FactionRegistry, name string) *Faction {
+	key := normalizeName(name)
+	for _, f := range registry.AllFactions() {
+		if normalizeName(f.Name) == key || normalizeName(f.ID) == key {
+			return f
+		}
+	}
+	return nil
+}
+
+func findFormation(faction *Faction, name string) int {
+	key := normalizeName(name)
+	for i, bf := range faction.Formations {
+		if normalizeName(bf.Name) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func findEnhancement(list []Enhancement, name string) int {
+	key := normalizeName(name)
+	for i, e := range list {
+		if normalizeName(e.Name) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// FormatListText writes the roster in the app's text format, which
+// ParseListText reads back.
+func FormatListText(w io.Writer, roster *ArmyRoster, faction *Faction) error {
+	limit := roster.PointsLimit
+	if limit <= 0 {
+		limit = DefaultPointsLimit
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %d/%d pts\n\n", faction.Name, roster.TotalPoints(faction), limit)
+	fmt.Fprintf(&b, "%s\n", faction.Name)
+	if roster.FormationIndex >= 0 && roster.FormationIndex < len(faction.Formations) {
+		fmt.Fprintf(&b, "%s\n", faction.Formations[roster.FormationIndex].Name)
+	}
+	b.WriteString("\n")
+
+	traits, artefacts := faction.AvailableHeroicTraits(), faction.AvailableArtefacts()
+	bearer := roster.ArtefactBearer()
+	for i, entry := range roster.Entries {
+		ws := faction.GetWarscroll(entry.WarscrollID)
+		if ws == nil {
+			return fmt.Errorf("entry %d: unknown warscroll '%s'", i, entry.WarscrollID)
+		}
+		points := ws.Points
+		if entry.Reinforced {
+			points *= 2
+		}
+		fmt.Fprintf(&b, "%s (%d)\n", ws.Name, points)
+		if entry.IsGeneral {
+			b.WriteString("• General\n")
+		}
+		if entry.Reinforced {
+			b.WriteString("• Reinforced\n")
+		}
+		if entry.IsGeneral && roster.HeroicTraitIdx >= 0 && roster.HeroicTraitIdx < len(traits) {
+			fmt.Fprintf(&b, "• Heroic Trait: %s\n", traits[roster.HeroicTraitIdx].Name)
+		}
+		if i == bearer && roster.ArtefactIdx >= 0 && roster.ArtefactIdx < len(artefacts) {
+			fmt.Fprintf(&b, "• Artefact: %s\n", artefacts[roster.ArtefactIdx].Name)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,133 @@
+package army
+
+import (
+	"strings"
+	"testing"
+)
+
+func listTestRegistry() *FactionRegistry {
+	f := makeTestFaction()
+	f.Formations = []BattleFormation{{Name: "Vanguard"}, {Name: "Shield Wall"}}
+	f.HeroicTraits = []Enhancement{{Name: "Brave", Effect: "ward", Value: 6}}
+	f.Artefacts = []Enhancement{{Name: "Trinket", Effect: "ward", Value: 6}}
+	r := NewRegistry()
+	r.factions[f.ID] = f
+	return r
+}
+
+const listTestText = `My List 1100/1500 pts
+
+Test
+Shield Wall
+
+General's Regiment
+Hero A (150)
+• General
+• Heroic Trait: Brave
+Infantry (200)
+• Reinforced
+Regiment 1
+hero a (150)
+• Artefact: Trinket
+Cavalry (180)
+
+Created with Warhammer Age of Sigmar: The App
+`
+
+func TestParseListText(t *testing.T) {
+	roster, faction, err := ParseListText(strings.NewReader(listTestText), listTestRegistry())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if faction == nil || roster.FactionID != "test" {
+		t.Fatalf("expected faction 'test', got %q", roster.FactionID)
+	}
+	if roster.PointsLimit != 1500 {
+		t.Errorf("expected points limit 1500, got %d", roster.PointsLimit)
+	}
+	if roster.FormationIndex != 1 {
+		t.Errorf("expected formation 1, got %d", roster.FormationIndex)
+	}
+	if len(roster.Entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(roster.Entries))
+	}
+	if !roster.Entries[0].IsGeneral || !roster.Entries[1].Reinforced {
+		t.Errorf("expected general and reinforced flags, got %+v", roster.Entries)
+	}
+	if roster.HeroicTraitIdx != 0 || roster.ArtefactIdx != 0 || roster.ArtefactUnitID != "hero_a" {
+		t.Errorf("unexpected enhancements: trait %d, artefact %d on %q",
+			roster.HeroicTraitIdx, roster.ArtefactIdx, roster.ArtefactUnitID)
+	}
+}
+
+func TestParseListText_ReportsUnmatchedLines(t *testing.T) {
+	text := strings.Replace(listTestText, "Cavalry (180)", "Dragon Riders (180)", 1)
+	text = strings.Replace(text, "• Artefact: Trinket", "• Artefact: Shiny Rock", 1)
+	roster, _, err := ParseListText(strings.NewReader(text), listTestRegistry())
+	if err == nil {
+		t.Fatal("expected errors for unmatched lines")
+	}
+	msg := err.Error()
+	for _, want := range []string{
+		`line 14: "• Artefact: Shiny Rock": no heroic trait or artefact named 'Shiny Rock' in Test`,
+		`line 15: "Dragon Riders (180)": no warscroll named 'Dragon Riders' in Test`,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in:\n%s", want, msg)
+		}
+	}
+	if len(roster.Entries) != 3 {
+		t.Errorf("expected the matched units to be kept, got %d entries", len(roster.Entries))
+	}
+}
+
+func TestParseListText_TraitNotOnGeneral(t *testing.T) {
+	text := strings.Replace(listTestText, "• General\n• Heroic Trait: Brave\n", "• Heroic Trait: Brave\n", 1)
+	_, _, err := ParseListText(strings.NewReader(text), listTestRegistry())
+	if err == nil || !strings.Contains(err.Error(), "heroic traits can only be taken by the general") {
+		t.Errorf("expected heroic trait error, got %v", err)
+	}
+}
+
+func TestFormatListText_RoundTrip(t *testing.T) {
+	registry := listTestRegistry()
+	faction := registry.GetFaction("test")
+	roster := &ArmyRoster{
+		FactionID:      "test",
+		PointsLimit:    2000,
+		FormationIndex: 1,
+		HeroicTraitIdx: 0,
+		ArtefactIdx:    1, // First Scourge of Ghyran artefact
+		ArtefactUnitID: "hero_a",
+		Entries: []RosterEntry{
+			{WarscrollID: "hero_a"},
+			{WarscrollID: "infantry", Reinforced: true},
+			{WarscrollID: "hero_b", IsGeneral: true},
+		},
+	}
+
+	var b strings.Builder
+	if err := FormatListText(&b, roster, faction); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(b.String(), "Test 550/2000 pts\n") {
+		t.Errorf("unexpected header:\n%s", b.String())
+	}
+
+	got, _, err := ParseListText(strings.NewReader(b.String()), registry)
+	if err != nil {
+		t.Fatalf("re-importing exported list: %v\n%s", err, b.String())
+	}
+	if got.FormationIndex != roster.FormationIndex || got.HeroicTraitIdx != roster.HeroicTraitIdx ||
+		got.ArtefactIdx != roster.ArtefactIdx || got.ArtefactUnitID != roster.ArtefactUnitID {
+		t.Errorf("selections changed in round trip: %+v", got)
+	}
+	if len(got.Entries) != len(roster.Entries) {
+		t.Fatalf("expected %d entries, got %d", len(roster.Entries), len(got.Entries))
+	}
+	for i := range roster.Entries {
+		if got.Entries[i] != roster.Entries[i] {
+			t.Errorf("entry %d: expected %+v, got %+v", i, roster.Entries[i], got.Entries[i])
+		}
+	}
+}
@@ -19,16 +19,18 @@ func NewRegistry() *FactionRegistry {
 	}
 }
 
-// LoadFaction loads a faction from a JSON file and registers it.
+// LoadFaction loads a faction from a JSON file and registers it. Decoding is
+// strict: unknown fields, type mismatches and out-of-range characteristics
+// are rejected with a *LoadError (or several, joined) giving the line.
 func (r *FactionRegistry) LoadFaction(path string) (*Faction, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading faction file %s: %w", path, err)
 	}
 
-	var faction Faction
-	if err := json.Unmarshal(data, &faction); err != nil {
-		return nil, fmt.Errorf("parsing faction file %s: %w", path, err)
+	faction, err := decodeFaction(path, data)
+	if err != nil {
+		return nil, err
 	}
 
 	// Set faction ID on each warscroll
@@ -38,8 +40,8 @@ func (r *FactionRegistry) LoadFaction(path string) (*Faction, error) {
 		}
 	}
 
-	r.factions[faction.ID] = &faction
-	return &faction, nil
+	r.factions[faction.ID] = faction
+	return faction, nil
 }
 
 // LoadAllFactions loads all JSON files from a directory.
@@ -100,8 +102,8 @@ func LoadRoster(path string) (*ArmyRoster, error) {
 
 // ParseFactionJSON parses faction data from raw JSON bytes (useful for embedding).
 func ParseFactionJSON(data []byte) (*Faction, error) {
-	var faction Faction
-	if err := json.Unmarshal(data, &faction); err != nil {
+	faction, err := decodeFaction("", data)
+	if err != nil {
 		return nil, fmt.Errorf("parsing faction JSON: %w", err)
 	}
 	for i := range faction.Warscrolls {
@@ -109,5 +111,5 @@ func ParseFactionJSON(data []byte) (*Faction, error) {
 			faction.Warscrolls[i].Faction = faction.ID
 		}
 	}
-	return &faction, nil
+	return faction, nil
 }
@@ -0,0 +1,95 @@
+package army
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// WithWarscrollField returns a copy of the faction with one integer field of
+// a warscroll set to value, to try out a change before it is published. The
+// field is a JSON path within the warscroll, such as `points`, `stats.save`
+// or `weapons[0].rend`. Values the engine cannot use are rejected as when
+// loading a faction. The faction itself is not changed.
+func (f *Faction) WithWarscrollField(warscrollID, field string, value int) (*Faction, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	var c Faction
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	idx := -1
+	for i := range c.Warscrolls {
+		if c.Warscrolls[i].ID == warscrollID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("faction '%s' has no warscroll '%s'", f.ID, warscrollID)
+	}
+
+	v := reflect.ValueOf(&c.Warscrolls[idx]).Elem()
+	for _, seg := range strings.Split(field, ".") {
+		name, elem := seg, -1
+		if i := strings.IndexByte(seg, '['); i >= 0 && strings.HasSuffix(seg, "]") {
+			name = seg[:i]
+			if elem, err = strconv.Atoi(seg[i+1 : len(seg)-1]); err != nil || elem < 0 {
+				return nil, fmt.Errorf("field %s: bad index in '%s'", field, seg)
+			}
+		}
+		fv, ok := jsonField(v, name)
+		if !ok {
+			return nil, fmt.Errorf("field %s: warscroll has no '%s'", field, name)
+		}
+		v = fv
+		if elem >= 0 {
+			if v.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("field %s: '%s' is not a list", field, name)
+			}
+			if elem >= v.Len() {
+				return nil, fmt.Errorf("field %s: '%s' has only %d entries", field, name, v.Len())
+			}
+			v = v.Index(elem)
+		}
+	}
+	if v.Kind() != reflect.Int {
+		return nil, fmt.Errorf("field %s is not an integer", field)
+	}
+	v.SetInt(int64(value))
+
+	before := make(map[rangeProblem]bool)
+	for _, p := range checkFactionRanges(f) {
+		before[p] = true
+	}
+	for _, p := range checkFactionRanges(&c) {
+		if !before[p] {
+			return nil, fmt.Errorf("%s: %s", describePath(&c, parentPath(p.path)), p.msg)
+		}
+	}
+	return &c, nil
+}
+
+// jsonField returns the field of struct v with the given JSON name.
+func jsonField(v reflect.Value, name string) (reflect.Value, bool) {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == name || (tag == "" && f.Name == name) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,63 @@
+package army
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithWarscrollField(t *testing.T) {
+	f, err := decodeFaction("mini.json", []byte(decodeTestFaction))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tc := range []struct {
+		field string
+		value int
+		get   func(ws *Warscroll) int
+	}{
+		{"stats.save", 3, func(ws *Warscroll) int { return ws.Stats.Save }},
+		{"weapons[1].rend", 2, func(ws *Warscroll) int { return ws.Weapons[1].Rend }},
+		{"weapons[0].hit", 2, func(ws *Warscroll) int { return ws.Weapons[0].ToHit }},
+		{"points", 130, func(ws *Warscroll) int { return ws.Points }},
+	} {
+		c, err := f.WithWarscrollField("mini_unit", tc.field, tc.value)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.field, err)
+			continue
+		}
+		if got := tc.get(c.GetWarscroll("mini_unit")); got != tc.value {
+			t.Errorf("%s: expected %d, got %d", tc.field, tc.value, got)
+		}
+	}
+
+	if ws := f.GetWarscroll("mini_unit"); ws.Stats.Save != 4 || ws.Weapons[1].Rend != 0 || ws.Points != 0 {
+		t.Errorf("expected the original faction unchanged, got %+v", ws)
+	}
+}
+
+func TestWithWarscrollField_Errors(t *testing.T) {
+	f, err := decodeFaction("mini.json", []byte(decodeTestFaction))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tc := range []struct {
+		warscroll, field string
+		value            int
+		want             string
+	}{
+		{"nobody", "points", 1, "no warscroll"},
+		{"mini_unit", "stats.armour", 1, "no 'armour'"},
+		{"mini_unit", "weapons[2].rend", 1, "only 2 entries"},
+		{"mini_unit", "stats[0]", 1, "not a list"},
+		{"mini_unit", "name", 1, "not an integer"},
+		{"mini_unit", "stats.save", 7, `warscroll "mini_unit": stats: save must be 2..6, got 7`},
+		{"mini_unit", "weapons[0].attacks", 0, "attacks must be at least 1"},
+	} {
+		_, err := f.WithWarscrollField(tc.warscroll, tc.field, tc.value)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s %s = %d: expected error containing %q, got %v", tc.warscroll, tc.field, tc.value, tc.want, err)
+		}
+	}
+}
@@ -111,9 +111,95 @@ func (r *ArmyRoster) Validate(faction *Faction) []error {
 		errs = append(errs, fmt.Errorf("only 1 general allowed, got %d", generalCount))
 	}
 
+	// Faction and enhancement selections
+	if r.FactionID != "" && r.FactionID != faction.ID {
+		errs = append(errs, fmt.Errorf("roster is for faction '%s', not '%s'", r.FactionID, faction.ID))
+	}
+	if len(faction.Formations) > 0 && (r.FormationIndex < 0 || r.FormationIndex >= len(faction.Formations)) {
+		errs = append(errs, fmt.Errorf("formation index %d out of range (0-%d)", r.FormationIndex, len(faction.Formations)-1))
+	}
+	errs = append(errs, r.validateEnhancements(faction)...)
+
+	return errs
+}
+
+// validateEnhancements checks the heroic trait and artefact selections:
+// indices in range, bearers are non-unique heroes in the roster, no hero
+// carries more than one enhancement and no enhancement is taken twice.
+func (r *ArmyRoster) validateEnhancements(faction *Faction) []error {
+	var errs []error
+	traits := faction.AvailableHeroicTraits()
+	artefacts := faction.AvailableArtefacts()
+	carried := make(map[int]string) // entry index -> enhancement name
+
+	assign := func(entry int, enh *Enhancement) {
+		ws := faction.GetWarscroll(r.Entries[entry].WarscrollID)
+		if ws == nil {
+			return
+		}
+		switch {
+		case !ws.HasKeyword("Hero"):
+			errs = append(errs, fmt.Errorf("'%s' cannot take '%s': only Heroes can take enhancements", ws.Name, enh.Name))
+		case ws.Unique:
+			errs = append(errs, fmt.Errorf("'%s' cannot take '%s': unique units cannot take enhancements", ws.Name, enh.Name))
+		}
+		if prev, ok := carried[entry]; ok {
+			if prev == enh.Name {
+				errs = append(errs, fmt.Errorf("enhancement '%s' selected more than once", enh.Name))
+			} else {
+				errs = append(errs, fmt.Errorf("'%s' already has '%s'; a hero can have only one enhancement", ws.Name, prev))
+			}
+			return
+		}
+		carried[entry] = enh.Name
+	}
+
+	if r.HeroicTraitIdx >= 0 && len(traits) > 0 {
+		if r.HeroicTraitIdx >= len(traits) {
+			errs = append(errs, fmt.Errorf("heroic trait index %d out of range (max %d)", r.HeroicTraitIdx, len(traits)-1))
+		} else if general := r.generalEntry(); general >= 0 {
+			assign(general, &traits[r.HeroicTraitIdx])
+		}
+	}
+
+	if r.ArtefactIdx >= 0 && len(artefacts) > 0 {
+		if r.ArtefactIdx >= len(artefacts) {
+			errs = append(errs, fmt.Errorf("artefact index %d out of range (max %d)", r.ArtefactIdx, len(artefacts)-1))
+		} else if bearer := r.ArtefactBearer(); bearer >= 0 {
+			assign(bearer, &artefacts[r.ArtefactIdx])
+		} else if r.ArtefactUnitID != "" {
+			errs = append(errs, fmt.Errorf("artefact bearer '%s' is not in the roster", r.ArtefactUnitID))
+		}
+	}
+
 	return errs
 }
 
+// generalEntry returns the index of the general's entry, or -1.
+func (r *ArmyRoster) generalEntry() int {
+	for i, e := range r.Entries {
+		if e.IsGeneral {
+			return i
+		}
+	}
+	return -1
+}
+
+// ArtefactBearer returns the index of the entry carrying the artefact: the
+// first entry for ArtefactUnitID, or the general if no bearer is named.
+// Returns -1 if there is no such entry.
+func (r *ArmyRoster) ArtefactBearer() int {
+	if r.ArtefactUnitID == "" {
+		return r.generalEntry()
+	}
+	for i, e := range r.Entries {
+		if e.WarscrollID == r.ArtefactUnitID {
+			return i
+		}
+	}
+	return -1
+}
+
 // TotalPoints calculates the total points cost of the roster.
 func (r *ArmyRoster) TotalPoints(faction *Faction) int {
 	total := 0
@@ -174,6 +260,14 @@ type UnitSpec struct {
 	Reinforced bool
 }
 
+// Points returns the unit's points cost: double the warscroll's if reinforced.
+func (s *UnitSpec) Points() int {
+	if s.Reinforced {
+		return 2 * s.Warscroll.Points
+	}
+	return s.Warscroll.Points
+}
+
 // CreateUnit creates a core.Unit from this spec using the game's CreateUnit interface.
 // The wardSave and special abilities from the warscroll are applied after creation.
 func (s *UnitSpec) ToUnitParams() (name string, ownerID int, stats core.Stats, weapons []core.Weapon, numModels int, position core.Position, baseSize float64) {
@@ -192,6 +286,8 @@ func (s *UnitSpec) ApplyToUnit(u *core.Unit) {
 	u.Spells = ws.ToCoreSpells()
 	u.Prayers = ws.ToCorePrayers()
 	u.IsGeneral = s.IsGeneral
+	u.WarscrollID = ws.ID
+	u.Points = s.Points()
 
 	// Apply ability effects
 	for _, ab := range ws.Abilities {
@@ -207,33 +303,3 @@ func (s *UnitSpec) ApplyToUnit(u *core.Unit) {
 		}
 	}
 }
-
-// ApplyEnhancement applies a selected enhancement effect to a hero unit.
-func ApplyEnhancement(u *core.Unit, enh *Enhancement) {
-	switch enh.Effect {
-	case "ward":
-		if enh.Value > 0 && (u.WardSave == 0 || enh.Value < u.WardSave) {
-			u.WardSave = enh.Value
-		}
-	case "extraAttacks":
-		for i := range u.Weapons {
-			if u.Weapons[i].IsMelee() {
-				u.Weapons[i].Attacks += enh.Value
-			}
-		}
-	case "extraRend":
-		for i := range u.Weapons {
-			if u.Weapons[i].IsMelee() {
-				u.Weapons[i].Rend += enh.Value
-			}
-		}
-	case "extraDamage":
-		for i := range u.Weapons {
-			if u.Weapons[i].IsMelee() {
-				u.Weapons[i].Damage += enh.Value
-			}
-		}
-	case "extraCast":
-		u.PowerLevel += enh.Value
-	}
-}
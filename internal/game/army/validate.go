@@ -0,0 +1,277 @@
+package army
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+)
+
+// IssueKind classifies a faction data problem.
+type IssueKind string
+
+const (
+	// IssueUnimplemented marks data the engine parses but silently ignores
+	// (an effect key with no handler, a keyword with no core mapping).
+	IssueUnimplemented IssueKind = "unimplemented"
+	// IssueInvalid marks data that is wrong regardless of engine support
+	// (unknown weapon ability strings, bad casting values, duplicate IDs).
+	IssueInvalid IssueKind = "invalid"
+)
+
+// DataIssue is a single problem found while validating faction data.
+type DataIssue struct {
+	File      string    // Source file, if known
+	Line      int       // Line in File, if known
+	FactionID string    // Faction the issue belongs to
+	Where     string    // Location inside the faction (e.g. `warscroll "kroxigor": weapon "Maul"`)
+	Kind      IssueKind // Unimplemented or invalid
+	Message   string    // Human-readable description
+}
+
+func (d DataIssue) String() string {
+	prefix := d.FactionID
+	if d.File != "" {
+		prefix = filepath.Base(d.File)
+		if d.Line > 0 {
+			prefix = fmt.Sprintf("%s:%d", prefix, d.Line)
+		}
+	}
+	if d.Where == "" {
+		return fmt.Sprintf("%s: [%s] %s", prefix, d.Kind, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: [%s] %s", prefix, d.Where, d.Kind, d.Message)
+}
+
+// implementedAbilityEffects are the warscroll ability effect keys handled by
+// UnitSpec.ApplyToUnit or RegisterWarscrollAbilityRules. Neither reports
+// what it ignores, so TestImplementedAbilityEffects_MatchCompilers keeps
+// this in sync with their switches. Battle traits, formations and
+// enhancements are instead checked against what their compilers report.
+var implementedAbilityEffects = map[string]bool{
+	"ward": true, "strikeFirst": true, "strikeLast": true,
+	"bonusChargeAttacks": true, "rerollCharges": true, "mortalOnCharge": true,
+	"fly": true, "shootInCombat": true, "healOnKill": true, "minusOneToBeHit": true,
+	"deathExplosion": true, "flyoverMortals": true, "selfHeal": true,
+}
+
+// Casting values are rolled on 2D6, so anything outside 2..12 can never
+// (or always) succeed.
+const (
+	minCastingValue = 2
+	maxCastingValue = 12
+)
+
+// ValidateFaction checks a single faction for unimplemented or invalid data.
+func ValidateFaction(f *Faction) []DataIssue {
+	v := &validator{faction: f}
+
+	if f.ID == "" {
+		v.invalid("", "faction has no id")
+	}
+
+	tags := make(map[string]bool)
+	seenIDs := make(map[string]bool)
+	for _, ws := range f.Warscrolls {
+		for _, t := range ws.Tags {
+			tags[t] = true
+		}
+		where := fmt.Sprintf("warscroll %q", ws.ID)
+		if ws.ID == "" {
+			v.invalid(fmt.Sprintf("warscroll %q", ws.Name), "warscroll has no id")
+		} else if seenIDs[ws.ID] {
+			v.invalid(where, "duplicate warscroll id")
+		}
+		seenIDs[ws.ID] = true
+		v.validateWarscroll(where, &ws)
+	}
+
+	// The trait and formation registrars report which effect keys they
+	// implement; any other effect is ignored by the engine.
+	traitEffects := implementedSet(registerFactionRules(rules.NewEngine(), f, 1))
+	for _, t := range f.BattleTraits {
+		if !traitEffects[t.Effect] {
+			v.unimplemented(fmt.Sprintf("battle trait %q", t.Name), fmt.Sprintf("effect %q has no handler", t.Effect))
+		}
+	}
+
+	for i, fm := range f.Formations {
+		where := fmt.Sprintf("formation %q", fm.Name)
+		formationEffects := implementedSet(registerFormationRules(rules.NewEngine(), f, i, 1))
+		for _, e := range fm.Effects {
+			if !formationEffects[e.Effect] {
+				v.unimplemented(where, fmt.Sprintf("effect %q has no handler", e.Effect))
+			}
+			if e.TargetTag != "" && !tags[e.TargetTag] {
+				v.invalid(where, fmt.Sprintf("target tag %q is not used by any warscroll", e.TargetTag))
+			}
+		}
+	}
+
+	v.validateEnhancements(f.HeroicTraits, f.Artefacts)
+
+	for _, sp := range f.SpellLore {
+		v.validateSpell(fmt.Sprintf("spell lore %q", sp.Name), &sp)
+	}
+	for _, pr := range f.PrayerLore {
+		v.validatePrayer(fmt.Sprintf("prayer lore %q", pr.Name), &pr)
+	}
+
+	return v.issues
+}
+
+// ValidateScourgeOfGhyran checks the universal enhancements every faction
+// can select, which no faction file lists.
+func ValidateScourgeOfGhyran() []DataIssue {
+	v := &validator{faction: &Faction{ID: "scourge-of-ghyran"}}
+	sog := DefaultScourgeOfGhyran()
+	v.validateEnhancements(sog.HeroicTraits, sog.Artefacts)
+	return v.issues
+}
+
+// ValidateDir loads every faction file in dir through a FactionRegistry and
+// validates it. Unlike LoadAllFactions it keeps going after a bad file and
+// reports cross-file problems such as duplicate faction or warscroll IDs.
+func ValidateDir(dir string) ([]DataIssue, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading faction directory %s: %w", dir, err)
+	}
+
+	var issues []DataIssue
+	registry := NewRegistry()
+	factionFile := make(map[string]string)
+	warscrollOwner := make(map[string]string)
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		f, err := registry.LoadFaction(path)
+		if err != nil {
+			loadErrs := LoadErrors(err)
+			if len(loadErrs) == 0 {
+				issues = append(issues, DataIssue{File: path, Kind: IssueInvalid, Message: err.Error()})
+			}
+			for _, le := range loadErrs {
+				issues = append(issues, DataIssue{File: path, Line: le.Line, Where: le.Context, Kind: IssueInvalid, Message: le.Msg})
+			}
+			continue
+		}
+
+		if other, ok := factionFile[f.ID]; ok {
+			issues = append(issues, DataIssue{File: path, FactionID: f.ID, Kind: IssueInvalid,
+				Message: fmt.Sprintf("duplicate faction id %q (also in %s)", f.ID, filepath.Base(other))})
+		} else {
+			factionFile[f.ID] = path
+		}
+
+		for _, ws := range f.Warscrolls {
+			if ws.ID == "" {
+				continue
+			}
+			if owner, ok := warscrollOwner[ws.ID]; ok && owner != f.ID {
+				issues = append(issues, DataIssue{File: path, FactionID: f.ID, Where: fmt.Sprintf("warscroll %q", ws.ID),
+					Kind: IssueInvalid, Message: fmt.Sprintf("warscroll id also used by faction %q", owner)})
+				continue
+			}
+			warscrollOwner[ws.ID] = f.ID
+		}
+
+		for _, is := range ValidateFaction(f) {
+			is.File = path
+			issues = append(issues, is)
+		}
+	}
+
+	issues = append(issues, ValidateScourgeOfGhyran()...)
+	return issues, nil
+}
+
+// validator accumulates issues for one faction.
+type validator struct {
+	faction *Faction
+	issues  []DataIssue
+}
+
+func (v *validator) add(kind IssueKind, where, msg string) {
+	v.issues = append(v.issues, DataIssue{FactionID: v.faction.ID, Where: where, Kind: kind, Message: msg})
+}
+
+func (v *validator) invalid(where, msg string)       { v.add(IssueInvalid, where, msg) }
+func (v *validator) unimplemented(where, msg string) { v.add(IssueUnimplemented, where, msg) }
+
+func (v *validator) validateWarscroll(where string, ws *Warscroll) {
+	for _, kw := range ws.Keywords {
+		if _, ok := coreKeywords[kw]; !ok {
+			v.unimplemented(where, fmt.Sprintf("keyword %q has no core mapping and is dropped", kw))
+		}
+	}
+	for _, w := range ws.Weapons {
+		for _, a := range w.Abilities {
+			if _, ok := weaponAbilities[a]; !ok {
+				v.invalid(fmt.Sprintf("%s: weapon %q", where, w.Name), fmt.Sprintf("unknown weapon ability %q", a))
+			}
+		}
+	}
+	for _, ab := range ws.Abilities {
+		if !implementedAbilityEffects[ab.Effect] {
+			v.unimplemented(fmt.Sprintf("%s: ability %q", where, ab.Name), fmt.Sprintf("effect %q has no handler", ab.Effect))
+		}
+	}
+	for _, sp := range ws.Spells {
+		v.validateSpell(fmt.Sprintf("%s: spell %q", where, sp.Name), &sp)
+	}
+	for _, pr := range ws.Prayers {
+		v.validatePrayer(fmt.Sprintf("%s: prayer %q", where, pr.Name), &pr)
+	}
+}
+
+func implementedSet(effects []string) map[string]bool {
+	set := make(map[string]bool, len(effects))
+	for _, e := range effects {
+		set[e] = true
+	}
+	return set
+}
+
+func (v *validator) validateEnhancements(traits, artefacts []Enhancement) {
+	seen := make(map[string]bool)
+	for _, group := range []struct {
+		label string
+		list  []Enhancement
+	}{{"heroic trait", traits}, {"artefact", artefacts}} {
+		for _, e := range group.list {
+			where := fmt.Sprintf("%s %q", group.label, e.Name)
+			if seen[e.Name] {
+				v.invalid(where, "duplicate enhancement name")
+			}
+			seen[e.Name] = true
+			// The compiler reports effects it has no case for.
+			if !RegisterEnhancementRules(rules.NewEngine(), &core.Unit{}, &e) {
+				v.unimplemented(where, fmt.Sprintf("effect %q has no handler", e.Effect))
+			}
+		}
+	}
+}
+
+func (v *validator) validateSpell(where string, sp *WarscrollSpell) {
+	if sp.CastingValue < minCastingValue || sp.CastingValue > maxCastingValue {
+		v.invalid(where, fmt.Sprintf("casting value %d must be %d..%d", sp.CastingValue, minCastingValue, maxCastingValue))
+	}
+	if _, ok := spellEffects[sp.Effect]; !ok {
+		v.invalid(where, fmt.Sprintf("unknown spell effect %q", sp.Effect))
+	}
+}
+
+func (v *validator) validatePrayer(where string, pr *WarscrollPrayer) {
+	if pr.ChantingValue < 1 {
+		v.invalid(where, fmt.Sprintf("chanting value %d must be at least 1", pr.ChantingValue))
+	}
+	if _, ok := spellEffects[pr.Effect]; !ok {
+		v.invalid(where, fmt.Sprintf("unknown prayer effect %q", pr.Effect))
+	}
+}
@@ -0,0 +1,232 @@
+package army
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func hasIssue(issues []DataIssue, kind IssueKind, substr string) bool {
+	for _, is := range issues {
+		if is.Kind == kind && strings.Contains(is.String(), substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateFaction_Clean(t *testing.T) {
+	f := &Faction{
+		ID: "test",
+		Warscrolls: []Warscroll{
+			{ID: "a", Name: "A", Keywords: []string{"Hero", "Wizard"}, Tags: []string{"Elite"},
+				Weapons:   []WarscrollWeapon{{Name: "Sword", Abilities: []string{"Charge"}}},
+				Abilities: []WarscrollAbility{{Name: "Tough", Effect: "ward", Value: 5}},
+				Spells:    []WarscrollSpell{{Name: "Bolt", CastingValue: 7, Effect: "damage"}}},
+		},
+		HeroicTraits: []Enhancement{{Name: "Brave", Effect: "ward", Value: 6}},
+	}
+	if issues := ValidateFaction(f); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func TestValidateFaction_ReportsProblems(t *testing.T) {
+	f := &Faction{
+		ID: "test",
+		Warscrolls: []Warscroll{
+			{ID: "a", Name: "A", Keywords: []string{"Infantry", "Daemon"},
+				Weapons:   []WarscrollWeapon{{Name: "Claw", Abilities: []string{"Crit(3 Hits)"}}},
+				Abilities: []WarscrollAbility{{Name: "Split", Effect: "splitHorrors"}},
+				Spells:    []WarscrollSpell{{Name: "Fizzle", CastingValue: 13, Effect: "damage"}}},
+			{ID: "a", Name: "A again"},
+		},
+		Formations: []BattleFormation{
+			{Name: "Host", Effects: []BattleFormationEffect{{TargetTag: "Missing", Effect: "rendBonus", Value: 1}}},
+		},
+		PrayerLore: []WarscrollPrayer{{Name: "Hymn", ChantingValue: 0, Effect: "heal"}},
+	}
+	issues := ValidateFaction(f)
+
+	checks := []struct {
+		kind   IssueKind
+		substr string
+	}{
+		{IssueUnimplemented, `keyword "Daemon"`},
+		{IssueInvalid, `unknown weapon ability "Crit(3 Hits)"`},
+		{IssueUnimplemented, `effect "splitHorrors"`},
+		{IssueInvalid, "casting value 13"},
+		{IssueInvalid, "duplicate warscroll id"},
+		{IssueInvalid, `target tag "Missing"`},
+		{IssueUnimplemented, `effect "rendBonus" has no handler`},
+		{IssueInvalid, "chanting value 0"},
+	}
+	for _, c := range checks {
+		if !hasIssue(issues, c.kind, c.substr) {
+			t.Errorf("expected %s issue containing %q, got %v", c.kind, c.substr, issues)
+		}
+	}
+}
+
+func TestValidateFaction_ChecksEachTraitAndFormationEffect(t *testing.T) {
+	f := &Faction{
+		ID: "seraphon",
+		Warscrolls: []Warscroll{
+			{ID: "a", Name: "A", Keywords: []string{"Infantry"}, Tags: []string{"Saurus"}},
+		},
+		BattleTraits: []FactionTrait{
+			{Name: "Scaly Skin", Effect: "saurusWard", Value: 6},
+			{Name: "Ancient Lore", Effect: "ancientLore"},
+		},
+		Formations: []BattleFormation{
+			{Name: "Sunclaw Temple-host", Effects: []BattleFormationEffect{
+				{TargetTag: "Saurus", Effect: "chargeRendBonus", Value: 1},
+				{TargetTag: "Saurus", Effect: "starlightBlessing", Value: 1},
+			}},
+		},
+	}
+	issues := ValidateFaction(f)
+
+	for _, want := range []string{`effect "ancientLore"`, `effect "starlightBlessing"`} {
+		if !hasIssue(issues, IssueUnimplemented, want) {
+			t.Errorf("expected unimplemented issue containing %q, got %v", want, issues)
+		}
+	}
+	for _, known := range []string{`effect "saurusWard"`, `effect "chargeRendBonus"`} {
+		if hasIssue(issues, IssueUnimplemented, known) {
+			t.Errorf("implemented %s reported as unimplemented: %v", known, issues)
+		}
+	}
+}
+
+func TestValidateDir_DuplicateFactionID(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte(`{"id":"dup","name":"Dup","warscrolls":[{"id":"dup_unit","name":"Unit","unitSize":1,"baseSizeMM":32,
+		"stats":{"move":5,"save":4,"control":1,"health":1}}]}`)
+	for _, name := range []string{"a.json", "b.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := ValidateDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasIssue(issues, IssueInvalid, `duplicate faction id "dup"`) {
+		t.Errorf("expected duplicate faction id issue, got %v", issues)
+	}
+	if !hasIssue(issues, IssueInvalid, "broken.json") {
+		t.Errorf("expected parse error for broken.json, got %v", issues)
+	}
+}
+
+func TestValidateDir_ShippedDataHasNoInvalidIssues(t *testing.T) {
+	dir := filepath.Join("..", "..", "..", "data", "factions")
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		t.Skip("data/factions not found, skipping")
+	}
+
+	issues, err := ValidateDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, is := range issues {
+		if is.Kind == IssueInvalid {
+			t.Errorf("invalid data: %s", is)
+		}
+	}
+}
+
+func TestValidateFaction_EnhancementsCheckedByCompiler(t *testing.T) {
+	f := &Faction{
+		ID:           "test",
+		HeroicTraits: []Enhancement{{Name: "Lucky", Effect: "fightOnDeath"}},
+		Artefacts:    []Enhancement{{Name: "Cloak", Effect: "invisible"}},
+	}
+	issues := ValidateFaction(f)
+	if hasIssue(issues, IssueUnimplemented, "Lucky") {
+		t.Errorf("fightOnDeath is implemented, got %v", issues)
+	}
+	if !hasIssue(issues, IssueUnimplemented, `effect "invisible"`) {
+		t.Errorf("expected unimplemented invisible effect, got %v", issues)
+	}
+}
+
+func TestValidateScourgeOfGhyran_ReportsUnimplementedDefaults(t *testing.T) {
+	issues := ValidateScourgeOfGhyran()
+	for _, is := range issues {
+		if is.Kind != IssueUnimplemented {
+			t.Errorf("unexpected issue: %s", is)
+		}
+	}
+	if !hasIssue(issues, IssueUnimplemented, `effect "redeploy"`) {
+		t.Errorf("expected redeploy to be reported, got %v", issues)
+	}
+	if hasIssue(issues, IssueUnimplemented, "Battle-hardened") {
+		t.Errorf("ward is implemented, got %v", issues)
+	}
+}
+
+// TestImplementedAbilityEffects_MatchCompilers keeps implementedAbilityEffects
+// in sync with the ability switches, which cannot report what they ignore.
+func TestImplementedAbilityEffects_MatchCompilers(t *testing.T) {
+	cases := switchCases(t, "roster.go", "ApplyToUnit")
+	for k := range switchCases(t, "factionrules.go", "RegisterWarscrollAbilityRules") {
+		cases[k] = true
+	}
+	for k := range cases {
+		if !implementedAbilityEffects[k] {
+			t.Errorf("effect %q is handled but missing from implementedAbilityEffects", k)
+		}
+	}
+	for k := range implementedAbilityEffects {
+		if !cases[k] {
+			t.Errorf("effect %q is in implementedAbilityEffects but has no case", k)
+		}
+	}
+}
+
+// switchCases returns the string constants of every case clause in the
+// named function of file.
+func switchCases(t *testing.T, file, fn string) map[string]bool {
+	t.Helper()
+	parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := make(map[string]bool)
+	found := false
+	for _, decl := range parsed.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Name.Name != fn {
+			continue
+		}
+		found = true
+		ast.Inspect(fd, func(n ast.Node) bool {
+			cc, ok := n.(*ast.CaseClause)
+			if !ok {
+				return true
+			}
+			for _, e := range cc.List {
+				if lit, ok := e.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					v, _ := strconv.Unquote(lit.Value)
+					cases[v] = true
+				}
+			}
+			return true
+		})
+	}
+	if !found {
+		t.Fatalf("%s not found in %s", fn, file)
+	}
+	return cases
+}
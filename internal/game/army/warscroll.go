@@ -90,23 +90,48 @@ func (w *Warscroll) HasKeyword(kw string) bool {
 	return false
 }
 
+// coreKeywords maps warscroll keyword strings to core.Keyword values.
+// Keywords missing from this table are dropped by ToCoreKeywords.
+var coreKeywords = map[string]core.Keyword{
+	"Infantry":      core.KeywordInfantry,
+	"Cavalry":       core.KeywordCavalry,
+	"Hero":          core.KeywordHero,
+	"Monster":       core.KeywordMonster,
+	"War Machine":   core.KeywordWarMachine,
+	"Wizard":        core.KeywordWizard,
+	"Priest":        core.KeywordPriest,
+	"Fly":           core.KeywordFly,
+	"Manifestation": core.KeywordManifestation,
+}
+
+// weaponAbilities maps weapon ability strings to the core bitmask.
+// Strings missing from this table are dropped by parseWeaponAbilities.
+var weaponAbilities = map[string]core.WeaponAbility{
+	"Anti-Infantry":    core.AbilityAntiInfantry,
+	"Anti-Cavalry":     core.AbilityAntiCavalry,
+	"Anti-Hero":        core.AbilityAntiHero,
+	"Anti-Monster":     core.AbilityAntiMonster,
+	"Anti-charge":      core.AbilityAntiCharge,
+	"Charge":           core.AbilityCharge,
+	"Crit(2 Hits)":     core.AbilityCrit2Hits,
+	"Crit(Auto-wound)": core.AbilityCritAutoWound,
+	"Crit(Mortal)":     core.AbilityCritMortal,
+	"Companion":        core.AbilityCompanion,
+	"Shoot in Combat":  core.AbilityShootInCombat,
+}
+
+// spellEffects lists the spell/prayer effect strings understood by parseSpellEffect.
+var spellEffects = map[string]core.SpellEffect{
+	"damage": core.SpellEffectDamage,
+	"heal":   core.SpellEffectHeal,
+	"buff":   core.SpellEffectBuff,
+}
+
 // ToCoreKeywords converts string keywords to core.Keyword values.
 func (w *Warscroll) ToCoreKeywords() []core.Keyword {
-	keywordMap := map[string]core.Keyword{
-		"Infantry":      core.KeywordInfantry,
-		"Cavalry":       core.KeywordCavalry,
-		"Hero":          core.KeywordHero,
-		"Monster":       core.KeywordMonster,
-		"War Machine":   core.KeywordWarMachine,
-		"Wizard":        core.KeywordWizard,
-		"Priest":        core.KeywordPriest,
-		"Fly":           core.KeywordFly,
-		"Manifestation": core.KeywordManifestation,
-	}
-
 	var result []core.Keyword
 	for _, k := range w.Keywords {
-		if ck, ok := keywordMap[k]; ok {
+		if ck, ok := coreKeywords[k]; ok {
 			result = append(result, ck)
 		}
 	}
@@ -178,21 +203,8 @@ func (w *Warscroll) ToCorePrayers() []core.Prayer {
 // parseWeaponAbilities converts string ability tags to the bitmask.
 func parseWeaponAbilities(abilities []string) core.WeaponAbility {
 	var result core.WeaponAbility
-	abilityMap := map[string]core.WeaponAbility{
-		"Anti-Infantry":    core.AbilityAntiInfantry,
-		"Anti-Cavalry":     core.AbilityAntiCavalry,
-		"Anti-Hero":        core.AbilityAntiHero,
-		"Anti-Monster":     core.AbilityAntiMonster,
-		"Anti-charge":      core.AbilityAntiCharge,
-		"Charge":           core.AbilityCharge,
-		"Crit(2 Hits)":     core.AbilityCrit2Hits,
-		"Crit(Auto-wound)": core.AbilityCritAutoWound,
-		"Crit(Mortal)":     core.AbilityCritMortal,
-		"Companion":        core.AbilityCompanion,
-		"Shoot in Combat":  core.AbilityShootInCombat,
-	}
 	for _, a := range abilities {
-		if wa, ok := abilityMap[a]; ok {
+		if wa, ok := weaponAbilities[a]; ok {
 			result |= wa
 		}
 	}
@@ -200,14 +212,8 @@ func parseWeaponAbilities(abilities []string) core.WeaponAbility {
 }
 
 func parseSpellEffect(s string) core.SpellEffect {
-	switch s {
-	case "damage":
-		return core.SpellEffectDamage
-	case "heal":
-		return core.SpellEffectHeal
-	case "buff":
-		return core.SpellEffectBuff
-	default:
-		return core.SpellEffectDamage
+	if e, ok := spellEffects[s]; ok {
+		return e
 	}
+	return core.SpellEffectDamage
 }
@@ -2,6 +2,7 @@ package game
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/jruiznavarro/wargamestactics/internal/game/core"
 	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
@@ -22,6 +23,10 @@ type CombatResult struct {
 	MortalDealt  int
 	WardSaved    int
 	ModelsSlain  int
+
+	// Trace lists the rules that applied during the resolution, in order,
+	// with what each changed.
+	Trace []rules.Applied
 }
 
 func (r CombatResult) String() string {
@@ -32,6 +37,15 @@ func (r CombatResult) String() string {
 	)
 }
 
+// combatTrace joins the traces of a set of combat results.
+func combatTrace(results []CombatResult) []rules.Applied {
+	var trace []rules.Applied
+	for _, r := range results {
+		trace = append(trace, r.Trace...)
+	}
+	return trace
+}
+
 // clampHitWoundMod caps hit/wound modifiers. AoS4 Rule 17.1:
 // Hit and wound roll modifiers are capped at +1/-1.
 func clampHitWoundMod(mod int) int {
@@ -57,100 +71,174 @@ func clampSaveMod(mod int) int {
 // AoS4 Rules 17.0: hit -> wound -> save -> damage, with modifier caps,
 // critical hits, weapon abilities, and ward saves.
 func ResolveAttacks(roller *dice.Roller, engine *rules.Engine, attacker *core.Unit, defender *core.Unit, weapon *core.Weapon, isShooting bool) CombatResult {
-	aliveModelsBefore := defender.AliveModels()
-	baseAttacks := attacker.AliveModels() * weapon.Attacks
+	return resolveAttacks(roller, engine, attacker, defender, weapon, isShooting, nil, nil)
+}
 
-	// Build base context
-	baseCtx := &rules.Context{
-		Attacker:   attacker,
-		Defender:   defender,
-		Weapon:     weapon,
-		IsShooting: isShooting,
-	}
+// contextPool recycles the rules.Context each weapon's attack sequence is
+// evaluated with. Contexts are reset before they go back in the pool.
+var contextPool = sync.Pool{New: func() any { return new(rules.Context) }}
+
+// spendFunc lets the owner of a unit replace some dice of a roll with stored
+// dice (see Game.storedDice). A nil spendFunc never replaces dice.
+type spendFunc func(unit *core.Unit, kind RollKind, n, target int) []int
+
+// attackProfile is what the rules make of one weapon's attacks before any
+// dice are rolled: the number of attacks and, for each step, its modifier,
+// re-roll and threshold. Rolling dice never changes it, so resolveAttacks
+// rolls against it and the odds functions (mathhammer.go) compute exact
+// distributions from it.
+type attackProfile struct {
+	attacks        int
+	hitMod         int
+	rerollHit      dice.RerollType
+	woundMod       int
+	rerollWound    dice.RerollType
+	saveThreshold  int // Save + Rend - save modifier; above 6 saves are impossible
+	rerollSave     dice.RerollType
+	damagePerWound int
+	ruleMortals    int // Mortal wounds inflicted by rules
+	ward           int // 0 if the defender has no ward
+
+	// trace lists the rules that applied, in order; the hit, wound, save
+	// and ward traces are the parts of it shown as reminders before those
+	// rolls.
+	trace                                      []rules.Applied
+	hitTrace, woundTrace, saveTrace, wardTrace []rules.Applied
+}
 
-	// Step 0: Attack count modifiers
-	engine.Evaluate(rules.BeforeAttackCount, baseCtx)
-	totalAttacks := baseAttacks + baseCtx.Modifiers.AttacksMod
-	if totalAttacks < 0 {
-		totalAttacks = 0
+// evaluateAttacks runs the rules for each step of one weapon's attack
+// sequence. AoS4 Rules 17.0-18.1: attack count, hit, wound and save
+// modifiers with their caps, Rend including Anti-X, damage and ward.
+func evaluateAttacks(engine *rules.Engine, attacker *core.Unit, defender *core.Unit, weapon *core.Weapon, isShooting bool, grid *core.Grid) attackProfile {
+	// One context, reset before each step, serves every evaluation for the
+	// weapon; each step's trace is copied into the profile before the reset.
+	ctx := contextPool.Get().(*rules.Context)
+	defer func() {
+		ctx.Reset()
+		contextPool.Put(ctx)
+	}()
+	var p attackProfile
+	// next ends the current step and returns its trace.
+	next := func() []rules.Applied {
+		from := len(p.trace)
+		p.trace = append(p.trace, ctx.Trace...)
+		p.ruleMortals += ctx.Modifiers.MortalWounds
+		ctx.Reset()
+		ctx.Attacker, ctx.Defender, ctx.Weapon, ctx.IsShooting = attacker, defender, weapon, isShooting
+		ctx.Grid = grid
+		return p.trace[from:len(p.trace):len(p.trace)]
 	}
+	next()
 
-	result := CombatResult{
-		AttackerID:   attacker.ID,
-		DefenderID:   defender.ID,
-		WeaponName:   weapon.Name,
-		TotalAttacks: totalAttacks,
+	// Step 0: Attack count modifiers
+	engine.Evaluate(rules.BeforeAttackCount, ctx)
+	p.attacks = attacker.AliveModels()*weapon.Attacks + ctx.Modifiers.AttacksMod
+	if p.attacks < 0 {
+		p.attacks = 0
 	}
+	next()
 
 	// Companion weapons ignore positive hit/wound modifiers from abilities (Rule 20.0)
 	isCompanion := weapon.HasAbility(core.AbilityCompanion)
 
-	// Step 1: Hit rolls with modifier caps (Rule 17.1)
-	// Re-rolls happen before modifiers (Rule 2.2, Errata Jan 2026)
-	hitCtx := &rules.Context{Attacker: attacker, Defender: defender, Weapon: weapon, IsShooting: isShooting}
-	engine.Evaluate(rules.BeforeHitRoll, hitCtx)
-	hitMod := clampHitWoundMod(hitCtx.Modifiers.HitMod)
-	if isCompanion && hitMod > 0 {
-		hitMod = 0
+	// Step 1: Hit modifier caps (Rule 17.1)
+	engine.Evaluate(rules.BeforeHitRoll, ctx)
+	if isCompanion {
+		ctx.IgnorePositive(rules.HitModifier)
 	}
-
-	hr := rollHits(roller, totalAttacks, weapon.ToHit, hitMod, weapon, hitCtx.RerollHit)
-	result.Hits = hr.Hits + hr.AutoWounds // Total hits for display
-	result.CriticalHits = hr.Crits
-
-	// Step 2: Wound rolls with modifier caps (Rule 17.1)
-	// Only normal hits go through wound rolls; auto-wounds skip this step
-	woundCtx := &rules.Context{Attacker: attacker, Defender: defender, Weapon: weapon, IsShooting: isShooting}
-	engine.Evaluate(rules.BeforeWoundRoll, woundCtx)
-	woundMod := clampHitWoundMod(woundCtx.Modifiers.WoundMod)
-	if isCompanion && woundMod > 0 {
-		woundMod = 0
+	p.hitMod = clampHitWoundMod(ctx.Modifiers.HitMod)
+	p.rerollHit = ctx.RerollHit
+	p.hitTrace = next()
+
+	// Step 2: Wound modifier caps (Rule 17.1)
+	engine.Evaluate(rules.BeforeWoundRoll, ctx)
+	if isCompanion {
+		ctx.IgnorePositive(rules.WoundModifier)
 	}
-	wounds := rollWounds(roller, hr.Hits, weapon.ToWound, woundMod, woundCtx.RerollWound) + hr.AutoWounds
-	result.Wounds = wounds
-
-	// Step 3: Save rolls with modifier caps (Rule 17.1)
-	saveCtx := &rules.Context{Attacker: attacker, Defender: defender, Weapon: weapon, IsShooting: isShooting}
-	engine.Evaluate(rules.BeforeSaveRoll, saveCtx)
-	saveMod := clampSaveMod(saveCtx.Modifiers.SaveMod)
+	p.woundMod = clampHitWoundMod(ctx.Modifiers.WoundMod)
+	p.rerollWound = ctx.RerollWound
+	p.woundTrace = next()
 
+	// Step 3: Save modifier caps (Rule 17.1)
+	engine.Evaluate(rules.BeforeSaveRoll, ctx)
+	saveMod := clampSaveMod(ctx.Modifiers.SaveMod)
 	// Calculate effective Rend: base + Anti-X bonuses + rule modifiers
-	effectiveRend := weapon.Rend + saveCtx.Modifiers.RendMod + applyAntiRend(weapon, defender)
-
+	effectiveRend := weapon.Rend + ctx.Modifiers.RendMod + applyAntiRend(weapon, defender)
 	// Save threshold = Save + Rend - SaveMod
 	// Rend is stored as positive (e.g. 1), making save harder
-	saveThreshold := defender.Stats.Save + effectiveRend - saveMod
-	savesFailed := rollSaves(roller, wounds, saveThreshold, saveCtx.RerollSave)
-	result.SavesFailed = savesFailed
+	p.saveThreshold = defender.Stats.Save + effectiveRend - saveMod
+	p.rerollSave = ctx.RerollSave
+	p.saveTrace = next()
 
 	// Step 4: Damage (with Charge weapon ability, Rule 20.0)
-	dmgCtx := &rules.Context{Attacker: attacker, Defender: defender, Weapon: weapon, IsShooting: isShooting}
-	engine.Evaluate(rules.BeforeDamage, dmgCtx)
-	damagePerWound := weapon.Damage + dmgCtx.Modifiers.DamageMod
+	engine.Evaluate(rules.BeforeDamage, ctx)
+	p.damagePerWound = weapon.Damage + ctx.Modifiers.DamageMod
 	if weapon.HasAbility(core.AbilityCharge) && attacker.HasCharged {
-		damagePerWound++
+		p.damagePerWound++
 	}
-	if damagePerWound < 1 {
-		damagePerWound = 1
+	if p.damagePerWound < 1 {
+		p.damagePerWound = 1
 	}
+	next()
 
-	// Build damage pool (Rule 18.0)
-	damagePool := savesFailed * damagePerWound
+	// Step 5: Ward saves (Rule 18.1)
+	p.ward = wardFor(engine, ctx)
+	p.wardTrace = next()
+	return p
+}
 
-	// Add mortal wounds from Crit (Mortal) and rules
-	totalMortals := hr.CritMortals +
-		baseCtx.Modifiers.MortalWounds +
-		hitCtx.Modifiers.MortalWounds +
-		woundCtx.Modifiers.MortalWounds +
-		saveCtx.Modifiers.MortalWounds +
-		dmgCtx.Modifiers.MortalWounds
+// resolveAttacks is ResolveAttacks with stored dice offered to the attacker
+// for hit and wound rolls and to the defender for save rolls, and with the
+// game's spatial grid available to rules for proximity checks.
+func resolveAttacks(roller *dice.Roller, engine *rules.Engine, attacker *core.Unit, defender *core.Unit, weapon *core.Weapon, isShooting bool, spend spendFunc, grid *core.Grid) CombatResult {
+	aliveModelsBefore := defender.AliveModels()
+	p := evaluateAttacks(engine, attacker, defender, weapon, isShooting, grid)
+
+	result := CombatResult{
+		AttackerID:   attacker.ID,
+		DefenderID:   defender.ID,
+		WeaponName:   weapon.Name,
+		TotalAttacks: p.attacks,
+		Trace:        p.trace,
+	}
+
+	// Step 1: Hit rolls
+	// Re-rolls happen before modifiers (Rule 2.2, Errata Jan 2026)
+	remind(roller, "hit", p.hitTrace)
+	hitDice := newRollBatch(roller, spend, attacker, RollHit, p.attacks, weapon.ToHit-p.hitMod)
+	hr := rollHits(hitDice, p.attacks, weapon.ToHit, p.hitMod, weapon, p.rerollHit)
+	result.Hits = hr.Hits + hr.AutoWounds // Total hits for display
+	result.CriticalHits = hr.Crits
+
+	// Step 2: Wound rolls
+	// Only normal hits go through wound rolls; auto-wounds skip this step
+	remind(roller, "wound", p.woundTrace)
+	woundDice := newRollBatch(roller, spend, attacker, RollWound, hr.Hits, weapon.ToWound-p.woundMod)
+	wounds := rollWounds(woundDice, hr.Hits, weapon.ToWound, p.woundMod, p.rerollWound) + hr.AutoWounds
+	result.Wounds = wounds
+
+	// Step 3: Save rolls
+	remind(roller, "save", p.saveTrace)
+	saveRolls := wounds
+	if p.saveThreshold > 6 {
+		saveRolls = 0 // Saves are impossible; nothing is rolled
+	}
+	saveDice := newRollBatch(roller, spend, defender, RollSave, saveRolls, p.saveThreshold)
+	savesFailed := rollSaves(saveDice, wounds, p.saveThreshold, p.rerollSave)
+	result.SavesFailed = savesFailed
+
+	// Step 4: Build damage pool (Rule 18.0), adding mortal wounds from
+	// Crit (Mortal) and rules
+	damagePool := savesFailed * p.damagePerWound
+	totalMortals := hr.CritMortals + p.ruleMortals
 	damagePool += totalMortals
 	result.MortalDealt = totalMortals
 
 	// Step 5: Ward saves (Rule 18.1)
 	wardSaved := 0
-	if defender.WardSave > 0 && damagePool > 0 {
-		wardSaved = rollWards(roller, damagePool, defender.WardSave)
+	if p.ward > 0 && damagePool > 0 {
+		remind(roller, "ward", p.wardTrace)
+		wardSaved = rollWards(roller, damagePool, p.ward)
 		damagePool -= wardSaved
 	}
 	result.WardSaved = wardSaved
@@ -165,14 +253,34 @@ func ResolveAttacks(roller *dice.Roller, engine *rules.Engine, attacker *core.Un
 	return result
 }
 
+// wardFor returns the ward save that applies to ctx.Defender. BeforeWardSave
+// rules may grant a better ward via WardOverride; the best one is used.
+// Returns 0 if the defender has no ward.
+func wardFor(engine *rules.Engine, ctx *rules.Context) int {
+	ward := ctx.Defender.WardSave
+	if engine == nil {
+		return ward
+	}
+	engine.Evaluate(rules.BeforeWardSave, ctx)
+	if ctx.WardOverride > 0 && (ward == 0 || ctx.WardOverride < ward) {
+		ward = ctx.WardOverride
+	}
+	return ward
+}
+
 // ResolveMortalWounds applies mortal wounds directly, bypassing saves.
 // Ward saves still apply (Rule 18.1).
 func ResolveMortalWounds(roller *dice.Roller, defender *core.Unit, mortalWounds int) (damage int, slain int) {
+	return resolveMortalWounds(roller, defender, mortalWounds, defender.WardSave)
+}
+
+// resolveMortalWounds applies mortal wounds against the given ward value.
+func resolveMortalWounds(roller *dice.Roller, defender *core.Unit, mortalWounds int, ward int) (damage int, slain int) {
 	aliveModelsBefore := defender.AliveModels()
 	pool := mortalWounds
 
-	if defender.WardSave > 0 && pool > 0 {
-		warded := rollWards(roller, pool, defender.WardSave)
+	if ward > 0 && pool > 0 {
+		warded := rollWards(roller, pool, ward)
 		pool -= warded
 	}
 
@@ -184,37 +292,52 @@ func ResolveMortalWounds(roller *dice.Roller, defender *core.Unit, mortalWounds
 
 // ResolveCombat resolves all melee weapon attacks from attacker against defender.
 func ResolveCombat(roller *dice.Roller, engine *rules.Engine, attacker *core.Unit, defender *core.Unit) []CombatResult {
+	return resolveCombat(roller, engine, attacker, defender, nil, nil)
+}
+
+// resolveCombat is ResolveCombat with stored dice offered via spend and the
+// game's spatial grid passed to the rules.
+func resolveCombat(roller *dice.Roller, engine *rules.Engine, attacker *core.Unit, defender *core.Unit, spend spendFunc, grid *core.Grid) []CombatResult {
 	var results []CombatResult
-	defenderAliveStart := defender.AliveModels()
+	aliveBefore := aliveModels(defender)
 	for _, idx := range attacker.MeleeWeapons() {
 		if attacker.IsDestroyed() || defender.IsDestroyed() {
 			break
 		}
-		result := ResolveAttacks(roller, engine, attacker, defender, &attacker.Weapons[idx], false)
+		result := resolveAttacks(roller, engine, attacker, defender, &attacker.Weapons[idx], false, spend, grid)
 		results = append(results, result)
 	}
-	fireCombatTriggers(engine, attacker, defender, results, defenderAliveStart, false)
+	fireCombatTriggers(engine, attacker, defender, results, aliveBefore, false, grid)
 	return results
 }
 
 // ResolveShooting resolves all ranged weapon attacks from attacker against defender.
 func ResolveShooting(roller *dice.Roller, engine *rules.Engine, attacker *core.Unit, defender *core.Unit) []CombatResult {
+	return resolveShooting(roller, engine, attacker, defender, nil, nil)
+}
+
+// resolveShooting is ResolveShooting with stored dice offered via spend and
+// the game's spatial grid passed to the rules.
+func resolveShooting(roller *dice.Roller, engine *rules.Engine, attacker *core.Unit, defender *core.Unit, spend spendFunc, grid *core.Grid) []CombatResult {
 	var results []CombatResult
-	defenderAliveStart := defender.AliveModels()
+	aliveBefore := aliveModels(defender)
 	for _, idx := range attacker.RangedWeapons() {
 		if attacker.IsDestroyed() || defender.IsDestroyed() {
 			break
 		}
-		result := ResolveAttacks(roller, engine, attacker, defender, &attacker.Weapons[idx], true)
+		result := resolveAttacks(roller, engine, attacker, defender, &attacker.Weapons[idx], true, spend, grid)
 		results = append(results, result)
 	}
-	fireCombatTriggers(engine, attacker, defender, results, defenderAliveStart, true)
+	fireCombatTriggers(engine, attacker, defender, results, aliveBefore, true, grid)
 	return results
 }
 
 // fireCombatTriggers fires post-combat events via the rules engine.
-func fireCombatTriggers(engine *rules.Engine, attacker *core.Unit, defender *core.Unit, results []CombatResult, defenderAliveStart int, isShooting bool) {
-	totalSlain := defenderAliveStart - defender.AliveModels()
+// aliveBefore are the defender's models that were alive before the attacks.
+func fireCombatTriggers(engine *rules.Engine, attacker *core.Unit, defender *core.Unit, results []CombatResult, aliveBefore []int, isShooting bool, grid *core.Grid) {
+	slain := slainSince(defender, aliveBefore)
+	totalSlain := len(slain)
+	units := grid.Units()
 
 	// AfterCombatResolve — always fires after a combat resolution
 	totalDamage := 0
@@ -225,6 +348,8 @@ func fireCombatTriggers(engine *rules.Engine, attacker *core.Unit, defender *cor
 		Attacker:   attacker,
 		Defender:   defender,
 		IsShooting: isShooting,
+		AllUnits:   units,
+		Grid:       grid,
 	}
 	afterCtx.Modifiers.DamageMod = totalDamage // Reuse as "total damage dealt" info
 	engine.Evaluate(rules.AfterCombatResolve, afterCtx)
@@ -234,7 +359,10 @@ func fireCombatTriggers(engine *rules.Engine, attacker *core.Unit, defender *cor
 		slainCtx := &rules.Context{
 			Attacker:   attacker,
 			Defender:   defender,
+			Slain:      slain,
 			IsShooting: isShooting,
+			AllUnits:   units,
+			Grid:       grid,
 		}
 		slainCtx.Modifiers.AttacksMod = totalSlain // Reuse as "models slain count" info
 		engine.Evaluate(rules.OnModelSlain, slainCtx)
@@ -245,12 +373,38 @@ func fireCombatTriggers(engine *rules.Engine, attacker *core.Unit, defender *cor
 		destroyedCtx := &rules.Context{
 			Attacker:   attacker,
 			Defender:   defender,
+			Slain:      slain,
 			IsShooting: isShooting,
+			AllUnits:   units,
+			Grid:       grid,
 		}
 		engine.Evaluate(rules.OnUnitDestroyed, destroyedCtx)
 	}
 }
 
+// aliveModels returns the indices of a unit's alive models.
+func aliveModels(u *core.Unit) []int {
+	var alive []int
+	for i := range u.Models {
+		if u.Models[i].IsAlive {
+			alive = append(alive, i)
+		}
+	}
+	return alive
+}
+
+// slainSince returns which of the models in aliveBefore have since been
+// slain.
+func slainSince(u *core.Unit, aliveBefore []int) []int {
+	var slain []int
+	for _, i := range aliveBefore {
+		if !u.Models[i].IsAlive {
+			slain = append(slain, i)
+		}
+	}
+	return slain
+}
+
 // applyAntiRend calculates extra Rend from Anti-X weapon abilities (Rule 20.0).
 func applyAntiRend(weapon *core.Weapon, defender *core.Unit) int {
 	extra := 0
@@ -284,138 +438,136 @@ type hitResult struct {
 // Natural 1 always fails. Unmodified 6 = critical hit.
 // Processes Crit weapon abilities.
 // Re-rolls happen on the unmodified roll before modifiers (Rule 2.2, Errata Jan 2026).
-func rollHits(roller *dice.Roller, numAttacks, toHit, modifier int, weapon *core.Weapon, reroll dice.RerollType) hitResult {
+// Stored dice taken from the batch are not re-rolled.
+func rollHits(batch *rollBatch, numAttacks, toHit, modifier int, weapon *core.Weapon, reroll dice.RerollType) hitResult {
 	var r hitResult
 	for i := 0; i < numAttacks; i++ {
-		roll := roller.RollD6()
+		roll, stored := batch.next()
 
 		// Re-roll before modifiers (Rule 2.2)
-		if reroll != dice.RerollNone {
-			shouldReroll := false
-			switch reroll {
-			case dice.RerollFailed:
-				shouldReroll = roll+modifier < toHit && roll != 6 // Don't re-roll crits
-			case dice.RerollOnes:
-				shouldReroll = roll == 1
-			case dice.RerollAll:
-				shouldReroll = true
-			}
-			if shouldReroll {
-				roll = roller.RollD6() // Max 1 re-roll per die
-			}
+		if !stored && rerollHit(reroll, roll, toHit, modifier) {
+			roll = batch.reroll() // Max 1 re-roll per die
 		}
+		r.add(roll, toHit, modifier, weapon)
+	}
+	return r
+}
 
-		if roll == 1 {
-			continue
-		}
+// rerollHit reports whether a hit or wound roll is re-rolled. Crits are not
+// re-rolled as failures.
+func rerollHit(reroll dice.RerollType, roll, need, modifier int) bool {
+	return rerolls(reroll, roll, roll+modifier < need && roll != 6)
+}
 
-		isCrit := roll == 6
+// rerolls reports whether a roll that failed or not is re-rolled.
+func rerolls(reroll dice.RerollType, roll int, failed bool) bool {
+	switch reroll {
+	case dice.RerollFailed:
+		return failed
+	case dice.RerollOnes:
+		return roll == 1
+	case dice.RerollAll:
+		return true
+	}
+	return false
+}
 
-		// Crit (Mortal): inflict mortal damage = weapon Damage, attack sequence ends
-		if isCrit && weapon.HasAbility(core.AbilityCritMortal) {
-			r.CritMortals += weapon.Damage
-			r.Crits++
-			continue
-		}
+// add scores one final hit roll.
+func (r *hitResult) add(roll, toHit, modifier int, weapon *core.Weapon) {
+	if roll == 1 {
+		return
+	}
 
-		// Crit (Auto-wound): automatically wounds, skips wound roll -> goes to save
-		if isCrit && weapon.HasAbility(core.AbilityCritAutoWound) {
-			r.AutoWounds++
-			r.Crits++
-			continue
-		}
+	isCrit := roll == 6
 
-		modifiedRoll := roll + modifier
+	// Crit (Mortal): inflict mortal damage = weapon Damage, attack sequence ends
+	if isCrit && weapon.HasAbility(core.AbilityCritMortal) {
+		r.CritMortals += weapon.Damage
+		r.Crits++
+		return
+	}
 
-		// Crit (2 Hits): scores 2 hits instead of 1
-		if isCrit && weapon.HasAbility(core.AbilityCrit2Hits) {
-			if modifiedRoll >= toHit {
-				r.Hits += 2
-				r.Crits++
-			}
-			continue
-		}
+	// Crit (Auto-wound): automatically wounds, skips wound roll -> goes to save
+	if isCrit && weapon.HasAbility(core.AbilityCritAutoWound) {
+		r.AutoWounds++
+		r.Crits++
+		return
+	}
+
+	modifiedRoll := roll + modifier
 
+	// Crit (2 Hits): scores 2 hits instead of 1
+	if isCrit && weapon.HasAbility(core.AbilityCrit2Hits) {
 		if modifiedRoll >= toHit {
-			r.Hits++
-			if isCrit {
-				r.Crits++
-			}
+			r.Hits += 2
+			r.Crits++
+		}
+		return
+	}
+
+	if modifiedRoll >= toHit {
+		r.Hits++
+		if isCrit {
+			r.Crits++
 		}
 	}
-	return r
 }
 
 // rollWounds rolls D6s for wounds. Natural 1 always fails.
 // Re-rolls happen before modifiers (Rule 2.2, Errata Jan 2026).
-func rollWounds(roller *dice.Roller, numHits, toWound, modifier int, reroll dice.RerollType) int {
+func rollWounds(batch *rollBatch, numHits, toWound, modifier int, reroll dice.RerollType) int {
 	wounds := 0
 	for i := 0; i < numHits; i++ {
-		roll := roller.RollD6()
+		roll, stored := batch.next()
 
 		// Re-roll before modifiers (Rule 2.2)
-		if reroll != dice.RerollNone {
-			shouldReroll := false
-			switch reroll {
-			case dice.RerollFailed:
-				shouldReroll = roll+modifier < toWound && roll != 6
-			case dice.RerollOnes:
-				shouldReroll = roll == 1
-			case dice.RerollAll:
-				shouldReroll = true
-			}
-			if shouldReroll {
-				roll = roller.RollD6()
-			}
-		}
-
-		if roll == 1 {
-			continue
+		if !stored && rerollHit(reroll, roll, toWound, modifier) {
+			roll = batch.reroll()
 		}
-		if roll+modifier >= toWound {
+		if woundSucceeds(roll, toWound, modifier) {
 			wounds++
 		}
 	}
 	return wounds
 }
 
+// woundSucceeds reports whether a final wound roll wounds.
+func woundSucceeds(roll, toWound, modifier int) bool {
+	return roll != 1 && roll+modifier >= toWound
+}
+
 // rollSaves rolls D6s for saves. Natural 1 always fails.
 // saveThreshold > 6 means saves are impossible.
 // Re-rolls happen before modifiers (Rule 2.2, Errata Jan 2026).
-func rollSaves(roller *dice.Roller, numWounds, saveThreshold int, reroll dice.RerollType) int {
+func rollSaves(batch *rollBatch, numWounds, saveThreshold int, reroll dice.RerollType) int {
 	failed := 0
 	for i := 0; i < numWounds; i++ {
 		if saveThreshold > 6 {
 			failed++
 			continue
 		}
-		roll := roller.RollD6()
+		roll, stored := batch.next()
 
 		// Re-roll before modifiers (Rule 2.2)
-		if reroll != dice.RerollNone {
-			shouldReroll := false
-			switch reroll {
-			case dice.RerollFailed:
-				shouldReroll = roll == 1 || roll < saveThreshold
-			case dice.RerollOnes:
-				shouldReroll = roll == 1
-			case dice.RerollAll:
-				shouldReroll = true
-			}
-			if shouldReroll {
-				roll = roller.RollD6()
-			}
+		if !stored && rerolls(reroll, roll, saveFails(roll, saveThreshold)) {
+			roll = batch.reroll()
 		}
 
-		if roll == 1 || roll < saveThreshold {
+		if saveFails(roll, saveThreshold) {
 			failed++
 		}
 	}
 	return failed
 }
 
+// saveFails reports whether a final save roll fails.
+func saveFails(roll, saveThreshold int) bool {
+	return roll == 1 || roll < saveThreshold
+}
+
 // rollWards rolls D6 per damage point. AoS4 Rule 18.1.
 func rollWards(roller *dice.Roller, damagePool, wardValue int) int {
+	roller.Label("ward", 1, damagePool)
 	saved := 0
 	for i := 0; i < damagePool; i++ {
 		roll := roller.RollD6()
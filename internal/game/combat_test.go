@@ -1,6 +1,8 @@
 package game
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/jruiznavarro/wargamestactics/internal/game/core"
@@ -82,6 +84,51 @@ func TestResolveAttacks_Deterministic(t *testing.T) {
 	}
 }
 
+func TestResolveAttacks_Scripted(t *testing.T) {
+	script := dice.NewScript().
+		Expect("hit", 6, 1, 3).
+		Expect("wound", 4, 2).
+		Expect("save", 4)
+	roller := dice.NewRollerFromSource(script)
+	attacker := newTestAttacker()
+	defender := newTestDefender()
+
+	result := ResolveAttacks(roller, newTestEngine(), attacker, defender, &attacker.Weapons[0], false)
+
+	// 3+ to hit: 6 and 3 hit; 3+ to wound: only 4 wounds; Rend 1 makes the
+	// 4+ save a 5+, so the 4 fails and Damage 2 slays one model.
+	if result.Hits != 2 || result.Wounds != 1 || result.SavesFailed != 1 {
+		t.Errorf("expected 2 hits, 1 wound, 1 failed save, got %d, %d, %d",
+			result.Hits, result.Wounds, result.SavesFailed)
+	}
+	if result.DamageDealt != 2 || result.ModelsSlain != 1 {
+		t.Errorf("expected 2 damage slaying 1 model, got %d damage, %d slain", result.DamageDealt, result.ModelsSlain)
+	}
+	if script.Remaining() != 0 {
+		t.Errorf("expected the whole script to be rolled, %d values left", script.Remaining())
+	}
+	if got := script.Rolled()[1]; got != "hit roll, die 2 of 3: 1" {
+		t.Errorf("expected second die labelled %q, got %q", "hit roll, die 2 of 3: 1", got)
+	}
+}
+
+func TestResolveAttacks_ScriptExhausted(t *testing.T) {
+	roller := dice.NewRollerFromSource(dice.NewScript(6, 6))
+	attacker := newTestAttacker()
+
+	err := func() (err error) {
+		defer dice.CatchRollError(&err)
+		ResolveAttacks(roller, newTestEngine(), attacker, newTestDefender(), &attacker.Weapons[0], false)
+		return nil
+	}()
+	if !errors.Is(err, dice.ErrScriptExhausted) {
+		t.Fatalf("expected script exhausted error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "hit roll, die 3 of 3") {
+		t.Errorf("expected error to name the roll, got %q", err)
+	}
+}
+
 func TestResolveAttacks_DamageAllocation(t *testing.T) {
 	roller := dice.NewRoller(100)
 	engine := newTestEngine()
@@ -605,6 +652,42 @@ func TestWardSave_ReducesDamage(t *testing.T) {
 	}
 }
 
+func TestWardSave_FromRule(t *testing.T) {
+	engine := rules.NewEngine()
+	engine.AddRule(rules.Rule{
+		Name:    "Test Ward",
+		Trigger: rules.BeforeWardSave,
+		Source:  rules.SourceEnhancement,
+		Condition: func(ctx *rules.Context) bool {
+			return ctx.Defender != nil && ctx.Defender.ID == 2
+		},
+		Apply: func(ctx *rules.Context) {
+			ctx.WardOverride = 4
+		},
+	})
+
+	attacker := &core.Unit{
+		ID: 1,
+		Models: []core.Model{
+			{ID: 0, CurrentWounds: 1, MaxWounds: 1, IsAlive: true},
+		},
+		Weapons: []core.Weapon{
+			{Name: "Test", Attacks: 100, ToHit: 2, ToWound: 2, Rend: 5, Damage: 1},
+		},
+	}
+	defender := &core.Unit{
+		ID:    2,
+		Stats: core.Stats{Save: 6, Health: 1},
+		Models: []core.Model{
+			{ID: 0, CurrentWounds: 500, MaxWounds: 500, IsAlive: true},
+		},
+	}
+	result := ResolveAttacks(dice.NewRoller(42), engine, attacker, defender, &attacker.Weapons[0], false)
+	if result.WardSaved == 0 {
+		t.Error("ward from a BeforeWardSave rule should have prevented some damage")
+	}
+}
+
 func TestResolveAttacks_WithRuleModifiers(t *testing.T) {
 	roller := dice.NewRoller(42)
 	engine := rules.NewEngine()
@@ -765,3 +848,61 @@ func TestRerollSave_ReducesDamage(t *testing.T) {
 			withReroll.DamageDealt, withoutReroll.DamageDealt)
 	}
 }
+
+func TestResolveAttacks_Trace(t *testing.T) {
+	engine := rules.NewEngine()
+	engine.AddRule(rules.Rule{
+		Name:    "All-out Attack",
+		Trigger: rules.BeforeHitRoll,
+		Source:  rules.SourceGlobal,
+		Apply:   func(ctx *rules.Context) { ctx.Modifiers.HitMod++ },
+	})
+	engine.AddRule(rules.Rule{
+		Name:    "Mystic Shield",
+		Trigger: rules.BeforeSaveRoll,
+		Source:  rules.SourceUnitAbility,
+		Apply:   func(ctx *rules.Context) { ctx.Modifiers.SaveMod++ },
+	})
+	engine.AddRule(rules.Rule{
+		Name:    "Amulet of Destiny",
+		Trigger: rules.BeforeWardSave,
+		Source:  rules.SourceEnhancement,
+		Apply:   func(ctx *rules.Context) { ctx.WardOverride = 5 },
+	})
+
+	attacker := &core.Unit{
+		ID:      1,
+		Models:  []core.Model{{ID: 0, CurrentWounds: 1, MaxWounds: 1, IsAlive: true}},
+		Weapons: []core.Weapon{{Name: "Sword", Attacks: 4, ToHit: 3, ToWound: 3, Damage: 1}},
+	}
+	defender := &core.Unit{
+		ID:     2,
+		Stats:  core.Stats{Save: 4, Health: 1},
+		Models: []core.Model{{ID: 0, CurrentWounds: 10, MaxWounds: 10, IsAlive: true}},
+	}
+	result := ResolveAttacks(dice.NewRoller(42), engine, attacker, defender, &attacker.Weapons[0], false)
+
+	want := "+1 hit (All-out Attack), +1 save (Mystic Shield), ward 5+ (Amulet of Destiny)"
+	if got := rules.FormatTrace(result.Trace); got != want {
+		t.Errorf("expected trace %q, got %q", want, got)
+	}
+}
+
+func BenchmarkResolveAttacks(b *testing.B) {
+	engine := rules.NewEngine()
+	engine.AddRule(rules.Rule{
+		Name: "Mystic Shield", Trigger: rules.BeforeSaveRoll, Source: rules.SourceGlobal,
+		Condition: func(ctx *rules.Context) bool { return ctx.Defender.ID == 2 },
+		Apply:     func(ctx *rules.Context) { ctx.Modifiers.SaveMod++ },
+	})
+	attacker := &core.Unit{ID: 1, Models: []core.Model{{ID: 0, CurrentWounds: 1, MaxWounds: 1, IsAlive: true}}}
+	weapon := &core.Weapon{Name: "Sword", Attacks: 10, ToHit: 3, ToWound: 4, Rend: 1, Damage: 1}
+	roller := dice.NewRoller(42)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		defender := &core.Unit{ID: 2, Stats: core.Stats{Save: 4},
+			Models: []core.Model{{ID: 0, CurrentWounds: 1000, MaxWounds: 1000, IsAlive: true}}}
+		ResolveAttacks(roller, engine, attacker, defender, weapon, false)
+	}
+}
@@ -1,6 +1,10 @@
 package command
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+)
 
 // CommandType identifies the kind of command.
 type CommandType string
@@ -17,6 +21,8 @@ const (
 	CommandTypeChant               CommandType = "chant"
 	CommandTypeRally               CommandType = "rally"
 	CommandTypeMagicalIntervention CommandType = "magical_intervention"
+	CommandTypeDeclare             CommandType = "declare"
+	CommandTypeTactic              CommandType = "tactic"
 	CommandTypeEndPhase            CommandType = "end_phase"
 )
 
@@ -24,6 +30,7 @@ const (
 type Result struct {
 	Description string
 	Success     bool
+	Trace       []rules.Applied // Rules that applied, with what each changed
 }
 
 func (r Result) String() string {
@@ -0,0 +1,19 @@
+package command
+
+import "github.com/jruiznavarro/wargamestactics/internal/game/core"
+
+// DeclareCommand records where a unit stands on a physical battlefield, for
+// games played without coordinates: engaged with an enemy unit, on an
+// objective, or clear of both when neither is set. The game moves the unit
+// to match. Declarations are bookkeeping, allowed in any phase and for
+// either player's units.
+type DeclareCommand struct {
+	OwnerID     int
+	UnitID      core.UnitID
+	EngagedWith core.UnitID // Enemy unit it is engaged with (0 = none)
+	Objective   int         // Objective ID it is on (0 = none)
+}
+
+func (c *DeclareCommand) Type() CommandType      { return CommandTypeDeclare }
+func (c *DeclareCommand) PlayerID() int          { return c.OwnerID }
+func (c *DeclareCommand) GetUnitID() core.UnitID { return c.UnitID }
@@ -0,0 +1,13 @@
+package command
+
+// TacticCommand picks a player's battle tactic for the battle round.
+// Card is a battle tactic card ID (1-6) and Tier 0-2 (Affray, Strike,
+// Domination). Allowed in any phase.
+type TacticCommand struct {
+	OwnerID int
+	Card    int
+	Tier    int
+}
+
+func (c *TacticCommand) Type() CommandType { return CommandTypeTactic }
+func (c *TacticCommand) PlayerID() int     { return c.OwnerID }
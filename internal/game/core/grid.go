@@ -0,0 +1,211 @@
+package core
+
+import (
+	"math"
+	"sort"
+)
+
+// Grid is a spatial index of units. Units are bucketed by Unit.Position()
+// into square cells, so proximity queries only look at the units in nearby
+// cells rather than every unit on the battlefield. Call Update whenever a
+// unit's models move; destroyed units are skipped by every query.
+type Grid struct {
+	cellSize float64
+	cells    map[gridCell][]*Unit
+	units    map[UnitID]gridCell
+
+	// Bounding box of the cells that have held units, which limits how far
+	// Nearest searches.
+	min, max gridCell
+
+	reach float64
+}
+
+type gridCell struct{ X, Y int }
+
+// NewGrid creates an empty grid with cells cellSize inches square.
+func NewGrid(cellSize float64) *Grid {
+	return &Grid{
+		cellSize: cellSize,
+		cells:    make(map[gridCell][]*Unit),
+		units:    make(map[UnitID]gridCell),
+	}
+}
+
+func (g *Grid) cellOf(p Position) gridCell {
+	return gridCell{int(math.Floor(p.X / g.cellSize)), int(math.Floor(p.Y / g.cellSize))}
+}
+
+// Update indexes u at its current position, moving it between cells if it
+// has moved since it was last indexed.
+func (g *Grid) Update(u *Unit) {
+	pos := u.Position()
+	for i := range u.Models {
+		if d := Distance(pos, u.Models[i].Position); u.Models[i].IsAlive && d > g.reach {
+			g.reach = d
+		}
+	}
+
+	c := g.cellOf(pos)
+	if old, ok := g.units[u.ID]; ok {
+		if old == c {
+			return
+		}
+		g.removeFromCell(old, u.ID)
+	}
+	g.cells[c] = append(g.cells[c], u)
+	if len(g.units) == 0 {
+		g.min, g.max = c, c
+	}
+	g.units[u.ID] = c
+	g.min = gridCell{min(g.min.X, c.X), min(g.min.Y, c.Y)}
+	g.max = gridCell{max(g.max.X, c.X), max(g.max.Y, c.Y)}
+}
+
+// Remove drops a unit from the grid.
+func (g *Grid) Remove(id UnitID) {
+	if c, ok := g.units[id]; ok {
+		g.removeFromCell(c, id)
+		delete(g.units, id)
+	}
+}
+
+func (g *Grid) removeFromCell(c gridCell, id UnitID) {
+	cell := g.cells[c]
+	for i, u := range cell {
+		if u.ID == id {
+			cell[i] = cell[len(cell)-1]
+			cell = cell[:len(cell)-1]
+			break
+		}
+	}
+	if len(cell) == 0 {
+		delete(g.cells, c)
+	} else {
+		g.cells[c] = cell
+	}
+}
+
+// Reach returns the furthest any alive model has been from its unit's
+// position when the unit was indexed. Queries that count every model, not
+// just the unit's position, widen their range by it.
+func (g *Grid) Reach() float64 {
+	if g == nil {
+		return 0
+	}
+	return g.reach
+}
+
+// Units returns every unit that is not destroyed, ordered by ID.
+func (g *Grid) Units() []*Unit {
+	if g == nil {
+		return nil
+	}
+	units := make([]*Unit, 0, len(g.units))
+	for _, cell := range g.cells {
+		for _, u := range cell {
+			if !u.IsDestroyed() {
+				units = append(units, u)
+			}
+		}
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i].ID < units[j].ID })
+	return units
+}
+
+// Any reports whether a unit within r of p satisfies match. A nil match
+// accepts any unit.
+func (g *Grid) Any(p Position, r float64, match func(u *Unit) bool) bool {
+	found := false
+	g.each(p, r, func(u *Unit) bool {
+		if match == nil || match(u) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// Within returns the units within r of p, ordered by ID.
+func (g *Grid) Within(p Position, r float64) []*Unit {
+	var units []*Unit
+	g.each(p, r, func(u *Unit) bool {
+		units = append(units, u)
+		return true
+	})
+	sort.Slice(units, func(i, j int) bool { return units[i].ID < units[j].ID })
+	return units
+}
+
+// each calls fn for every unit within r of p until fn returns false.
+func (g *Grid) each(p Position, r float64, fn func(u *Unit) bool) {
+	if g == nil || len(g.units) == 0 {
+		return
+	}
+	lo := g.cellOf(Position{X: p.X - r, Y: p.Y - r})
+	hi := g.cellOf(Position{X: p.X + r, Y: p.Y + r})
+	lo = gridCell{max(lo.X, g.min.X), max(lo.Y, g.min.Y)}
+	hi = gridCell{min(hi.X, g.max.X), min(hi.Y, g.max.Y)}
+	for x := lo.X; x <= hi.X; x++ {
+		for y := lo.Y; y <= hi.Y; y++ {
+			for _, u := range g.cells[gridCell{x, y}] {
+				if u.IsDestroyed() || Distance(p, u.Position()) > r {
+					continue
+				}
+				if !fn(u) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Nearest returns the unit nearest to p that satisfies match (a nil match
+// accepts any unit) and its distance, or nil if there is none. Ties go to
+// the unit with the lowest ID.
+func (g *Grid) Nearest(p Position, match func(u *Unit) bool) (*Unit, float64) {
+	if g == nil || len(g.units) == 0 {
+		return nil, 0
+	}
+	var best *Unit
+	bestDist := math.MaxFloat64
+	consider := func(u *Unit) {
+		if u.IsDestroyed() || (match != nil && !match(u)) {
+			return
+		}
+		d := Distance(p, u.Position())
+		if d < bestDist || (d == bestDist && u.ID < best.ID) {
+			best, bestDist = u, d
+		}
+	}
+	visit := func(c gridCell) {
+		for _, u := range g.cells[c] {
+			consider(u)
+		}
+	}
+
+	// Search rings of cells outwards from p's cell. Every cell in ring k is
+	// at least (k-1) cells away from p, so the search stops once that is
+	// further than the best unit found or the rings cover every occupied cell.
+	c := g.cellOf(p)
+	last := max(c.X-g.min.X, g.max.X-c.X, c.Y-g.min.Y, g.max.Y-c.Y)
+	for k := 0; k <= last; k++ {
+		if best != nil && float64(k-1)*g.cellSize > bestDist {
+			break
+		}
+		for x := c.X - k; x <= c.X+k; x++ {
+			visit(gridCell{x, c.Y - k})
+			if k > 0 {
+				visit(gridCell{x, c.Y + k})
+			}
+		}
+		for y := c.Y - k + 1; y < c.Y+k; y++ {
+			visit(gridCell{c.X - k, y})
+			visit(gridCell{c.X + k, y})
+		}
+	}
+	if best == nil {
+		return nil, 0
+	}
+	return best, bestDist
+}
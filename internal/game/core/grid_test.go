@@ -0,0 +1,90 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func gridUnit(id UnitID, owner int, x, y float64) *Unit {
+	return &Unit{ID: id, OwnerID: owner, Models: []Model{
+		{ID: 0, Position: Position{X: x, Y: y}, IsAlive: true, CurrentWounds: 1, MaxWounds: 1},
+	}}
+}
+
+func unitIDs(units []*Unit) []UnitID {
+	var ids []UnitID
+	for _, u := range units {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func TestGrid_Within(t *testing.T) {
+	g := NewGrid(6)
+	units := []*Unit{
+		gridUnit(3, 1, 10, 10),
+		gridUnit(1, 2, 12.5, 10), // Same cell
+		gridUnit(2, 2, 13, 10),   // Next cell, exactly 3" away
+		gridUnit(4, 2, 14, 10),   // 4" away
+		gridUnit(5, 2, -2, -2),   // Negative coordinates
+	}
+	for _, u := range units {
+		g.Update(u)
+	}
+
+	got := unitIDs(g.Within(Position{X: 10, Y: 10}, 3))
+	if want := []UnitID{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("expected %v within 3\", got %v", want, got)
+	}
+	if got := unitIDs(g.Within(Position{X: 0, Y: 0}, 3)); !slices.Equal(got, []UnitID{5}) {
+		t.Errorf("expected the unit at negative coordinates, got %v", got)
+	}
+
+	// Moving a unit re-indexes it; destroyed units are skipped.
+	units[3].Models[0].Position = Position{X: 40, Y: 40}
+	g.Update(units[3])
+	units[1].Models[0].IsAlive = false
+	if got := unitIDs(g.Within(Position{X: 10, Y: 10}, 5)); !slices.Equal(got, []UnitID{2, 3}) {
+		t.Errorf("expected 2 and 3 after the move and the kill, got %v", got)
+	}
+	if !g.Any(Position{X: 40, Y: 40}, 0.5, nil) {
+		t.Error("expected the moved unit at its new position")
+	}
+
+	g.Remove(3)
+	if g.Any(Position{X: 10, Y: 10}, 1, nil) {
+		t.Error("expected the removed unit to be gone")
+	}
+	if len(g.Units()) != 3 {
+		t.Errorf("expected 3 units left, got %v", unitIDs(g.Units()))
+	}
+}
+
+func TestGrid_Nearest(t *testing.T) {
+	g := NewGrid(6)
+	for _, u := range []*Unit{
+		gridUnit(1, 1, 0, 0),
+		gridUnit(2, 2, 50, 50),
+		gridUnit(3, 2, 30, 0),
+		gridUnit(4, 2, 0, 30), // As near as unit 3
+	} {
+		g.Update(u)
+	}
+	enemy := func(u *Unit) bool { return u.OwnerID != 1 }
+
+	u, d := g.Nearest(Position{X: 0, Y: 0}, enemy)
+	if u == nil || u.ID != 3 || d != 30 {
+		t.Errorf("expected unit 3 at 30\" (lowest ID of a tie), got %v %v", u, d)
+	}
+	if u, _ := g.Nearest(Position{X: 100, Y: 100}, nil); u == nil || u.ID != 2 {
+		t.Errorf("expected unit 2 from far outside the occupied cells, got %v", u)
+	}
+	if u, _ := g.Nearest(Position{}, func(u *Unit) bool { return u.OwnerID == 3 }); u != nil {
+		t.Errorf("expected no match, got %v", u)
+	}
+
+	var empty *Grid
+	if u, _ := empty.Nearest(Position{}, nil); u != nil || empty.Any(Position{}, 10, nil) || empty.Units() != nil {
+		t.Error("expected a nil grid to be empty")
+	}
+}
@@ -33,3 +33,17 @@ func (p Position) Towards(target Position, dist float64) Position {
 		Y: p.Y + (target.Y-p.Y)*ratio,
 	}
 }
+
+// DistanceToSegment returns the shortest distance from p to the line segment
+// from a to b.
+func DistanceToSegment(p, a, b Position) float64 {
+	dx := b.X - a.X
+	dy := b.Y - a.Y
+	lenSq := dx*dx + dy*dy
+	if lenSq < 1e-18 {
+		return Distance(p, a)
+	}
+	t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / lenSq
+	t = math.Max(0, math.Min(1, t))
+	return Distance(p, Position{X: a.X + t*dx, Y: a.Y + t*dy})
+}
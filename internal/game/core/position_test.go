@@ -53,3 +53,28 @@ func TestPosition_Add(t *testing.T) {
 		t.Errorf("expected (4.5, 2.0), got (%f, %f)", result.X, result.Y)
 	}
 }
+
+func TestDistanceToSegment(t *testing.T) {
+	a, b := Position{X: 0, Y: 0}, Position{X: 10, Y: 0}
+	tests := []struct {
+		name string
+		p    Position
+		want float64
+	}{
+		{"on segment", Position{X: 5, Y: 0}, 0},
+		{"beside middle", Position{X: 5, Y: 2}, 2},
+		{"past end", Position{X: 13, Y: 4}, 5},
+		{"before start", Position{X: -3, Y: 0}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistanceToSegment(tt.p, a, b)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("DistanceToSegment(%v) = %f, want %f", tt.p, got, tt.want)
+			}
+		})
+	}
+	if got := DistanceToSegment(Position{X: 3, Y: 4}, a, a); math.Abs(got-5) > 1e-9 {
+		t.Errorf("degenerate segment: got %f, want 5", got)
+	}
+}
@@ -42,6 +42,9 @@ type Unit struct {
 	WardSave       int         // Ward save value (0 = none, 6 = 6+, 5 = 5+)
 	StrikeOrder    StrikeOrder // Determines combat activation priority
 	IsGeneral      bool        // True if this unit is the army general
+	Enhancements   []string    // Names of heroic traits/artefacts carried by this unit
+	WarscrollID    string      // Warscroll the unit was built from ("" if not from a roster)
+	Points         int         // Points the unit cost in its roster
 
 	// Magic (AoS4 Rule 19.0 / 19.2)
 	Spells       []Spell  // Known spells (warscroll/faction specific)
@@ -0,0 +1,219 @@
+package game
+
+import (
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/pkg/dice"
+)
+
+// RollKind identifies a roll that a player may replace with stored dice.
+type RollKind int
+
+const (
+	RollHit RollKind = iota
+	RollWound
+	RollSave
+	RollCasting
+	RollCharge
+	RollRun
+)
+
+func (k RollKind) String() string {
+	switch k {
+	case RollHit:
+		return "hit"
+	case RollWound:
+		return "wound"
+	case RollSave:
+		return "save"
+	case RollCasting:
+		return "casting"
+	case RollCharge:
+		return "charge"
+	case RollRun:
+		return "run"
+	default:
+		return "unknown"
+	}
+}
+
+// stream returns the dice stream rolls of this kind are made on. Run
+// rolls use the main stream.
+func (k RollKind) stream() string {
+	switch k {
+	case RollCasting:
+		return dice.StreamMagic
+	case RollCharge:
+		return dice.StreamCharge
+	case RollHit, RollWound, RollSave:
+		return dice.StreamCombat
+	default:
+		return ""
+	}
+}
+
+// DicePool is a player's store of pre-rolled dice that can be used in place
+// of rolling (Tzeentch Masters of Destiny). army.DestinyDicePool implements it.
+type DicePool interface {
+	Values() []int
+	UseValue(value int) bool
+	AddDie(value int)
+}
+
+// DiceRequest describes a roll that the owner of a dice pool is about to make.
+type DiceRequest struct {
+	Kind   RollKind
+	UnitID core.UnitID // Unit making the roll
+	Dice   int         // Number of dice about to be rolled
+	Target int         // Value each die needs; for 2D6 rolls, the total needed
+	Pool   []int       // Stored dice available
+}
+
+// StoredDiceChooser is implemented by players that decide when to spend
+// stored dice. Players that don't implement it never spend them.
+type StoredDiceChooser interface {
+	// ChooseStoredDice returns the stored values to use in place of the
+	// first dice of the roll, or nil to roll normally. Values that are not
+	// in the pool, or beyond req.Dice, are ignored.
+	ChooseStoredDice(view *GameView, req DiceRequest) []int
+}
+
+// SetDestinyDice gives a player a stored dice pool; count dice are rolled
+// into it by RollDestinyDice at the start of the battle.
+func (g *Game) SetDestinyDice(playerID int, pool DicePool, count int) {
+	if g.DestinyDice == nil {
+		g.DestinyDice = make(map[int]DicePool)
+		g.destinyRolls = make(map[int]int)
+	}
+	g.DestinyDice[playerID] = pool
+	g.destinyRolls[playerID] = count
+}
+
+// RollDestinyDice fills each stored dice pool with its starting dice.
+// RunGame calls it once before the first battle round.
+func (g *Game) RollDestinyDice() {
+	for _, p := range g.Players {
+		count := g.destinyRolls[p.ID()]
+		if count == 0 {
+			continue
+		}
+		rolls := g.rollFor("", "destiny dice", count).RollMultipleD6(count)
+		for _, r := range rolls {
+			g.DestinyDice[p.ID()].AddDie(r)
+		}
+		delete(g.destinyRolls, p.ID())
+		g.Logf("  %s rolls %d Destiny Dice: %v", p.Name(), count, rolls)
+	}
+}
+
+// AddDestinyDie adds a die to a player's stored dice pool. Returns false if
+// the player has no pool.
+func (g *Game) AddDestinyDie(playerID int, value int) bool {
+	pool, ok := g.DestinyDice[playerID]
+	if !ok {
+		return false
+	}
+	pool.AddDie(value)
+	g.Logf("  %s adds a Destiny Die (%d) to the pool", g.playerName(playerID), value)
+	return true
+}
+
+// storedDice asks the owner of unit whether to replace up to n dice of a roll
+// with stored dice, and removes the chosen dice from the pool. Returns the
+// values spent, in the order they replace the roll's dice.
+func (g *Game) storedDice(unit *core.Unit, kind RollKind, n, target int) []int {
+	if n <= 0 {
+		return nil
+	}
+	pool, ok := g.DestinyDice[unit.OwnerID]
+	if !ok {
+		return nil
+	}
+	var chooser StoredDiceChooser
+	for _, p := range g.Players {
+		if p.ID() == unit.OwnerID {
+			chooser, _ = p.(StoredDiceChooser)
+			break
+		}
+	}
+	if chooser == nil {
+		return nil
+	}
+	values := pool.Values()
+	if len(values) == 0 {
+		return nil
+	}
+
+	// Build the view only once there is a choice to make.
+	req := DiceRequest{Kind: kind, UnitID: unit.ID, Dice: n, Target: target, Pool: values}
+	var spent []int
+	for _, v := range chooser.ChooseStoredDice(g.View(unit.OwnerID), req) {
+		if len(spent) == n {
+			break
+		}
+		if pool.UseValue(v) {
+			spent = append(spent, v)
+		}
+	}
+	if len(spent) > 0 {
+		g.Logf("    %s spends Destiny Dice %v on %s's %s roll", g.playerName(unit.OwnerID), spent, unit.Name, kind)
+	}
+	return spent
+}
+
+// rollBatch supplies the dice for a batch of rolls: any stored dice the
+// player chose to spend come first, then fresh rolls.
+type rollBatch struct {
+	roller *dice.Roller
+	stored []int
+	kind   RollKind
+	rolled int // Fresh dice rolled so far
+	fresh  int // Fresh dice in the batch
+}
+
+// next returns the next die of the batch and whether it is a stored die.
+// Stored dice are not re-rolled.
+func (b *rollBatch) next() (roll int, stored bool) {
+	if len(b.stored) > 0 {
+		roll, b.stored = b.stored[0], b.stored[1:]
+		return roll, true
+	}
+	b.rolled++
+	b.roller.Label(b.kind.String(), b.rolled, b.fresh)
+	return b.roller.RollD6(), false
+}
+
+// reroll re-rolls the die last returned by next.
+func (b *rollBatch) reroll() int {
+	b.roller.Label(b.kind.String(), 0, b.fresh)
+	return b.roller.RollD6()
+}
+
+// rollFor returns the roller for stream with the next n dice labelled what,
+// for scripted and manual dice.
+func (g *Game) rollFor(stream, what string, n int) *dice.Roller {
+	r := g.Roller.Stream(stream)
+	r.Label(what, 1, n)
+	return r
+}
+
+// rollD6s rolls n dice for unit, letting its owner replace any of them with
+// stored dice first.
+func (g *Game) rollD6s(unit *core.Unit, kind RollKind, n, target int) []int {
+	batch := newRollBatch(g.Roller.Stream(kind.stream()), g.storedDice, unit, kind, n, target)
+	rolls := make([]int, n)
+	for i := range rolls {
+		rolls[i], _ = batch.next()
+	}
+	return rolls
+}
+
+// newRollBatch prepares a batch of n dice for unit, offering its owner the
+// chance to spend stored dice via spend (which may be nil).
+func newRollBatch(roller *dice.Roller, spend spendFunc, unit *core.Unit, kind RollKind, n, target int) *rollBatch {
+	b := &rollBatch{roller: roller, kind: kind}
+	if spend != nil {
+		b.stored = spend(unit, kind, n, target)
+	}
+	b.fresh = n - len(b.stored)
+	return b
+}
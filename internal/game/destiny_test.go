@@ -0,0 +1,168 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/command"
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+)
+
+// testPool is a minimal DicePool.
+type testPool struct{ dice []int }
+
+func (p *testPool) Values() []int { return append([]int(nil), p.dice...) }
+func (p *testPool) AddDie(v int)  { p.dice = append(p.dice, v) }
+func (p *testPool) UseValue(v int) bool {
+	for i, d := range p.dice {
+		if d == v {
+			p.dice = append(p.dice[:i], p.dice[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// choosingPlayer spends the given values for rolls of one kind.
+type choosingPlayer struct {
+	stubPlayer
+	kind     RollKind
+	values   []int
+	requests []DiceRequest
+}
+
+func (c *choosingPlayer) ChooseStoredDice(view *GameView, req DiceRequest) []int {
+	c.requests = append(c.requests, req)
+	if req.Kind != c.kind {
+		return nil
+	}
+	return c.values
+}
+
+func hasLog(g *Game, substr string) bool {
+	for _, l := range g.Log {
+		if strings.Contains(l, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDestinyDice_ChargeUsesStoredDice(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	p1 := &choosingPlayer{stubPlayer: stubPlayer{id: 1, name: "Tzeentch"}, kind: RollCharge, values: []int{6, 6}}
+	g.AddPlayer(p1)
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	pool := &testPool{dice: []int{6, 1, 6}}
+	g.SetDestinyDice(1, pool, 0)
+
+	g.CreateUnit("Chargers", 1, core.Stats{Move: 5, Health: 1}, nil, 1, core.Position{X: 10, Y: 10}, 1.0)
+	g.CreateUnit("Target", 2, core.Stats{Move: 4, Health: 2, Save: 4}, nil, 1, core.Position{X: 21.5, Y: 10}, 1.0)
+
+	result, err := g.ExecuteCommand(&command.ChargeCommand{OwnerID: 1, ChargerID: 1, TargetID: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("expected a charge of 12 to succeed: %s", result.Description)
+	}
+	if len(p1.requests) != 1 || p1.requests[0].Dice != 2 || p1.requests[0].Target != 12 {
+		t.Errorf("expected one 2-dice charge request needing 12, got %+v", p1.requests)
+	}
+	if got := pool.Values(); len(got) != 1 || got[0] != 1 {
+		t.Errorf("expected only the 1 to remain, got %v", got)
+	}
+	if !hasLog(g, "spends Destiny Dice [6 6] on Chargers's charge roll") {
+		t.Errorf("expected spend to be logged, got %v", g.Log)
+	}
+}
+
+func TestDestinyDice_FightSubstitutesHitRolls(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	p1 := &choosingPlayer{stubPlayer: stubPlayer{id: 1, name: "Tzeentch"}, kind: RollHit, values: []int{1, 1, 1, 5}}
+	g.AddPlayer(p1)
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	pool := &testPool{dice: []int{1, 1, 1, 1}}
+	g.SetDestinyDice(1, pool, 0)
+
+	weapons := []core.Weapon{{Name: "Sword", Attacks: 3, ToHit: 3, ToWound: 3, Damage: 1}}
+	g.CreateUnit("Attackers", 1, core.Stats{Move: 5, Save: 4, Health: 1}, weapons, 1, core.Position{X: 10, Y: 10}, 1.0)
+	target := g.CreateUnit("Defenders", 2, core.Stats{Move: 4, Save: 4, Health: 3}, nil, 1, core.Position{X: 11, Y: 10}, 1.0)
+
+	if _, err := g.ExecuteCommand(&command.FightCommand{OwnerID: 1, AttackerID: 1, TargetID: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Models[0].CurrentWounds != 3 {
+		t.Errorf("expected three stored 1s to miss every attack, target has %d wounds", target.Models[0].CurrentWounds)
+	}
+	// The 5 is not in the pool and the fourth value exceeds the roll, so only three dice are spent.
+	if got := pool.Values(); len(got) != 1 {
+		t.Errorf("expected one die left in the pool, got %v", got)
+	}
+}
+
+func TestDestinyDice_RolledAtBattleStartAndInView(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "Tzeentch"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	pool := &testPool{}
+	g.SetDestinyDice(1, pool, 9)
+
+	g.RollDestinyDice()
+	if len(pool.dice) != 9 {
+		t.Fatalf("expected 9 destiny dice, got %d", len(pool.dice))
+	}
+	g.RollDestinyDice()
+	if len(pool.dice) != 9 {
+		t.Errorf("expected the pool to be rolled only once, got %d dice", len(pool.dice))
+	}
+	if !hasLog(g, "Tzeentch rolls 9 Destiny Dice") {
+		t.Errorf("expected the roll to be logged, got %v", g.Log)
+	}
+
+	g.AddDestinyDie(1, 4)
+	view := g.View(1)
+	if len(view.DestinyDice[1]) != 10 {
+		t.Errorf("expected view to show 10 dice, got %v", view.DestinyDice[1])
+	}
+	if _, ok := view.DestinyDice[2]; ok {
+		t.Error("expected no pool for player 2")
+	}
+	if !hasLog(g, "adds a Destiny Die (4)") {
+		t.Errorf("expected the added die to be logged, got %v", g.Log)
+	}
+}
+
+func TestDestinyDice_NotSpentWithoutChooser(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	pool := &testPool{dice: []int{6, 6}}
+	g.SetDestinyDice(1, pool, 0)
+
+	g.CreateUnit("Chargers", 1, core.Stats{Move: 5, Health: 1}, nil, 1, core.Position{X: 10, Y: 10}, 1.0)
+	g.CreateUnit("Target", 2, core.Stats{Move: 4, Health: 2, Save: 4}, nil, 1, core.Position{X: 18, Y: 10}, 1.0)
+	if _, err := g.ExecuteCommand(&command.ChargeCommand{OwnerID: 1, ChargerID: 1, TargetID: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pool.dice) != 2 {
+		t.Errorf("expected the pool to be untouched, got %v", pool.dice)
+	}
+}
+
+func TestDestinyDice_EmptyPoolNotOffered(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	p1 := &choosingPlayer{stubPlayer: stubPlayer{id: 1, name: "Tzeentch"}, kind: RollCharge, values: []int{6, 6}}
+	g.AddPlayer(p1)
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	g.SetDestinyDice(1, &testPool{}, 0)
+
+	g.CreateUnit("Chargers", 1, core.Stats{Move: 5, Health: 1}, nil, 1, core.Position{X: 10, Y: 10}, 1.0)
+	g.CreateUnit("Target", 2, core.Stats{Move: 4, Health: 2, Save: 4}, nil, 1, core.Position{X: 18, Y: 10}, 1.0)
+	if _, err := g.ExecuteCommand(&command.ChargeCommand{OwnerID: 1, ChargerID: 1, TargetID: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(p1.requests) != 0 {
+		t.Errorf("expected no stored dice requests with an empty pool, got %+v", p1.requests)
+	}
+}
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+	"github.com/jruiznavarro/wargamestactics/pkg/dice"
+)
+
+// applyEffects is the rules engine's effect handler: it carries out the game
+// actions rules queued during an evaluation, one at a time in queue order.
+// Effects on units that no longer exist, or were destroyed by an earlier
+// effect, are skipped.
+func (g *Game) applyEffects(effects []rules.Effect) {
+	for _, e := range effects {
+		if g.IsOver {
+			return
+		}
+		if e.RollNeeded > 0 {
+			roll := g.rollFor("", e.Rule, 1).RollD6()
+			if roll < e.RollNeeded {
+				g.Logf("    %s: rolled %d, needed %d+", e.Rule, roll, e.RollNeeded)
+				continue
+			}
+		}
+
+		switch e.Kind {
+		case rules.EffectMortalWounds:
+			target := g.GetUnit(e.UnitID)
+			if target == nil || target.IsDestroyed() || e.Amount <= 0 {
+				continue
+			}
+			dmg, slain := g.applyMortalWounds(nil, target, e.Amount, DamageOther)
+			g.Logf("    %s: %s suffers %d mortal damage (%d slain)", e.Rule, target.Name, dmg, slain)
+			g.CheckVictory()
+		case rules.EffectHeal:
+			target := g.GetUnit(e.UnitID)
+			if target == nil || target.IsDestroyed() {
+				continue
+			}
+			if healed := g.healUnit(target, e.Amount); healed > 0 {
+				g.Logf("    %s: %s heals %d wound(s)", e.Rule, target.Name, healed)
+			}
+		case rules.EffectCommandPoints:
+			state := g.Commands.GetState(e.PlayerID)
+			if state == nil || e.Amount <= 0 {
+				continue
+			}
+			state.CommandPoints += e.Amount
+			g.Logf("    %s: %s receives %d command point(s)", e.Rule, g.playerName(e.PlayerID), e.Amount)
+		case rules.EffectSpawnUnit:
+			s := e.Spawn
+			if s == nil || s.Models <= 0 {
+				continue
+			}
+			u := g.CreateUnit(s.Name, s.OwnerID, s.Stats, s.Weapons, s.Models, s.Position, s.BaseSize)
+			u.Keywords = s.Keywords
+			u.WardSave = s.WardSave
+			g.Logf("    %s: %s is set up at (%.1f, %.1f)", e.Rule, u.Name, s.Position.X, s.Position.Y)
+		case rules.EffectFight:
+			unit, target := g.GetUnit(e.UnitID), g.GetUnit(e.TargetID)
+			if unit == nil || target == nil || target.IsDestroyed() {
+				continue
+			}
+			g.fightImmediately(unit, target, e.Models, e.Rule)
+		}
+	}
+}
+
+// fightImmediately lets unit fight target with its melee weapons outside the
+// normal combat sequence. A unit destroyed by the attack that triggered the
+// effect fights with models, the ones that attack slew, before they are
+// removed; models slain earlier stay dead.
+func (g *Game) fightImmediately(unit, target *core.Unit, models []int, rule string) {
+	if len(unit.MeleeWeapons()) == 0 {
+		return
+	}
+
+	var revived []int
+	if unit.IsDestroyed() {
+		for _, i := range models {
+			if i >= 0 && i < len(unit.Models) && !unit.Models[i].IsAlive {
+				unit.Models[i].IsAlive = true
+				revived = append(revived, i)
+			}
+		}
+		if len(revived) == 0 {
+			return
+		}
+	}
+	defer func() {
+		for _, i := range revived {
+			unit.Models[i].IsAlive = false
+		}
+	}()
+
+	if core.Distance(unit.Position(), target.Position()) > 3.0 {
+		return
+	}
+	g.Logf("    %s: %s fights immediately", rule, unit.Name)
+	results := resolveCombat(g.Roller.Stream(dice.StreamCombat), g.Rules, unit, target, g.storedDice, g.Grid)
+	unit.HasFought = true
+	damage, slain := 0, 0
+	for _, r := range results {
+		damage += r.DamageDealt
+		slain += r.ModelsSlain
+		g.logCombatResult(unit.Name, target.Name, r)
+	}
+	g.recordDamage(unit, target, DamageMelee, damage, slain)
+}
@@ -0,0 +1,237 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/command"
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+)
+
+func TestEffects_FightOnDeath(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+
+	killer := []core.Weapon{{Name: "Axe", Attacks: 10, ToHit: 2, ToWound: 2, Rend: 3, Damage: 3}}
+	attacker := g.CreateUnit("Killers", 1, core.Stats{Move: 5, Save: 6, Health: 20}, killer, 1, core.Position{X: 10, Y: 10}, 1.0)
+	avenger := []core.Weapon{{Name: "Blade", Attacks: 6, ToHit: 2, ToWound: 2, Rend: 3, Damage: 1}}
+	hero := g.CreateUnit("Hero", 2, core.Stats{Move: 5, Save: 6, Health: 1}, avenger, 1, core.Position{X: 11, Y: 10}, 1.0)
+
+	heroID := hero.ID
+	g.Rules.AddRule(rules.Rule{
+		Name:    "Disciplined Fury",
+		Trigger: rules.OnUnitDestroyed,
+		Source:  rules.SourceEnhancement,
+		Condition: func(ctx *rules.Context) bool {
+			return ctx.Defender.ID == heroID && ctx.Attacker != nil
+		},
+		Apply: func(ctx *rules.Context) {
+			ctx.FightImmediately(ctx.Defender, ctx.Attacker)
+		},
+	})
+
+	if _, err := g.ExecuteCommand(&command.FightCommand{OwnerID: 1, AttackerID: attacker.ID, TargetID: hero.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hero.IsDestroyed() {
+		t.Fatal("expected the hero to be slain")
+	}
+	if attacker.Models[0].CurrentWounds == 20 {
+		t.Error("expected the slain hero to fight back before being removed")
+	}
+	if !hasLog(g, "Disciplined Fury: Hero fights immediately") {
+		t.Errorf("expected the fight to be logged, got %v", g.Log)
+	}
+}
+
+func TestEffects_FightOnDeathOnlySlainModelsFight(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+
+	killer := []core.Weapon{{Name: "Axe", Attacks: 10, ToHit: 2, ToWound: 2, Rend: 3, Damage: 3}}
+	attacker := g.CreateUnit("Killers", 1, core.Stats{Move: 5, Save: 6, Health: 20}, killer, 1, core.Position{X: 10, Y: 10}, 1.0)
+	avenger := []core.Weapon{{Name: "Blade", Attacks: 1, ToHit: 2, ToWound: 2, Rend: 3, Damage: 1}}
+	guard := g.CreateUnit("Guard", 2, core.Stats{Move: 5, Save: 6, Health: 1}, avenger, 5, core.Position{X: 11, Y: 10}, 1.0)
+	guard.AllocateDamage(3) // Three of the five were slain earlier
+
+	guardID := guard.ID
+	var fights []rules.Effect
+	g.Rules.AddRule(rules.Rule{
+		Name:    "Disciplined Fury",
+		Trigger: rules.OnUnitDestroyed,
+		Source:  rules.SourceEnhancement,
+		Condition: func(ctx *rules.Context) bool {
+			return ctx.Defender.ID == guardID && ctx.Attacker != nil
+		},
+		Apply: func(ctx *rules.Context) {
+			ctx.FightImmediately(ctx.Defender, ctx.Attacker)
+			fights = append(fights, ctx.Effects...)
+		},
+	})
+
+	if _, err := g.ExecuteCommand(&command.FightCommand{OwnerID: 1, AttackerID: attacker.ID, TargetID: guard.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !guard.IsDestroyed() {
+		t.Fatal("expected the guard to be destroyed")
+	}
+	if len(fights) != 1 || !reflect.DeepEqual(fights[0].Models, []int{3, 4}) {
+		t.Fatalf("expected the two models slain by the attack to fight, got %+v", fights)
+	}
+	if !hasLog(g, "Guard -> Killers [Blade]") || !hasLog(g, "      2 attacks --(hit)") {
+		t.Errorf("expected 2 attacks from the 2 models just slain, got %v", g.Log)
+	}
+}
+
+func TestEffects_ChainedDestruction(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	bomb := g.CreateUnit("Bomb", 1, core.Stats{Health: 1}, nil, 1, core.Position{X: 10, Y: 10}, 1.0)
+	g.CreateUnit("Victim", 2, core.Stats{Health: 2}, nil, 1, core.Position{X: 12, Y: 10}, 1.0)
+	g.CreateUnit("Bystander", 2, core.Stats{Health: 5}, nil, 1, core.Position{X: 30, Y: 10}, 1.0)
+
+	bombID := bomb.ID
+	g.Rules.AddRule(rules.Rule{
+		Name:    "Explode",
+		Trigger: rules.OnUnitDestroyed,
+		Source:  rules.SourceUnitAbility,
+		Condition: func(ctx *rules.Context) bool {
+			return ctx.Defender.ID == bombID
+		},
+		Apply: func(ctx *rules.Context) {
+			for _, u := range ctx.AllUnits {
+				if u.OwnerID != 1 && core.Distance(u.Position(), core.Position{X: 10, Y: 10}) <= 3 {
+					ctx.DealMortalWounds(u, 2)
+				}
+			}
+		},
+	})
+
+	g.applyMortalWounds(nil, bomb, 1, DamageOther)
+	if !hasLog(g, "Explode: Victim suffers 2 mortal damage (1 slain)") {
+		t.Errorf("expected the explosion to slay the victim, got %v", g.Log)
+	}
+	if hasLog(g, "Bystander") {
+		t.Error("expected the distant unit to be untouched")
+	}
+}
+
+func TestEffects_SpawnAndRollNeeded(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	g.Commands.InitRound([]int{1, 2}, 4, -1)
+
+	g.applyEffects([]rules.Effect{
+		{Kind: rules.EffectSpawnUnit, Rule: "Split", Spawn: &rules.SpawnSpec{
+			Name: "Blue Horrors", OwnerID: 1, Stats: core.Stats{Health: 1, Save: 5}, Models: 5,
+			Position: core.Position{X: 20, Y: 12}, BaseSize: 1.0, WardSave: 6,
+		}},
+		{Kind: rules.EffectCommandPoints, Rule: "Never", PlayerID: 1, Amount: 1, RollNeeded: 7},
+		{Kind: rules.EffectCommandPoints, Rule: "Always", PlayerID: 2, Amount: 2, RollNeeded: 1},
+	})
+
+	spawned := g.UnitsForPlayer(1)
+	if len(spawned) != 1 || spawned[0].Name != "Blue Horrors" || spawned[0].AliveModels() != 5 || spawned[0].WardSave != 6 {
+		t.Fatalf("expected 5 Blue Horrors with ward 6+, got %+v", spawned)
+	}
+	if cp := g.Commands.GetState(1).CommandPoints; cp != 4 {
+		t.Errorf("expected an effect needing 7+ never to happen, player 1 has %d CP", cp)
+	}
+	if cp := g.Commands.GetState(2).CommandPoints; cp != 6 {
+		t.Errorf("expected player 2 to receive 2 CP, has %d", cp)
+	}
+	if !hasLog(g, "Never: rolled") {
+		t.Errorf("expected the failed roll to be logged, got %v", g.Log)
+	}
+}
+
+func TestEffects_AfterMoveAndAfterCast(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	g.Commands.InitRound([]int{1, 2}, 4, -1)
+	flyer := g.CreateUnit("Screamers", 1, core.Stats{Move: 16, Health: 3}, nil, 1, core.Position{X: 5, Y: 10}, 1.0)
+	enemy := g.CreateUnit("Enemies", 2, core.Stats{Health: 3}, nil, 1, core.Position{X: 12, Y: 10.5}, 1.0)
+
+	var moved []float64
+	g.Rules.AddRule(rules.Rule{
+		Name:    "Slashing Fins",
+		Trigger: rules.AfterMove,
+		Source:  rules.SourceUnitAbility,
+		Apply: func(ctx *rules.Context) {
+			moved = append(moved, ctx.Distance)
+			if core.DistanceToSegment(enemy.Position(), ctx.Origin, ctx.Destination) <= 1 {
+				ctx.DealMortalWounds(enemy, 1)
+			}
+		},
+	})
+	if _, err := g.ExecuteCommand(&command.MoveCommand{OwnerID: 1, UnitID: flyer.ID, Destination: core.Position{X: 20, Y: 10}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(moved) != 1 || moved[0] != 15 {
+		t.Errorf("expected one AfterMove evaluation of 15\", got %v", moved)
+	}
+	if enemy.Models[0].CurrentWounds != 2 {
+		t.Errorf("expected the unit passed across to suffer 1 mortal wound, has %d wounds", enemy.Models[0].CurrentWounds)
+	}
+
+	wizard := g.CreateUnitFromSpec("Wizard", 2, core.Stats{Health: 5}, nil, 1, core.Position{X: 30, Y: 10}, 1.0,
+		[]core.Keyword{core.KeywordWizard, core.KeywordHero}, 0, 1,
+		[]core.Spell{{Name: "Bolt", CastingValue: 2, Range: 18, Effect: core.SpellEffectDamage, EffectValue: 1}}, nil)
+	casts := 0
+	g.Rules.AddRule(rules.Rule{
+		Name:    "Aetherquartz Brooch",
+		Trigger: rules.AfterCast,
+		Source:  rules.SourceEnhancement,
+		Condition: func(ctx *rules.Context) bool {
+			return ctx.Attacker.ID == wizard.ID
+		},
+		Apply: func(ctx *rules.Context) { casts++ },
+	})
+	if _, err := g.ExecuteCommand(&command.CastCommand{OwnerID: 2, CasterID: wizard.ID, SpellIndex: 0, TargetID: flyer.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if casts != 1 && !wizard.HasMiscast {
+		t.Errorf("expected AfterCast to fire once for a successful cast, got %d", casts)
+	}
+}
+
+func TestExecuteCommand_TraceAndVerboseLog(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	g.Verbose = true
+	charger := g.CreateUnit("Chargers", 1, core.Stats{Move: 5, Health: 1}, nil, 1, core.Position{X: 10, Y: 10}, 1.0)
+	target := g.CreateUnit("Target", 2, core.Stats{Move: 4, Health: 2, Save: 4}, nil, 1, core.Position{X: 14, Y: 10}, 1.0)
+
+	if v := g.View(1); v.LastResult != "" || v.LastTrace != nil {
+		t.Errorf("expected no last result before any command, got %q %v", v.LastResult, v.LastTrace)
+	}
+
+	g.Rules.AddRule(rules.Rule{
+		Name:    "Swift",
+		Trigger: rules.BeforeCharge,
+		Source:  rules.SourceUnitAbility,
+		Apply:   func(ctx *rules.Context) { ctx.Modifiers.ChargeMod += 2 },
+	})
+	result, err := g.ExecuteCommand(&command.ChargeCommand{OwnerID: 1, ChargerID: charger.ID, TargetID: target.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Trace) != 1 || result.Trace[0].Rule != "Swift" || result.Trace[0].Delta.ChargeMod != 2 {
+		t.Errorf("expected the charge bonus in the trace, got %+v", result.Trace)
+	}
+	if !hasLog(g, "why: +2 charge (Swift)") {
+		t.Errorf("expected a verbose why line, got %v", g.Log)
+	}
+
+	view := g.View(1)
+	if view.LastResult != result.Description || len(view.LastTrace) != 1 {
+		t.Errorf("expected the view to carry the last result and trace, got %q %v", view.LastResult, view.LastTrace)
+	}
+}
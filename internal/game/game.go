@@ -3,6 +3,7 @@ package game
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math"
 
 	"github.com/jruiznavarro/wargamestactics/internal/game/board"
@@ -18,6 +19,7 @@ import (
 type Game struct {
 	Board          *board.Board
 	Units          map[core.UnitID]*core.Unit
+	Grid           *core.Grid // Spatial index of Units, kept up to date by CreateUnit and placeUnit
 	Players        []Player
 	Roller         *dice.Roller
 	Rules          *rules.Engine
@@ -53,13 +55,38 @@ type Game struct {
 
 	// GH 2025-26: Seize the Initiative
 	PreviousSecondPlayer int // Player index who went second in the previous round (-1 = first round)
+
+	// Verbose adds a "why" line after each action listing the rules that
+	// applied to it.
+	Verbose bool
+	// LogOutput, if set, also receives each log line as it is written, for
+	// games followed live.
+	LogOutput io.Writer
+	// LastResult is the result of the most recent command (nil before any).
+	LastResult *command.Result
+
+	// Stored dice pools (Tzeentch Masters of Destiny)
+	DestinyDice  map[int]DicePool // playerID -> pool
+	destinyRolls map[int]int      // playerID -> dice still to roll at battle start
+
+	// Where units were before their first declared position (tabletop.go)
+	deployedAt map[core.UnitID]core.Position
+
+	// Telemetry, if set, records what each unit does (telemetry.go).
+	Telemetry *Telemetry
+	// Invariants, if set, checks the rules hold after every command and
+	// phase transition (invariants.go).
+	Invariants *InvariantChecker
+	// The objective each unit contests, from the last control calculation
+	contesting map[core.UnitID]int
 }
 
 // NewGame creates a new game with the given seed and board dimensions.
 func NewGame(seed int64, boardWidth, boardHeight float64) *Game {
-	return &Game{
+	g := &Game{
 		Board:                     board.NewBoard(boardWidth, boardHeight),
 		Units:                     make(map[core.UnitID]*core.Unit),
+		Grid:                      core.NewGrid(gridCellSize),
 		Roller:                    dice.NewRoller(seed),
 		Rules:                     rules.NewEngine(),
 		Commands:                  commands.NewCommandTracker(),
@@ -74,6 +101,8 @@ func NewGame(seed int64, boardWidth, boardHeight float64) *Game {
 		UnitsDestroyedThisTurnMap: make(map[int]int),
 		PreviousSecondPlayer:      -1,
 	}
+	g.Rules.SetEffectHandler(g.applyEffects)
+	return g
 }
 
 // NewGameFromBattleplan creates a game configured with a specific battleplan (GH 2025-26).
@@ -82,6 +111,7 @@ func NewGameFromBattleplan(seed int64, bp *board.Battleplan) *Game {
 	g := &Game{
 		Board:                     bp.SetupBoard(),
 		Units:                     make(map[core.UnitID]*core.Unit),
+		Grid:                      core.NewGrid(gridCellSize),
 		Roller:                    dice.NewRoller(seed),
 		Rules:                     rules.NewEngine(),
 		Commands:                  commands.NewCommandTracker(),
@@ -97,6 +127,7 @@ func NewGameFromBattleplan(seed int64, bp *board.Battleplan) *Game {
 		PreviousSecondPlayer:      -1,
 		Battleplan:         bp,
 	}
+	g.Rules.SetEffectHandler(g.applyEffects)
 	return g
 }
 
@@ -110,14 +141,14 @@ func (g *Game) SetBattleplan(bp *board.Battleplan) {
 
 // RandomBattleplan selects a random battleplan by rolling on both tables.
 func (g *Game) RandomBattleplan() *board.Battleplan {
-	tableRoll := g.Roller.RollD6()
+	tableRoll := g.rollFor("", "battleplan table", 1).RollD6()
 	var table board.BattleplanTable
 	if tableRoll <= 3 {
 		table = board.BattleplanTable1
 	} else {
 		table = board.BattleplanTable2
 	}
-	planRoll := g.Roller.RollD6()
+	planRoll := g.rollFor("", "battleplan", 1).RollD6()
 	bp := board.GetBattleplan(table, planRoll)
 	g.Logf("Battleplan roll: Table %d (roll %d), Plan %d → %s", table, tableRoll, planRoll, bp.Name)
 	return bp
@@ -163,6 +194,7 @@ func (g *Game) CreateUnit(name string, ownerID int, stats core.Stats, weapons []
 	}
 
 	g.Units[id] = unit
+	g.Grid.Update(unit)
 	return unit
 }
 
@@ -186,7 +218,7 @@ func (g *Game) GetUnit(id core.UnitID) *core.Unit {
 // UnitsForPlayer returns all non-destroyed units belonging to a player.
 func (g *Game) UnitsForPlayer(playerID int) []*core.Unit {
 	var units []*core.Unit
-	for _, u := range g.Units {
+	for _, u := range g.unitsInOrder() {
 		if u.OwnerID == playerID && !u.IsDestroyed() {
 			units = append(units, u)
 		}
@@ -198,7 +230,7 @@ func (g *Game) UnitsForPlayer(playerID int) []*core.Unit {
 func (g *Game) View(playerID int) *GameView {
 	unitsByOwner := make(map[int][]UnitView)
 
-	for _, u := range g.Units {
+	for _, u := range g.unitsInOrder() {
 		if u.IsDestroyed() {
 			continue
 		}
@@ -269,6 +301,7 @@ func (g *Game) View(playerID int) *GameView {
 			Prayers:       prayerViews,
 			CanCast:       u.CanCast(),
 			CanChant:      u.CanChant(),
+			Enhancements:  append([]string(nil), u.Enhancements...),
 		}
 		unitsByOwner[u.OwnerID] = append(unitsByOwner[u.OwnerID], view)
 	}
@@ -343,6 +376,21 @@ func (g *Game) View(playerID int) *GameView {
 		vpMap[p.ID()] = g.VictoryPoints[p.ID()]
 	}
 
+	var destinyMap map[int][]int
+	if len(g.DestinyDice) > 0 {
+		destinyMap = make(map[int][]int)
+		for id, pool := range g.DestinyDice {
+			destinyMap[id] = pool.Values()
+		}
+	}
+
+	var lastResult string
+	var lastTrace []rules.Applied
+	if g.LastResult != nil {
+		lastResult = g.LastResult.Description
+		lastTrace = append(lastTrace, g.LastResult.Trace...)
+	}
+
 	bpName := ""
 	if g.Battleplan != nil {
 		bpName = g.Battleplan.Name
@@ -422,6 +470,9 @@ func (g *Game) View(playerID int) *GameView {
 		CommandPoints:   cpMap,
 		VictoryPoints:   vpMap,
 		BattleTactics:   btViews,
+		DestinyDice:     destinyMap,
+		LastResult:      lastResult,
+		LastTrace:       lastTrace,
 	}
 }
 
@@ -429,6 +480,9 @@ func (g *Game) View(playerID int) *GameView {
 func (g *Game) Logf(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
 	g.Log = append(g.Log, msg)
+	if g.LogOutput != nil {
+		fmt.Fprintln(g.LogOutput, msg)
+	}
 }
 
 func (g *Game) logCombatResult(attackerName, defenderName string, r CombatResult) {
@@ -452,10 +506,28 @@ func (g *Game) logCombatResult(attackerName, defenderName string, r CombatResult
 	} else {
 		g.Logf("      => No damage")
 	}
+	g.logTrace(r.Trace)
 }
 
-// ExecuteCommand validates and executes a command.
+// logTrace logs which rules applied to an action, in verbose mode.
+func (g *Game) logTrace(trace []rules.Applied) {
+	if g.Verbose && len(trace) > 0 {
+		g.Logf("      why: %s", rules.FormatTrace(trace))
+	}
+}
+
+// ExecuteCommand validates and executes a command. The result of the last
+// command executed without error is kept in LastResult.
 func (g *Game) ExecuteCommand(cmd interface{}) (command.Result, error) {
+	result, err := g.executeCommand(cmd)
+	g.checkCommand(cmd, err)
+	if _, endPhase := cmd.(*command.EndPhaseCommand); err == nil && !endPhase {
+		g.LastResult = &result
+	}
+	return result, err
+}
+
+func (g *Game) executeCommand(cmd interface{}) (command.Result, error) {
 	switch c := cmd.(type) {
 	case *command.MoveCommand:
 		return g.executeMove(c)
@@ -479,6 +551,10 @@ func (g *Game) ExecuteCommand(cmd interface{}) (command.Result, error) {
 		return g.executeRally(c)
 	case *command.MagicalInterventionCommand:
 		return g.executeMagicalIntervention(c)
+	case *command.DeclareCommand:
+		return g.executeDeclare(c)
+	case *command.TacticCommand:
+		return g.executeTactic(c)
 	case *command.EndPhaseCommand:
 		return command.Result{Description: "Phase ended", Success: true}, nil
 	default:
@@ -534,16 +610,28 @@ func (g *Game) executeMove(cmd *command.MoveCommand) (command.Result, error) {
 		return command.Result{}, fmt.Errorf("cannot end normal move within 3\" of enemy unit")
 	}
 
-	for i := range unit.Models {
-		if unit.Models[i].IsAlive {
-			unit.Models[i].Position = cmd.Destination
-		}
-	}
+	g.placeUnit(unit, cmd.Destination)
 	unit.HasMoved = true
 
 	desc := fmt.Sprintf("%s moved %.1f\" to (%.1f, %.1f)", unit.Name, dist, cmd.Destination.X, cmd.Destination.Y)
 	g.Logf("%s", desc)
-	return command.Result{Description: desc, Success: true}, nil
+	trace := append(moveCtx.Trace, g.afterMove(unit, origin)...)
+	g.logTrace(trace)
+	return command.Result{Description: desc, Success: true, Trace: trace}, nil
+}
+
+// afterMove evaluates AfterMove rules once unit has finished a move that
+// started at origin, and returns the rules that applied.
+func (g *Game) afterMove(unit *core.Unit, origin core.Position) []rules.Applied {
+	dest := unit.Position()
+	return g.Rules.Evaluate(rules.AfterMove, &rules.Context{
+		Attacker:    unit,
+		Origin:      origin,
+		Destination: dest,
+		Distance:    core.Distance(origin, dest),
+		AllUnits:    g.aliveUnits(),
+		Grid:        g.Grid,
+	}).Trace
 }
 
 // executeRun: AoS4 Rule 14.1. Move + D6" extra. Cannot shoot or charge after.
@@ -580,7 +668,9 @@ func (g *Game) executeRun(cmd *command.RunCommand) (command.Result, error) {
 		return command.Result{}, fmt.Errorf("run blocked: %s", moveCtx.BlockMessage)
 	}
 
-	runRoll := g.Roller.RollD6()
+	runNeed := int(math.Ceil(dist)) - (unit.Stats.Move + moveCtx.Modifiers.MoveMod)
+	remind(g.Roller, "run", moveCtx.Trace)
+	runRoll := g.rollD6s(unit, RollRun, 1, runNeed)[0]
 	maxMove := float64(unit.Stats.Move+moveCtx.Modifiers.MoveMod) + float64(runRoll)
 	if maxMove < 0 {
 		maxMove = 0
@@ -594,17 +684,15 @@ func (g *Game) executeRun(cmd *command.RunCommand) (command.Result, error) {
 		return command.Result{}, fmt.Errorf("cannot end run within 3\" of enemy unit")
 	}
 
-	for i := range unit.Models {
-		if unit.Models[i].IsAlive {
-			unit.Models[i].Position = cmd.Destination
-		}
-	}
+	g.placeUnit(unit, cmd.Destination)
 	unit.HasMoved = true
 	unit.HasRun = true
 
 	desc := fmt.Sprintf("%s ran %.1f\" to (%.1f, %.1f) (roll: %d)", unit.Name, dist, cmd.Destination.X, cmd.Destination.Y, runRoll)
 	g.Logf("%s", desc)
-	return command.Result{Description: desc, Success: true}, nil
+	trace := append(moveCtx.Trace, g.afterMove(unit, origin)...)
+	g.logTrace(trace)
+	return command.Result{Description: desc, Success: true, Trace: trace}, nil
 }
 
 // executeRetreat: AoS4 Rule 14.1. Move from combat, D3 mortal damage.
@@ -639,9 +727,9 @@ func (g *Game) executeRetreat(cmd *command.RetreatCommand) (command.Result, erro
 	}
 
 	// D3 mortal damage for retreating
-	mortalDmg := g.Roller.RollD3()
+	mortalDmg := g.rollFor(dice.StreamCombat, "retreat mortal wounds", 1).RollD3()
 	g.Logf("    %s retreats and suffers %d mortal damage", unit.Name, mortalDmg)
-	ResolveMortalWounds(g.Roller, unit, mortalDmg)
+	g.applyMortalWounds(nil, unit, mortalDmg, DamageOther)
 
 	if unit.IsDestroyed() {
 		desc := fmt.Sprintf("%s was destroyed while retreating!", unit.Name)
@@ -650,17 +738,15 @@ func (g *Game) executeRetreat(cmd *command.RetreatCommand) (command.Result, erro
 		return command.Result{Description: desc, Success: true}, nil
 	}
 
-	for i := range unit.Models {
-		if unit.Models[i].IsAlive {
-			unit.Models[i].Position = cmd.Destination
-		}
-	}
+	g.placeUnit(unit, cmd.Destination)
 	unit.HasMoved = true
 	unit.HasRetreated = true
 
 	desc := fmt.Sprintf("%s retreated %.1f\" to (%.1f, %.1f)", unit.Name, dist, cmd.Destination.X, cmd.Destination.Y)
 	g.Logf("%s", desc)
-	return command.Result{Description: desc, Success: true}, nil
+	trace := g.afterMove(unit, origin)
+	g.logTrace(trace)
+	return command.Result{Description: desc, Success: true, Trace: trace}, nil
 }
 
 func (g *Game) executeShoot(cmd *command.ShootCommand) (command.Result, error) {
@@ -732,7 +818,7 @@ func (g *Game) executeShoot(cmd *command.ShootCommand) (command.Result, error) {
 		}
 	}
 
-	results := ResolveShooting(g.Roller, g.Rules, shooter, target)
+	results := resolveShooting(g.Roller.Stream(dice.StreamCombat), g.Rules, shooter, target, g.storedDice, g.Grid)
 	shooter.HasShot = true
 
 	totalDamage := 0
@@ -742,9 +828,10 @@ func (g *Game) executeShoot(cmd *command.ShootCommand) (command.Result, error) {
 		totalSlain += r.ModelsSlain
 		g.logCombatResult(shooter.Name, target.Name, r)
 	}
+	g.recordDamage(shooter, target, DamageShooting, totalDamage, totalSlain)
 
 	desc := fmt.Sprintf("%s shot at %s: %d damage, %d models slain", shooter.Name, target.Name, totalDamage, totalSlain)
-	return command.Result{Description: desc, Success: true}, nil
+	return command.Result{Description: desc, Success: true, Trace: combatTrace(results)}, nil
 }
 
 func (g *Game) executeFight(cmd *command.FightCommand) (command.Result, error) {
@@ -778,7 +865,7 @@ func (g *Game) executeFight(cmd *command.FightCommand) (command.Result, error) {
 		return command.Result{}, fmt.Errorf("target is not visible (blocked by impassable terrain)")
 	}
 
-	results := ResolveCombat(g.Roller, g.Rules, attacker, target)
+	results := resolveCombat(g.Roller.Stream(dice.StreamCombat), g.Rules, attacker, target, g.storedDice, g.Grid)
 	attacker.HasFought = true
 
 	totalDamage := 0
@@ -788,9 +875,10 @@ func (g *Game) executeFight(cmd *command.FightCommand) (command.Result, error) {
 		totalSlain += r.ModelsSlain
 		g.logCombatResult(attacker.Name, target.Name, r)
 	}
+	g.recordDamage(attacker, target, DamageMelee, totalDamage, totalSlain)
 
 	desc := fmt.Sprintf("%s fought %s: %d damage, %d models slain", attacker.Name, target.Name, totalDamage, totalSlain)
-	return command.Result{Description: desc, Success: true}, nil
+	return command.Result{Description: desc, Success: true, Trace: combatTrace(results)}, nil
 }
 
 func (g *Game) executeCharge(cmd *command.ChargeCommand) (command.Result, error) {
@@ -836,27 +924,66 @@ func (g *Game) executeCharge(cmd *command.ChargeCommand) (command.Result, error)
 		return command.Result{}, fmt.Errorf("charge blocked: %s", chargeCtx.BlockMessage)
 	}
 
-	chargeRoll := g.Roller.Roll2D6() + chargeCtx.Modifiers.ChargeMod
+	chargeNeed := int(math.Ceil(dist)) - chargeCtx.Modifiers.ChargeMod
+	remind(g.Roller, "charge", chargeCtx.Trace)
+	chargeDice := g.rollD6s(charger, RollCharge, 2, chargeNeed)
+	chargeRoll := chargeDice[0] + chargeDice[1] + chargeCtx.Modifiers.ChargeMod
 	g.Logf("Charge roll: %d", chargeRoll)
 
 	if float64(chargeRoll) < dist {
 		charger.HasCharged = true
 		desc := fmt.Sprintf("%s failed charge against %s (rolled %d, needed %.1f\")", charger.Name, target.Name, chargeRoll, dist)
 		g.Logf("%s", desc)
-		return command.Result{Description: desc, Success: false}, nil
+		g.logTrace(chargeCtx.Trace)
+		return command.Result{Description: desc, Success: false, Trace: chargeCtx.Trace}, nil
 	}
 
-	newPos := charger.Position().Towards(target.Position(), dist-0.5)
-	for i := range charger.Models {
-		if charger.Models[i].IsAlive {
-			charger.Models[i].Position = newPos
-		}
+	end, ok := g.chargeEnd(charger, target, float64(chargeRoll))
+	if !ok {
+		charger.HasCharged = true
+		desc := fmt.Sprintf("%s failed charge against %s (no room to end in base contact)", charger.Name, target.Name)
+		g.Logf("%s", desc)
+		g.logTrace(chargeCtx.Trace)
+		return command.Result{Description: desc, Success: false, Trace: chargeCtx.Trace}, nil
 	}
+	g.placeUnit(charger, end)
 	charger.HasCharged = true
 
 	desc := fmt.Sprintf("%s charged %s (rolled %d, needed %.1f\")", charger.Name, target.Name, chargeRoll, dist)
 	g.Logf("%s", desc)
-	return command.Result{Description: desc, Success: true}, nil
+	g.logTrace(chargeCtx.Trace)
+	return command.Result{Description: desc, Success: true, Trace: chargeCtx.Trace}, nil
+}
+
+// chargeEnd returns where a successful charge ends: in base contact with the
+// target on the side facing the charger or, if another enemy unit stands
+// there, the nearest spot around the target within reach that is clear.
+// Returns false if there is no such spot.
+func (g *Game) chargeEnd(charger, target *core.Unit, reach float64) (core.Position, bool) {
+	origin, at := charger.Position(), target.Position()
+	end := origin.Towards(at, core.Distance(origin, at)-baseContact)
+	if !g.standsOnEnemy(charger, target, end) {
+		return end, true
+	}
+	facing := math.Atan2(origin.Y-at.Y, origin.X-at.X)
+	for step := 1; step <= 8; step++ {
+		for _, side := range []float64{1, -1} {
+			a := facing + side*float64(step)*math.Pi/8
+			p := core.Position{X: at.X + baseContact*math.Cos(a), Y: at.Y + baseContact*math.Sin(a)}
+			if core.Distance(origin, p) <= reach && g.Board.IsInBounds(p) && !g.standsOnEnemy(charger, target, p) {
+				return p, true
+			}
+		}
+	}
+	return core.Position{}, false
+}
+
+// standsOnEnemy reports whether u at pos would be closer than base contact
+// to an enemy unit other than except.
+func (g *Game) standsOnEnemy(u, except *core.Unit, pos core.Position) bool {
+	return g.Grid.Any(pos, baseContact-1e-9, func(other *core.Unit) bool {
+		return other.OwnerID != u.OwnerID && other != except
+	})
 }
 
 // ResetTurnFlags resets all unit action flags and per-turn spell tracking.
@@ -895,24 +1022,15 @@ func (g *Game) CheckVictory() {
 func (g *Game) CalculateObjectiveControl() {
 	// Step 1: Assign each unit to its nearest contested objective (Rule 32.1)
 	unitObjective := make(map[core.UnitID]int) // unitID -> objectiveID
-	for _, u := range g.Units {
-		if u.IsDestroyed() {
-			continue
-		}
-		bestObjID := -1
-		bestDist := math.MaxFloat64
-		for _, obj := range g.Board.Objectives {
-			if obj.IsContested(u.Position()) {
-				d := core.Distance(u.Position(), obj.Position)
-				if d < bestDist {
-					bestDist = d
-					bestObjID = obj.ID
-				}
+	bestDist := make(map[core.UnitID]float64)
+	for _, obj := range g.Board.Objectives {
+		for _, u := range g.Grid.Within(obj.Position, obj.Radius) {
+			d := core.Distance(u.Position(), obj.Position)
+			if best, ok := bestDist[u.ID]; !ok || d < best {
+				bestDist[u.ID] = d
+				unitObjective[u.ID] = obj.ID
 			}
 		}
-		if bestObjID >= 0 {
-			unitObjective[u.ID] = bestObjID
-		}
 	}
 
 	// Step 2: Calculate control per objective using assigned units only
@@ -923,12 +1041,8 @@ func (g *Game) CalculateObjectiveControl() {
 		}
 		scores := make(map[int]*playerScore) // playerID -> score
 
-		for _, u := range g.Units {
-			if u.IsDestroyed() {
-				continue
-			}
-			assignedObj, ok := unitObjective[u.ID]
-			if !ok || assignedObj != obj.ID {
+		for _, u := range g.Grid.Within(obj.Position, obj.Radius+g.Grid.Reach()) {
+			if assignedObj, ok := unitObjective[u.ID]; !ok || assignedObj != obj.ID {
 				continue
 			}
 			if scores[u.OwnerID] == nil {
@@ -952,6 +1066,7 @@ func (g *Game) CalculateObjectiveControl() {
 		}
 		g.ObjectiveControl[obj.ID] = bestPlayer
 	}
+	g.contesting = unitObjective
 }
 
 // ObjectivesControlledBy returns the number of objectives controlled by a player.
@@ -1009,24 +1124,19 @@ func (g *Game) ScoreEndOfTurn(playerID int) int {
 func (g *Game) CalculateGhyraniteObjectiveControl() {
 	// Step 1: Assign each unit to its nearest contested Ghyranite objective
 	unitObjective := make(map[core.UnitID]int) // unitID -> objectiveID
-	for _, u := range g.Units {
-		if u.IsDestroyed() {
-			continue
-		}
-		bestObjID := -1
-		bestDist := math.MaxFloat64
-		for _, obj := range g.Board.Objectives {
-			// Per-model contesting: check if any model in the unit is within range
-			if obj.IsContestedByModel(u.Models) {
-				d := core.Distance(u.Position(), obj.Position)
-				if d < bestDist {
-					bestDist = d
-					bestObjID = obj.ID
-				}
+	bestDist := make(map[core.UnitID]float64)
+	for _, obj := range g.Board.Objectives {
+		// Per-model contesting: a unit counts if any of its models is within
+		// range, so look as far out as any model can be from its unit.
+		for _, u := range g.Grid.Within(obj.Position, obj.Radius+g.Grid.Reach()) {
+			if !obj.IsContestedByModel(u.Models) {
+				continue
+			}
+			d := core.Distance(u.Position(), obj.Position)
+			if best, ok := bestDist[u.ID]; !ok || d < best {
+				bestDist[u.ID] = d
+				unitObjective[u.ID] = obj.ID
 			}
-		}
-		if bestObjID >= 0 {
-			unitObjective[u.ID] = bestObjID
 		}
 	}
 
@@ -1038,12 +1148,8 @@ func (g *Game) CalculateGhyraniteObjectiveControl() {
 		}
 		scores := make(map[int]*playerScore)
 
-		for _, u := range g.Units {
-			if u.IsDestroyed() {
-				continue
-			}
-			assignedObj, ok := unitObjective[u.ID]
-			if !ok || assignedObj != obj.ID {
+		for _, u := range g.Grid.Within(obj.Position, obj.Radius+g.Grid.Reach()) {
+			if assignedObj, ok := unitObjective[u.ID]; !ok || assignedObj != obj.ID {
 				continue
 			}
 			if scores[u.OwnerID] == nil {
@@ -1067,6 +1173,7 @@ func (g *Game) CalculateGhyraniteObjectiveControl() {
 		}
 		g.ObjectiveControl[obj.ID] = bestPlayer
 	}
+	g.contesting = unitObjective
 
 	// Step 3: Calculate pair control
 	for _, pairID := range g.Board.PairIDs() {
@@ -1130,10 +1237,15 @@ func (g *Game) ScoreGhyraniteEndOfTurn(playerID int) int {
 
 // ScoreEndOfTurnAuto selects the appropriate scoring method based on whether a battleplan is active.
 func (g *Game) ScoreEndOfTurnAuto(playerID int) int {
+	var scored int
 	if g.Battleplan != nil {
-		return g.ScoreGhyraniteEndOfTurn(playerID)
+		scored = g.ScoreGhyraniteEndOfTurn(playerID)
+	} else {
+		scored = g.ScoreEndOfTurn(playerID)
 	}
-	return g.ScoreEndOfTurn(playerID)
+	g.recordObjectives(playerID, scored)
+	g.checkObjectiveControl("scoring")
+	return scored
 }
 
 // --- Battle Tactics Integration ---
@@ -1170,6 +1282,7 @@ func (g *Game) EvaluateAndScoreBattleTactic(playerID int) int {
 	tactic := tracker.ActiveTactic.Tactic
 	if g.EvaluateBattleTactic(playerID, tactic) {
 		vp := tracker.CompleteTactic()
+		g.recordTactic(playerID, tactic)
 		g.VictoryPoints[playerID] += vp
 		g.Logf("  Battle Tactic '%s' completed! +%d VP (total: %d)",
 			tactic.Name, vp, g.VictoryPoints[playerID])
@@ -1241,7 +1354,7 @@ func (g *Game) rollOffPriorityWithSeize() (first, second int) {
 
 	// Attempt to seize: spend 1 CP, roll D6
 	state.CommandPoints--
-	seizeRoll := g.Roller.RollD6()
+	seizeRoll := g.rollFor(dice.StreamPriority, "seize the initiative", 1).RollD6()
 	threshold := 6
 
 	// Underdog gets +1 to seize roll (needs 5+)
@@ -1297,8 +1410,8 @@ func (g *Game) CheckFinalVictory() {
 
 func (g *Game) rollOffPriority() (first, second int) {
 	for {
-		roll0 := g.Roller.RollD6()
-		roll1 := g.Roller.RollD6()
+		roll0 := g.rollFor(dice.StreamPriority, "priority", 2).RollD6()
+		roll1 := g.Roller.Stream(dice.StreamPriority).RollD6()
 		g.Logf("Priority roll: %s rolled %d, %s rolled %d",
 			g.Players[0].Name(), roll0, g.Players[1].Name(), roll1)
 
@@ -1313,9 +1426,17 @@ func (g *Game) rollOffPriority() (first, second int) {
 	}
 }
 
+// maxFailedCommands is how many rejected commands in a row a player may issue
+// in one phase before the phase is ended for them. It stops an AI that keeps
+// proposing the same illegal command from stalling the game. Human players
+// can correct their mistakes, so their phases are never ended for them.
+const maxFailedCommands = 10
+
 func (g *Game) runPlayerPhase(playerIdx int, p phase.Phase) {
 	player := g.Players[playerIdx]
 	g.ActivePlayer = playerIdx
+	human, _ := player.(HumanPlayer)
+	failed := 0
 
 	for {
 		view := g.View(player.ID())
@@ -1332,8 +1453,14 @@ func (g *Game) runPlayerPhase(playerIdx int, p phase.Phase) {
 		result, err := g.ExecuteCommand(cmd)
 		if err != nil {
 			g.Logf("    Error: %s", err.Error())
+			failed++
+			if (human == nil || !human.IsHuman()) && failed >= maxFailedCommands {
+				g.Logf("    %s issued %d invalid commands in a row; ending phase", player.Name(), failed)
+				break
+			}
 			continue
 		}
+		failed = 0
 		g.Logf("    %s", result.String())
 
 		g.CheckVictory()
@@ -1345,7 +1472,7 @@ func (g *Game) runPlayerPhase(playerIdx int, p phase.Phase) {
 
 func (g *Game) engagedUnits(playerID int, strikeOrder core.StrikeOrder) []*core.Unit {
 	var result []*core.Unit
-	for _, u := range g.Units {
+	for _, u := range g.unitsInOrder() {
 		if u.OwnerID != playerID || u.IsDestroyed() || u.HasFought {
 			continue
 		}
@@ -1359,67 +1486,58 @@ func (g *Game) engagedUnits(playerID int, strikeOrder core.StrikeOrder) []*core.
 	return result
 }
 
+// baseContact is how far apart the engine stands the points of units in
+// base contact.
+const baseContact = 0.5
+
+// gridCellSize is the side in inches of the cells of the game's spatial
+// grid: wide enough that the common 3" and 4" checks look at few cells.
+const gridCellSize = 6.0
+
+// placeUnit moves every alive model of a unit to pos and re-indexes the unit
+// in the spatial grid.
+func (g *Game) placeUnit(u *core.Unit, pos core.Position) {
+	for i := range u.Models {
+		if u.Models[i].IsAlive {
+			u.Models[i].Position = pos
+		}
+	}
+	g.Grid.Update(u)
+}
+
 // isEngaged returns true if the unit is in combat range AND visible to an enemy.
 // AoS4 Rule 7.0 (Errata Jan 2026): both conditions must be met by the same model.
 func (g *Game) isEngaged(u *core.Unit) bool {
-	for _, other := range g.Units {
-		if other.OwnerID == u.OwnerID || other.IsDestroyed() {
-			continue
-		}
-		if core.Distance(u.Position(), other.Position()) <= 3.0 &&
-			g.Board.IsVisible(u.Position(), other.Position()) {
-			return true
-		}
-	}
-	return false
+	pos := u.Position()
+	return g.Grid.Any(pos, 3.0, func(other *core.Unit) bool {
+		return other.OwnerID != u.OwnerID && g.Board.IsVisible(pos, other.Position())
+	})
 }
 
 // hasNearbyGuard returns true if a Hero has a friendly non-Manifestation model within 4".
 // AoS4 Rule 25.0 (Errata Jan 2026): Guarded Hero cannot be targeted by shooting.
 func (g *Game) hasNearbyGuard(hero *core.Unit) bool {
-	for _, u := range g.Units {
-		if u.OwnerID != hero.OwnerID || u.IsDestroyed() || u.ID == hero.ID {
-			continue
-		}
-		if u.HasKeyword(core.KeywordManifestation) {
-			continue
-		}
-		if core.Distance(hero.Position(), u.Position()) <= 4.0 {
-			return true
-		}
-	}
-	return false
+	return g.Grid.Any(hero.Position(), 4.0, func(u *core.Unit) bool {
+		return u.OwnerID == hero.OwnerID && u.ID != hero.ID &&
+			!u.HasKeyword(core.KeywordManifestation)
+	})
 }
 
 // wouldEngageEnemy returns true if moving to pos would bring the unit within 3" of any enemy.
 func (g *Game) wouldEngageEnemy(u *core.Unit, pos core.Position) bool {
-	for _, other := range g.Units {
-		if other.OwnerID == u.OwnerID || other.IsDestroyed() {
-			continue
-		}
-		if core.Distance(pos, other.Position()) <= 3.0 {
-			return true
-		}
-	}
-	return false
+	return g.Grid.Any(pos, 3.0, func(other *core.Unit) bool {
+		return other.OwnerID != u.OwnerID
+	})
 }
 
 func (g *Game) nearestEnemyPos(u *core.Unit) (core.Position, bool) {
-	bestDist := math.MaxFloat64
-	var bestPos core.Position
-	found := false
-	for _, other := range g.Units {
-		if other.OwnerID == u.OwnerID || other.IsDestroyed() {
-			continue
-		}
-		d := core.Distance(u.Position(), other.Position())
-		if d < bestDist {
-			bestDist = d
-			bestPos = other.Position()
-			found = true
-		}
+	enemy, _ := g.Grid.Nearest(u.Position(), func(other *core.Unit) bool {
+		return other.OwnerID != u.OwnerID
+	})
+	if enemy == nil {
+		return core.Position{}, false
 	}
-	return bestPos, found
+	return enemy.Position(), true
 }
 
 func (g *Game) executePileIn(cmd *command.PileInCommand) (command.Result, error) {
@@ -1457,8 +1575,8 @@ func (g *Game) executePileIn(cmd *command.PileInCommand) (command.Result, error)
 	}
 
 	pileInDist := 3.0 + float64(pileInCtx.Modifiers.PileInMod)
-	if distBefore <= pileInDist {
-		pileInDist = distBefore - 0.5
+	if distBefore-pileInDist < baseContact {
+		pileInDist = distBefore - baseContact
 		if pileInDist < 0 {
 			pileInDist = 0
 		}
@@ -1477,11 +1595,7 @@ func (g *Game) executePileIn(cmd *command.PileInCommand) (command.Result, error)
 		return command.Result{Description: fmt.Sprintf("%s: cannot pile in closer", unit.Name), Success: true}, nil
 	}
 
-	for i := range unit.Models {
-		if unit.Models[i].IsAlive {
-			unit.Models[i].Position = newPos
-		}
-	}
+	g.placeUnit(unit, newPos)
 	unit.HasPiledIn = true
 
 	moved := core.Distance(origin, newPos)
@@ -1554,6 +1668,9 @@ func (g *Game) runCombatSubPhase(turnPlayerIdx int, order core.StrikeOrder, p ph
 				result, err := g.ExecuteCommand(cmd)
 				if err != nil {
 					g.Logf("    Error: %s", err.Error())
+					if unit != nil {
+						g.autoFightUnit(unit)
+					}
 				} else {
 					g.Logf("    %s", result.String())
 				}
@@ -1592,7 +1709,7 @@ func (g *Game) autoFightUnit(unit *core.Unit) {
 
 	var bestTarget *core.Unit
 	bestDist := math.MaxFloat64
-	for _, other := range g.Units {
+	for _, other := range g.unitsInOrder() {
 		if other.OwnerID == unit.OwnerID || other.IsDestroyed() {
 			continue
 		}
@@ -1615,6 +1732,9 @@ func (g *Game) autoFightUnit(unit *core.Unit) {
 	}
 	result, err := g.ExecuteCommand(fightCmd)
 	if err != nil {
+		// The activation is spent even if the fight is illegal (e.g. the
+		// target is not visible), otherwise the sub-phase never ends.
+		unit.HasFought = true
 		g.Logf("    Error: %s", err.Error())
 	} else {
 		g.Logf("    %s", result.String())
@@ -1666,23 +1786,47 @@ func (g *Game) runPlayerTurn(playerIdx int) {
 	// Snapshot enemy alive units for destruction tracking
 	aliveSnapshot := g.SnapshotAliveUnits(playerID)
 
+	g.ActivePlayer = playerIdx
+	if g.Telemetry != nil {
+		g.Telemetry.startTurn()
+	}
+	g.runWindow(rules.OnTurnStart, "")
+
 	for _, p := range phases {
 		if g.IsOver {
 			break
 		}
 
 		g.CurrentPhase = p.Type
+		g.ActivePlayer = playerIdx
 		g.Commands.ResetPhase()
 		g.Logf("  -- %s --", p.Type)
+		g.runWindow(rules.OnPhaseStart, p.Type)
+		g.checkPhase(fmt.Sprintf("start of %s", p.Type))
+		if g.IsOver {
+			break
+		}
 
 		if p.Alternating {
 			g.runAlternatingPhase(playerIdx, p)
 		} else {
 			g.runPlayerPhase(playerIdx, p)
 		}
+		if p.Type == phase.PhaseEndOfTurn && !g.IsOver {
+			g.ScoreEndOfTurnAuto(playerID)
+			g.EvaluateAndScoreBattleTactic(playerID)
+		}
+
+		g.ActivePlayer = playerIdx
+		g.runWindow(rules.OnPhaseEnd, p.Type)
 
 		// Clean up temporary rules from commands (All-out Attack/Defence)
 		g.CleanupPhaseRules()
+		g.checkPhase(fmt.Sprintf("end of %s", p.Type))
+	}
+
+	if !g.IsOver {
+		g.runWindow(rules.OnTurnEnd, "")
 	}
 
 	// Update destruction count for battle tactic evaluation
@@ -1700,6 +1844,7 @@ func (g *Game) RunGame(maxRounds int) {
 	if g.Battleplan != nil {
 		g.InitBattleTactics()
 	}
+	g.RollDestinyDice()
 
 	for round := 1; round <= maxRounds; round++ {
 		g.BattleRound = round
@@ -1756,6 +1901,12 @@ func (g *Game) RunGame(maxRounds int) {
 			tracker.ResetRound()
 		}
 
+		g.ActivePlayer = first
+		g.runWindow(rules.OnBattleRoundStart, "")
+		if g.IsOver {
+			return
+		}
+
 		g.ResetTurnFlags()
 		g.runPlayerTurn(first)
 		if g.IsOver {
@@ -1768,6 +1919,11 @@ func (g *Game) RunGame(maxRounds int) {
 			return
 		}
 
+		g.runWindow(rules.OnBattleRoundEnd, "")
+		if g.IsOver {
+			return
+		}
+
 		// Track who went second for Seize the Initiative next round
 		g.PreviousSecondPlayer = second
 	}
@@ -1775,6 +1931,7 @@ func (g *Game) RunGame(maxRounds int) {
 	if !g.IsOver {
 		g.Logf("Game ended after %d battle rounds", maxRounds)
 		g.IsOver = true
+		g.CheckFinalVictory()
 	}
 }
 
@@ -1845,6 +2002,7 @@ func (g *Game) ExecuteRally(playerID int, unitID core.UnitID) (int, error) {
 	}
 
 	rallyPoints := 0
+	g.Roller.Label("rally", 1, 6)
 	for i := 0; i < 6; i++ {
 		roll := g.Roller.RollD6()
 		if roll >= 4 {
@@ -1950,7 +2108,7 @@ func (g *Game) ExecuteRedeploy(playerID int, unitID core.UnitID, destination cor
 		return err
 	}
 
-	redeployDist := float64(g.Roller.RollD6())
+	redeployDist := float64(g.rollFor("", "redeploy", 1).RollD6())
 	origin := unit.Position()
 	dist := core.Distance(origin, destination)
 
@@ -1962,11 +2120,7 @@ func (g *Game) ExecuteRedeploy(playerID int, unitID core.UnitID, destination cor
 		return fmt.Errorf("cannot end redeploy within 3\" of enemy")
 	}
 
-	for i := range unit.Models {
-		if unit.Models[i].IsAlive {
-			unit.Models[i].Position = destination
-		}
-	}
+	g.placeUnit(unit, destination)
 
 	g.Logf("    %s redeployed %.1f\" (max %.0f\")", unit.Name, dist, redeployDist)
 	return nil
@@ -1978,7 +2132,7 @@ func (g *Game) ExecuteForwardToVictory(playerID int, unitID core.UnitID) (int, e
 		return 0, err
 	}
 
-	newRoll := g.Roller.Roll2D6()
+	newRoll := g.rollFor(dice.StreamCharge, "charge", 2).Roll2D6()
 	unit := g.GetUnit(unitID)
 	g.Logf("    %s re-rolls charge: %d", unit.Name, newRoll)
 	return newRoll, nil
@@ -2012,9 +2166,9 @@ func (g *Game) ExecutePowerThrough(playerID int, unitID core.UnitID, targetID co
 		return err
 	}
 
-	mortalDmg := g.Roller.RollD3()
+	mortalDmg := g.rollFor(dice.StreamCombat, "power through mortal wounds", 1).RollD3()
 	g.Logf("    Power Through: %s deals %d mortal damage to %s", unit.Name, mortalDmg, target.Name)
-	ResolveMortalWounds(g.Roller, target, mortalDmg)
+	g.applyMortalWounds(unit, target, mortalDmg, DamageOther)
 
 	g.CheckVictory()
 	return nil
@@ -2086,8 +2240,8 @@ func (g *Game) executeCast(cmd *command.CastCommand) (command.Result, error) {
 	caster.CastCount++
 
 	// Roll 2D6
-	die1 := g.Roller.RollD6()
-	die2 := g.Roller.RollD6()
+	castDice := g.rollD6s(caster, RollCasting, 2, spell.CastingValue)
+	die1, die2 := castDice[0], castDice[1]
 	castingRoll := die1 + die2
 
 	g.Logf("    %s casts %s: rolled %d+%d = %d (needs %d)",
@@ -2096,10 +2250,10 @@ func (g *Game) executeCast(cmd *command.CastCommand) (command.Result, error) {
 	// Miscast: double 1s = fail + D3 mortal + no more spells this phase
 	if die1 == 1 && die2 == 1 {
 		caster.HasMiscast = true
-		mortalDmg := g.Roller.RollD3()
+		mortalDmg := g.rollFor(dice.StreamMagic, "miscast mortal wounds", 1).RollD3()
 		g.Logf("    MISCAST! %s suffers %d mortal damage and cannot cast again this phase",
 			caster.Name, mortalDmg)
-		ResolveMortalWounds(g.Roller, caster, mortalDmg)
+		g.applyMortalWounds(nil, caster, mortalDmg, DamageSpells)
 		g.CheckVictory()
 		desc := fmt.Sprintf("%s miscast %s! %d mortal damage", caster.Name, spell.Name, mortalDmg)
 		return command.Result{Description: desc, Success: false}, nil
@@ -2111,10 +2265,13 @@ func (g *Game) executeCast(cmd *command.CastCommand) (command.Result, error) {
 		return command.Result{Description: desc, Success: false}, nil
 	}
 
+	castCtx := g.Rules.Evaluate(rules.AfterCast, &rules.Context{Attacker: caster, Defender: target, AllUnits: g.aliveUnits(), Grid: g.Grid})
+	g.logTrace(castCtx.Trace)
+
 	// Unbind attempt: closest enemy wizard within 30" that hasn't used all unbinds
 	if unbound := g.attemptUnbind(caster, castingRoll); unbound {
 		desc := fmt.Sprintf("%s was unbound!", spell.Name)
-		return command.Result{Description: desc, Success: false}, nil
+		return command.Result{Description: desc, Success: false, Trace: castCtx.Trace}, nil
 	}
 
 	// Track same-spell restriction
@@ -2124,7 +2281,9 @@ func (g *Game) executeCast(cmd *command.CastCommand) (command.Result, error) {
 	g.SpellsCastThisTurn[caster.OwnerID][spell.Name] = true
 
 	// Spell succeeds - apply effect
-	return g.applySpellEffect(caster, target, &spell)
+	result, err := g.applySpellEffect(caster, target, &spell)
+	result.Trace = castCtx.Trace
+	return result, err
 }
 
 // attemptUnbind finds the closest enemy wizard within 30" and tries to unbind.
@@ -2133,7 +2292,7 @@ func (g *Game) attemptUnbind(caster *core.Unit, castingRoll int) bool {
 	var bestWizard *core.Unit
 	bestDist := math.MaxFloat64
 
-	for _, u := range g.Units {
+	for _, u := range g.unitsInOrder() {
 		if u.OwnerID == caster.OwnerID || u.IsDestroyed() {
 			continue
 		}
@@ -2152,7 +2311,7 @@ func (g *Game) attemptUnbind(caster *core.Unit, castingRoll int) bool {
 	}
 
 	bestWizard.UnbindCount++
-	unbindRoll := g.Roller.Roll2D6()
+	unbindRoll := g.rollFor(dice.StreamMagic, "unbind", 2).Roll2D6()
 	g.Logf("    %s attempts to unbind: rolled %d (needs > %d)",
 		bestWizard.Name, unbindRoll, castingRoll)
 
@@ -2168,25 +2327,27 @@ func (g *Game) attemptUnbind(caster *core.Unit, castingRoll int) bool {
 func (g *Game) applySpellEffect(caster *core.Unit, target *core.Unit, spell *core.Spell) (command.Result, error) {
 	switch spell.Effect {
 	case core.SpellEffectDamage:
-		mortalDmg := g.Roller.RollD3()
+		mortalDmg := g.rollFor(dice.StreamMagic, "spell damage", 1).RollD3()
 		g.Logf("    %s deals %d mortal wounds to %s", spell.Name, mortalDmg, target.Name)
-		ResolveMortalWounds(g.Roller, target, mortalDmg)
+		g.applyMortalWounds(caster, target, mortalDmg, DamageSpells)
 		g.CheckVictory()
 		desc := fmt.Sprintf("%s cast %s on %s: %d mortal wounds", caster.Name, spell.Name, target.Name, mortalDmg)
 		return command.Result{Description: desc, Success: true}, nil
 
 	case core.SpellEffectHeal:
-		healAmount := g.Roller.RollD3()
+		healAmount := g.rollFor(dice.StreamMagic, "spell healing", 1).RollD3()
 		healed := g.healUnit(target, healAmount)
 		g.Logf("    %s heals %d wounds on %s", spell.Name, healed, target.Name)
 		desc := fmt.Sprintf("%s cast %s on %s: healed %d wounds", caster.Name, spell.Name, target.Name, healed)
 		return command.Result{Description: desc, Success: true}, nil
 
 	case core.SpellEffectBuff:
+		name := fmt.Sprintf("SpellBuff_%s_%d", spell.Name, target.ID)
 		g.Rules.AddRule(rules.Rule{
-			Name:    fmt.Sprintf("SpellBuff_%s_%d", spell.Name, target.ID),
-			Trigger: rules.BeforeSaveRoll,
-			Source:  rules.SourceGlobal,
+			Name:     name,
+			Trigger:  rules.BeforeSaveRoll,
+			Source:   rules.SourceGlobal,
+			StackKey: name, // Casting the same buff twice on a unit does not stack
 			Condition: func(ctx *rules.Context) bool {
 				return ctx.Defender != nil && ctx.Defender.ID == target.ID
 			},
@@ -2225,13 +2386,13 @@ func (g *Game) executeChant(cmd *command.ChantCommand) (command.Result, error) {
 	chanter.ChantCount++
 
 	// Roll D6
-	chantRoll := g.Roller.RollD6()
+	chantRoll := g.rollFor(dice.StreamMagic, "chant", 1).RollD6()
 	g.Logf("    %s chants %s: rolled %d (ritual points: %d)",
 		chanter.Name, prayer.Name, chantRoll, chanter.RitualPoints)
 
 	// Unmodified 1: fail and remove D3 ritual points
 	if chantRoll == 1 {
-		lost := g.Roller.RollD3()
+		lost := g.rollFor(dice.StreamMagic, "ritual points lost", 1).RollD3()
 		chanter.RitualPoints -= lost
 		if chanter.RitualPoints < 0 {
 			chanter.RitualPoints = 0
@@ -2296,25 +2457,27 @@ func (g *Game) executeChant(cmd *command.ChantCommand) (command.Result, error) {
 func (g *Game) applyPrayerEffect(chanter *core.Unit, target *core.Unit, prayer *core.Prayer) (command.Result, error) {
 	switch prayer.Effect {
 	case core.SpellEffectDamage:
-		mortalDmg := g.Roller.RollD3()
+		mortalDmg := g.rollFor(dice.StreamMagic, "prayer damage", 1).RollD3()
 		g.Logf("    %s deals %d mortal wounds to %s", prayer.Name, mortalDmg, target.Name)
-		ResolveMortalWounds(g.Roller, target, mortalDmg)
+		g.applyMortalWounds(chanter, target, mortalDmg, DamageSpells)
 		g.CheckVictory()
 		desc := fmt.Sprintf("%s answered %s on %s: %d mortal wounds", chanter.Name, prayer.Name, target.Name, mortalDmg)
 		return command.Result{Description: desc, Success: true}, nil
 
 	case core.SpellEffectHeal:
-		healAmount := g.Roller.RollD3()
+		healAmount := g.rollFor(dice.StreamMagic, "prayer healing", 1).RollD3()
 		healed := g.healUnit(target, healAmount)
 		g.Logf("    %s heals %d wounds on %s", prayer.Name, healed, target.Name)
 		desc := fmt.Sprintf("%s answered %s on %s: healed %d wounds", chanter.Name, prayer.Name, target.Name, healed)
 		return command.Result{Description: desc, Success: true}, nil
 
 	case core.SpellEffectBuff:
+		name := fmt.Sprintf("PrayerBuff_%s_%d", prayer.Name, target.ID)
 		g.Rules.AddRule(rules.Rule{
-			Name:    fmt.Sprintf("PrayerBuff_%s_%d", prayer.Name, target.ID),
-			Trigger: rules.BeforeSaveRoll,
-			Source:  rules.SourceGlobal,
+			Name:     name,
+			Trigger:  rules.BeforeSaveRoll,
+			Source:   rules.SourceGlobal,
+			StackKey: name, // Casting the same buff twice on a unit does not stack
 			Condition: func(ctx *rules.Context) bool {
 				return ctx.Defender != nil && ctx.Defender.ID == target.ID
 			},
@@ -2331,6 +2494,26 @@ func (g *Game) applyPrayerEffect(chanter *core.Unit, target *core.Unit, prayer *
 	}
 }
 
+// applyMortalWounds inflicts mortal wounds on a unit, honouring ward saves
+// granted by BeforeWardSave rules (enhancements, faction traits).
+// If the mortal wounds destroy the unit, OnUnitDestroyed rules are evaluated.
+// source is the unit inflicting them, or nil, and kind how, for telemetry.
+func (g *Game) applyMortalWounds(source, target *core.Unit, amount int, kind DamageKind) (damage int, slain int) {
+	aliveBefore := aliveModels(target)
+	wardCtx := &rules.Context{Defender: target}
+	ward := wardFor(g.Rules, wardCtx)
+	if ward > 0 && amount > 0 {
+		remind(g.Roller, "ward", wardCtx.Trace)
+	}
+	damage, slain = resolveMortalWounds(g.Roller.Stream(dice.StreamCombat), target, amount, ward)
+	g.recordDamage(source, target, kind, damage, slain)
+	if len(aliveBefore) > 0 && target.IsDestroyed() {
+		g.Rules.Evaluate(rules.OnUnitDestroyed, &rules.Context{Defender: target, Slain: slainSince(target, aliveBefore),
+			AllUnits: g.aliveUnits(), Grid: g.Grid})
+	}
+	return damage, slain
+}
+
 // healUnit applies healing to a unit, distributing across wounded models. Returns total healed.
 func (g *Game) healUnit(target *core.Unit, amount int) int {
 	healed := 0
@@ -2395,6 +2578,7 @@ func (g *Game) executeRally(cmd *command.RallyCommand) (command.Result, error) {
 
 	// Roll 6D6, count 4+
 	rallyPoints := 0
+	g.Roller.Label("rally", 1, 6)
 	for i := 0; i < 6; i++ {
 		roll := g.Roller.RollD6()
 		if roll >= 4 {
@@ -2501,8 +2685,8 @@ func (g *Game) executeMagicalInterventionSpell(cmd *command.MagicalInterventionC
 	caster.CastCount++
 
 	// Roll 2D6 with -1 penalty for magical intervention
-	die1 := g.Roller.RollD6()
-	die2 := g.Roller.RollD6()
+	castDice := g.rollD6s(caster, RollCasting, 2, spell.CastingValue+1)
+	die1, die2 := castDice[0], castDice[1]
 	castingRoll := die1 + die2 - 1 // -1 for magical intervention
 
 	g.Logf("    %s (Magical Intervention) casts %s: rolled %d+%d-1 = %d (needs %d)",
@@ -2511,9 +2695,9 @@ func (g *Game) executeMagicalInterventionSpell(cmd *command.MagicalInterventionC
 	// Miscast on natural double 1s (before modifier)
 	if die1 == 1 && die2 == 1 {
 		caster.HasMiscast = true
-		mortalDmg := g.Roller.RollD3()
+		mortalDmg := g.rollFor(dice.StreamMagic, "miscast mortal wounds", 1).RollD3()
 		g.Logf("    MISCAST! %s suffers %d mortal damage", caster.Name, mortalDmg)
-		ResolveMortalWounds(g.Roller, caster, mortalDmg)
+		g.applyMortalWounds(nil, caster, mortalDmg, DamageSpells)
 		g.CheckVictory()
 		desc := fmt.Sprintf("%s miscast %s via Magical Intervention! %d mortal damage", caster.Name, spell.Name, mortalDmg)
 		return command.Result{Description: desc, Success: false}, nil
@@ -2525,10 +2709,13 @@ func (g *Game) executeMagicalInterventionSpell(cmd *command.MagicalInterventionC
 		return command.Result{Description: desc, Success: false}, nil
 	}
 
+	castCtx := g.Rules.Evaluate(rules.AfterCast, &rules.Context{Attacker: caster, Defender: target, AllUnits: g.aliveUnits(), Grid: g.Grid})
+	g.logTrace(castCtx.Trace)
+
 	// Unbind attempt
 	if unbound := g.attemptUnbind(caster, castingRoll); unbound {
 		desc := fmt.Sprintf("%s was unbound!", spell.Name)
-		return command.Result{Description: desc, Success: false}, nil
+		return command.Result{Description: desc, Success: false, Trace: castCtx.Trace}, nil
 	}
 
 	// Track same-spell restriction
@@ -2537,7 +2724,9 @@ func (g *Game) executeMagicalInterventionSpell(cmd *command.MagicalInterventionC
 	}
 	g.SpellsCastThisTurn[caster.OwnerID][spell.Name] = true
 
-	return g.applySpellEffect(caster, target, &spell)
+	result, err := g.applySpellEffect(caster, target, &spell)
+	result.Trace = castCtx.Trace
+	return result, err
 }
 
 // executeMagicalInterventionPrayer handles chanting a prayer via Magical Intervention (-1 penalty).
@@ -2553,7 +2742,7 @@ func (g *Game) executeMagicalInterventionPrayer(cmd *command.MagicalIntervention
 	chanter.ChantCount++
 
 	// Roll D6 with -1 penalty
-	rawRoll := g.Roller.RollD6()
+	rawRoll := g.rollFor(dice.StreamMagic, "chant", 1).RollD6()
 	chantRoll := rawRoll - 1 // -1 for magical intervention
 
 	g.Logf("    %s (Magical Intervention) chants %s: rolled %d-1 = %d (ritual points: %d)",
@@ -2561,7 +2750,7 @@ func (g *Game) executeMagicalInterventionPrayer(cmd *command.MagicalIntervention
 
 	// Natural 1 still fails (check raw roll)
 	if rawRoll == 1 {
-		lost := g.Roller.RollD3()
+		lost := g.rollFor(dice.StreamMagic, "ritual points lost", 1).RollD3()
 		chanter.RitualPoints -= lost
 		if chanter.RitualPoints < 0 {
 			chanter.RitualPoints = 0
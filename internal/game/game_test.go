@@ -1,6 +1,7 @@
 package game
 
 import (
+	"math"
 	"testing"
 
 	"github.com/jruiznavarro/wargamestactics/internal/game/board"
@@ -217,6 +218,61 @@ func TestExecuteCharge(t *testing.T) {
 	}
 }
 
+func TestExecuteCharge_EndsClearOfOtherEnemies(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	charger := g.CreateUnit("Chargers", 1, core.Stats{Move: 5, Health: 1}, nil, 1, core.Position{X: 10, Y: 10}, 1.0)
+	target := g.CreateUnit("Target", 2, core.Stats{Move: 4, Health: 2, Save: 4}, nil, 1, core.Position{X: 12, Y: 10}, 1.0)
+	// Stands where a straight charge at the target would end
+	other := g.CreateUnit("Other", 2, core.Stats{Move: 4, Health: 2, Save: 4}, nil, 1, core.Position{X: 11.6, Y: 10}, 1.0)
+
+	result, err := g.ExecuteCommand(&command.ChargeCommand{OwnerID: 1, ChargerID: charger.ID, TargetID: target.ID})
+	if err != nil || !result.Success {
+		t.Fatalf("expected a 2\" charge to succeed, got %+v, %v", result, err)
+	}
+	if d := core.Distance(charger.Position(), target.Position()); math.Abs(d-baseContact) > 1e-9 {
+		t.Errorf("expected the charger in base contact with the target, %.2f\" apart", d)
+	}
+	if d := core.Distance(charger.Position(), other.Position()); d < baseContact {
+		t.Errorf("expected the charger clear of the other enemy, %.2f\" apart", d)
+	}
+}
+
+func TestExecuteCharge_NoRoomAroundTarget(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	charger := g.CreateUnit("Chargers", 1, core.Stats{Move: 5, Health: 1}, nil, 1, core.Position{X: 10, Y: 10}, 1.0)
+	target := g.CreateUnit("Target", 2, core.Stats{Move: 4, Health: 2, Save: 4}, nil, 1, core.Position{X: 12, Y: 10}, 1.0)
+	for i := 0; i < 6; i++ {
+		a := float64(i) * math.Pi / 3
+		pos := core.Position{X: 12 + baseContact*math.Cos(a), Y: 10 + baseContact*math.Sin(a)}
+		g.CreateUnit("Guard", 2, core.Stats{Move: 4, Health: 2, Save: 4}, nil, 1, pos, 1.0)
+	}
+
+	result, err := g.ExecuteCommand(&command.ChargeCommand{OwnerID: 1, ChargerID: charger.ID, TargetID: target.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Success {
+		t.Error("expected the charge to fail with no room around the target")
+	}
+	if !charger.HasCharged || charger.Position() != (core.Position{X: 10, Y: 10}) {
+		t.Errorf("expected the charger to have charged without moving, at %+v", charger.Position())
+	}
+}
+
+func TestExecutePileIn_StopsAtBaseContact(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	unit := g.CreateUnit("Warriors", 1, core.Stats{Move: 5, Health: 1}, nil, 1, core.Position{X: 10, Y: 10}, 1.0)
+	// Just over a full 3" pile-in away
+	enemy := g.CreateUnit("Enemy", 2, core.Stats{Move: 4, Health: 2, Save: 4}, nil, 1, core.Position{X: 13.2, Y: 10}, 1.0)
+
+	if _, err := g.ExecuteCommand(&command.PileInCommand{OwnerID: 1, UnitID: unit.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := core.Distance(unit.Position(), enemy.Position()); math.Abs(d-baseContact) > 1e-9 {
+		t.Errorf("expected the unit to stop in base contact, %.2f\" from the enemy", d)
+	}
+}
+
 func TestCheckVictory(t *testing.T) {
 	g := NewGame(42, 48, 24)
 	p1 := &stubPlayer{id: 1, name: "Player 1"}
@@ -699,3 +755,134 @@ func TestEngagement_RequiresVisibility(t *testing.T) {
 		t.Error("unit should not be engaged when LOS is blocked by impassable terrain")
 	}
 }
+
+// repeatPlayer returns the same command forever.
+type repeatPlayer struct {
+	stubPlayer
+	cmd   interface{}
+	calls int
+}
+
+func (r *repeatPlayer) GetNextCommand(view *GameView, currentPhase phase.Phase) interface{} {
+	r.calls++
+	return r.cmd
+}
+
+func TestRunPlayerPhase_EndsAfterRepeatedInvalidCommands(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	p1 := &repeatPlayer{stubPlayer: stubPlayer{id: 1, name: "Stuck"},
+		cmd: &command.MoveCommand{OwnerID: 1, UnitID: 99, Destination: core.Position{X: 5, Y: 5}}}
+	g.AddPlayer(p1)
+	g.AddPlayer(&stubPlayer{id: 2, name: "Player 2"})
+
+	g.runPlayerPhase(0, phase.StandardTurnSequence()[1])
+
+	if p1.calls != maxFailedCommands {
+		t.Errorf("expected phase to end after %d failed commands, got %d calls", maxFailedCommands, p1.calls)
+	}
+}
+
+// humanRepeatPlayer is a person giving the same illegal command limit times
+// before ending the phase.
+type humanRepeatPlayer struct {
+	repeatPlayer
+	limit int
+}
+
+func (h *humanRepeatPlayer) IsHuman() bool { return true }
+
+func (h *humanRepeatPlayer) GetNextCommand(view *GameView, currentPhase phase.Phase) interface{} {
+	if h.calls == h.limit {
+		return &command.EndPhaseCommand{OwnerID: h.id}
+	}
+	return h.repeatPlayer.GetNextCommand(view, currentPhase)
+}
+
+func TestRunPlayerPhase_HumanKeepsPhaseAfterInvalidCommands(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	p1 := &humanRepeatPlayer{limit: 3 * maxFailedCommands}
+	p1.stubPlayer = stubPlayer{id: 1, name: "Human"}
+	p1.cmd = &command.MoveCommand{OwnerID: 1, UnitID: 99, Destination: core.Position{X: 5, Y: 5}}
+	g.AddPlayer(p1)
+	g.AddPlayer(&stubPlayer{id: 2, name: "Player 2"})
+
+	g.runPlayerPhase(0, phase.StandardTurnSequence()[1])
+
+	if p1.calls != p1.limit {
+		t.Errorf("expected the human to keep the phase for %d commands, got %d", p1.limit, p1.calls)
+	}
+}
+
+func TestCombatSubPhase_FailedFightSpendsActivation(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	sword := []core.Weapon{{Name: "Sword", Range: 0, Attacks: 2, ToHit: 4, ToWound: 4, Damage: 1}}
+	stats := core.Stats{Move: 5, Save: 4, Control: 1, Health: 10}
+	attacker := g.CreateUnit("P1 Warriors", 1, stats, sword, 1, core.Position{X: 10, Y: 12}, 1.0)
+	defender := g.CreateUnit("P2 Warriors", 2, stats, sword, 1, core.Position{X: 11, Y: 12}, 1.0)
+	// Player 1 keeps choosing a target that does not exist
+	p1 := &repeatPlayer{stubPlayer: stubPlayer{id: 1, name: "P1"},
+		cmd: &command.FightCommand{OwnerID: 1, AttackerID: attacker.ID, TargetID: 99}}
+	g.AddPlayer(p1)
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+
+	g.runCombatSubPhase(0, core.StrikeNormal, phase.StandardTurnSequence()[4])
+
+	if p1.calls != 1 {
+		t.Errorf("expected player 1 to be asked once, got %d", p1.calls)
+	}
+	if !attacker.HasFought || !defender.HasFought {
+		t.Errorf("expected both units to have fought, got %v and %v", attacker.HasFought, defender.HasFought)
+	}
+}
+
+func TestAutoFightUnit_IllegalFightSpendsActivation(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	sword := []core.Weapon{{Name: "Sword", Range: 0, Attacks: 2, ToHit: 4, ToWound: 4, Damage: 1}}
+	stats := core.Stats{Move: 5, Save: 4, Control: 1, Health: 10}
+	attacker := g.CreateUnit("P1 Warriors", 1, stats, sword, 1, core.Position{X: 10, Y: 12}, 1.0)
+	g.CreateUnit("P2 Warriors", 2, stats, sword, 1, core.Position{X: 12, Y: 12}, 1.0)
+	g.Board.AddTerrain("Wall", board.TerrainImpassable, core.Position{X: 10.5, Y: 11}, 1, 2)
+
+	g.autoFightUnit(attacker)
+
+	if !attacker.HasFought {
+		t.Error("expected the unit's activation to be spent when its target is not visible")
+	}
+}
+
+func TestGrid_FollowsMovesAndReachesRules(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	sword := []core.Weapon{{Name: "Sword", Attacks: 2, ToHit: 4, ToWound: 4, Damage: 1}}
+	mover := g.CreateUnit("Mover", 1, core.Stats{Move: 6, Health: 1}, sword, 1, core.Position{X: 10, Y: 10}, 1.0)
+	enemy := g.CreateUnit("Enemy", 2, core.Stats{Health: 5, Save: 4}, nil, 1, core.Position{X: 20, Y: 10}, 1.0)
+
+	if _, err := g.ExecuteCommand(&command.MoveCommand{OwnerID: 1, UnitID: mover.ID, Destination: core.Position{X: 15, Y: 10}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := g.Grid.Within(core.Position{X: 15, Y: 10}, 0.1); len(got) != 1 || got[0] != mover {
+		t.Errorf("expected the grid to follow the move, got %v", got)
+	}
+	if g.isEngaged(mover) || !g.wouldEngageEnemy(mover, core.Position{X: 18, Y: 10}) {
+		t.Error("expected engagement checks to use the new position")
+	}
+	if pos, ok := g.nearestEnemyPos(mover); !ok || pos != enemy.Position() {
+		t.Errorf("expected the enemy as nearest, got %v", pos)
+	}
+
+	// Attack rules see the grid, so auras work during combat.
+	g.Rules.AddRule(rules.Rule{
+		Name:    "Aura",
+		Trigger: rules.BeforeHitRoll,
+		Source:  rules.SourceFaction,
+		Condition: func(ctx *rules.Context) bool {
+			return ctx.Grid.Any(ctx.Attacker.Position(), 12, func(u *core.Unit) bool { return u.ID == enemy.ID })
+		},
+		Apply: func(ctx *rules.Context) { ctx.Modifiers.HitMod++ },
+	})
+	results := resolveCombat(g.Roller, g.Rules, mover, enemy, nil, g.Grid)
+	if len(results) != 1 || len(results[0].Trace) != 1 || results[0].Trace[0].Rule != "Aura" {
+		t.Errorf("expected the aura to apply, got %+v", results)
+	}
+}
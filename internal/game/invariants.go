@@ -0,0 +1,273 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+	"github.com/jruiznavarro/wargamestactics/internal/game/command"
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/phase"
+)
+
+// coherencyRange is how close each model must be to another of its unit.
+const coherencyRange = 0.5
+
+// Violation is a state the rules should never allow, found by an
+// InvariantChecker.
+type Violation struct {
+	Round   int
+	Phase   phase.PhaseType
+	Trigger string      // The command or phase transition after which it was found
+	Command interface{} // The command, if a command triggered the check
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("round %d, %s, after %s: %s", v.Round, v.Phase, v.Trigger, v.Message)
+}
+
+// InvariantChecker checks the game state after every command and phase
+// transition, for debugging the rules. A game checks nothing unless its
+// Invariants is set. It checks that:
+//
+//   - models have between 0 and their maximum wounds, and alive ones at least 1
+//   - alive models are on the board and their units in coherency
+//   - units do not overlap enemy units
+//   - action flags are only set on units that could have acted so far this
+//     turn, and no unit fights twice in a turn
+//   - command points are never negative and victory points never fall
+//   - scored objectives are controlled by units that are still alive
+//
+// The engine stands all models of a unit on one point and measures between
+// those points, with units in base contact 1/2" apart, so overlapping bases
+// are checked as enemy units closer than that. Friendly units are not kept
+// apart as they move, so they may share ground.
+//
+// A broken state is reported once, after the command or transition that
+// caused it, rather than again at every check it lasts through.
+type InvariantChecker struct {
+	// Panic makes the first violation panic instead of being collected.
+	Panic bool
+	// Violations are the violations found so far, in order.
+	Violations []Violation
+
+	lastVP     map[int]int
+	round      int
+	turnPlayer int                  // Index of the player whose turn it is, -1 until the first phase
+	fought     map[core.UnitID]bool // Units that have fought this turn
+	phaseIdx   map[phase.PhaseType]int
+	broken     map[string]bool // States found broken by the last check
+	found      map[string]bool // States found broken by the current check
+}
+
+// NewInvariantChecker returns a checker that collects violations, or
+// panics on the first if panicOnViolation is set.
+func NewInvariantChecker(panicOnViolation bool) *InvariantChecker {
+	c := &InvariantChecker{
+		Panic:      panicOnViolation,
+		lastVP:     make(map[int]int),
+		turnPlayer: -1,
+		fought:     make(map[core.UnitID]bool),
+		phaseIdx:   make(map[phase.PhaseType]int),
+		broken:     make(map[string]bool),
+		found:      make(map[string]bool),
+	}
+	for i, p := range phase.StandardTurnSequence() {
+		c.phaseIdx[p.Type] = i
+	}
+	return c
+}
+
+// Err returns the violations found as an error, or nil if there were none.
+func (c *InvariantChecker) Err() error {
+	if len(c.Violations) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d invariant violations:", len(c.Violations))
+	for _, v := range c.Violations {
+		b.WriteString("\n  " + v.String())
+	}
+	return fmt.Errorf("%s", b.String())
+}
+
+func (c *InvariantChecker) report(g *Game, trigger string, cmd interface{}, format string, args ...interface{}) {
+	v := Violation{
+		Round:   g.BattleRound,
+		Phase:   g.CurrentPhase,
+		Trigger: trigger,
+		Command: cmd,
+		Message: fmt.Sprintf(format, args...),
+	}
+	if c.Panic {
+		panic("invariant violated: " + v.String())
+	}
+	c.Violations = append(c.Violations, v)
+}
+
+// broke reports a broken state, unless it was already broken at the last
+// check.
+func (c *InvariantChecker) broke(g *Game, trigger string, cmd interface{}, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	c.found[msg] = true
+	if !c.broken[msg] {
+		c.report(g, trigger, cmd, "%s", msg)
+	}
+}
+
+// checkCommand checks the game after a command, whether or not it failed.
+func (g *Game) checkCommand(cmd interface{}, err error) {
+	if g.Invariants == nil {
+		return
+	}
+	trigger := fmt.Sprintf("%T %+v", cmd, cmd)
+	if err != nil {
+		trigger += " (failed: " + err.Error() + ")"
+	}
+	if fight, ok := cmd.(*command.FightCommand); ok && err == nil {
+		g.Invariants.checkFought(g, trigger, cmd, fight.AttackerID)
+	}
+	g.Invariants.check(g, trigger, cmd)
+}
+
+// checkPhase checks the game at a phase transition, e.g. "start of Hero
+// Phase". The active player at a transition is the one whose turn it is.
+func (g *Game) checkPhase(transition string) {
+	c := g.Invariants
+	if c == nil {
+		return
+	}
+	if g.BattleRound != c.round || g.ActivePlayer != c.turnPlayer {
+		c.round, c.turnPlayer = g.BattleRound, g.ActivePlayer
+		clear(c.fought)
+	}
+	c.check(g, transition, nil)
+}
+
+// checkFought records that a unit fought, reporting it if it already had
+// this turn.
+func (c *InvariantChecker) checkFought(g *Game, trigger string, cmd interface{}, id core.UnitID) {
+	if c.fought[id] {
+		c.report(g, trigger, cmd, "%s fought twice this turn", g.unitName(id))
+	}
+	c.fought[id] = true
+}
+
+func (c *InvariantChecker) check(g *Game, trigger string, cmd interface{}) {
+	defer func() { c.broken, c.found = c.found, c.broken; clear(c.found) }()
+
+	units := g.unitsInOrder()
+	for _, u := range units {
+		c.checkUnit(g, u, trigger, cmd)
+	}
+	for i, u := range units {
+		if u.IsDestroyed() {
+			continue
+		}
+		for _, o := range units[i+1:] {
+			if o.IsDestroyed() || o.OwnerID == u.OwnerID {
+				continue
+			}
+			if d := core.Distance(u.Position(), o.Position()); d < baseContact-1e-9 {
+				c.broke(g, trigger, cmd, "%s and %s overlap, %.2f\" apart", u.Name, o.Name, d)
+			}
+		}
+	}
+
+	for _, p := range g.Players {
+		id := p.ID()
+		if s := g.Commands.GetState(id); s != nil && s.CommandPoints < 0 {
+			c.broke(g, trigger, cmd, "%s has %d command points", p.Name(), s.CommandPoints)
+		}
+		if vp := g.VictoryPoints[id]; vp < c.lastVP[id] {
+			c.report(g, trigger, cmd, "%s's victory points fell from %d to %d", p.Name(), c.lastVP[id], vp)
+		}
+		c.lastVP[id] = g.VictoryPoints[id]
+	}
+}
+
+func (c *InvariantChecker) checkUnit(g *Game, u *core.Unit, trigger string, cmd interface{}) {
+	var positions []core.Position
+	for i, m := range u.Models {
+		if m.CurrentWounds < 0 || m.CurrentWounds > m.MaxWounds {
+			c.broke(g, trigger, cmd, "%s model %d has %d of %d wounds", u.Name, i, m.CurrentWounds, m.MaxWounds)
+		}
+		if !m.IsAlive {
+			continue
+		}
+		if m.CurrentWounds == 0 {
+			c.broke(g, trigger, cmd, "%s model %d is alive with no wounds", u.Name, i)
+		}
+		if !g.Board.IsInBounds(m.Position) {
+			c.broke(g, trigger, cmd, "%s model %d is off the board at (%.1f, %.1f)", u.Name, i, m.Position.X, m.Position.Y)
+		}
+		positions = append(positions, m.Position)
+	}
+	if len(positions) == 0 {
+		return
+	}
+	if !board.UnitCoherencyValid(positions, coherencyRange) {
+		c.broke(g, trigger, cmd, "%s is out of coherency", u.Name)
+	}
+
+	// Flags set by actions the unit cannot have taken yet this turn.
+	now, ok := c.phaseIdx[g.CurrentPhase]
+	if !ok || c.turnPlayer < 0 || c.turnPlayer >= len(g.Players) {
+		return
+	}
+	active := u.OwnerID == g.Players[c.turnPlayer].ID()
+	flags := []struct {
+		set        bool
+		name       string
+		from       phase.PhaseType
+		activeOnly bool
+	}{
+		{u.HasMoved, "moved", phase.PhaseMovement, true},
+		{u.HasRun, "run", phase.PhaseMovement, true},
+		{u.HasRetreated, "retreated", phase.PhaseMovement, true},
+		{u.HasShot, "shot", phase.PhaseShooting, true},
+		{u.HasCharged, "charged", phase.PhaseCharging, true},
+		{u.HasPiledIn, "piled in", phase.PhaseCombat, false},
+	}
+	for _, f := range flags {
+		if !f.set {
+			continue
+		}
+		if f.activeOnly && !active {
+			c.broke(g, trigger, cmd, "%s has %s in its opponent's turn", u.Name, f.name)
+		} else if now < c.phaseIdx[f.from] {
+			c.broke(g, trigger, cmd, "%s has %s before the %s", u.Name, f.name, f.from)
+		}
+	}
+	if (u.HasRun || u.HasRetreated) && !u.HasMoved {
+		c.broke(g, trigger, cmd, "%s has run or retreated without moving", u.Name)
+	}
+}
+
+// checkObjectiveControl checks, right after control is worked out, that
+// each controlled objective is contested by a unit of its controller that
+// is still alive.
+func (g *Game) checkObjectiveControl(trigger string) {
+	if g.Invariants == nil {
+		return
+	}
+	held := make(map[int]bool) // Objectives contested by an alive unit of their controller
+	for id, obj := range g.contesting {
+		if u := g.Units[id]; u != nil && !u.IsDestroyed() && g.ObjectiveControl[obj] == u.OwnerID {
+			held[obj] = true
+		}
+	}
+	for _, obj := range g.Board.Objectives {
+		if owner, ok := g.ObjectiveControl[obj.ID]; ok && owner >= 0 && !held[obj.ID] {
+			g.Invariants.report(g, trigger, nil, "objective %d is controlled by player %d without an alive unit on it", obj.ID, owner)
+		}
+	}
+}
+
+func (g *Game) unitName(id core.UnitID) string {
+	if u := g.Units[id]; u != nil {
+		return u.Name
+	}
+	return fmt.Sprintf("unit %d", id)
+}
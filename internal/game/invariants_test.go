@@ -0,0 +1,171 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/command"
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/phase"
+)
+
+func setupInvariantGame() *Game {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	g.Commands.InitRound([]int{1, 2}, 4, -1)
+	g.Invariants = NewInvariantChecker(false)
+	g.BattleRound = 1
+	g.CurrentPhase = phase.PhaseHero
+	return g
+}
+
+// expectViolations checks that the violations found since the last call
+// match want, in order.
+func expectViolations(t *testing.T, g *Game, seen *int, want ...string) {
+	t.Helper()
+	got := g.Invariants.Violations[*seen:]
+	*seen = len(g.Invariants.Violations)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d violations, got %d: %v", len(want), len(got), got)
+	}
+	for i, v := range got {
+		if !strings.Contains(v.Message, want[i]) {
+			t.Errorf("violation %d: expected %q, got %q", i, want[i], v.Message)
+		}
+	}
+}
+
+func TestInvariants_LegalPlay(t *testing.T) {
+	g := setupInvariantGame()
+	sword := []core.Weapon{{Name: "Sword", Range: 0, Attacks: 4, ToHit: 3, ToWound: 3, Damage: 1}}
+	g.CreateUnit("Warriors", 1, core.Stats{Move: 5, Save: 4, Control: 1, Health: 1}, sword, 5, core.Position{X: 10, Y: 10}, 1.0)
+	g.CreateUnit("Enemies", 2, core.Stats{Move: 5, Save: 4, Control: 1, Health: 1}, sword, 5, core.Position{X: 14, Y: 10}, 1.0)
+
+	g.RunGame(2)
+	if err := g.Invariants.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInvariants_ReportsBrokenStates(t *testing.T) {
+	g := setupInvariantGame()
+	u := g.CreateUnit("Warriors", 1, core.Stats{Move: 5, Health: 2}, nil, 2, core.Position{X: 10, Y: 10}, 1.0)
+	g.CreateUnit("Enemies", 2, core.Stats{Move: 5, Health: 1}, nil, 1, core.Position{X: 10.2, Y: 10}, 1.0)
+	var seen int
+
+	g.checkPhase("start of Hero Phase")
+	expectViolations(t, g, &seen, "overlap")
+
+	u.Models[0].CurrentWounds = 3
+	u.Models[1].Position = core.Position{X: 50, Y: 10}
+	g.Commands.GetState(1).CommandPoints = -1
+	g.checkPhase("end of Hero Phase")
+	expectViolations(t, g, &seen, "has 3 of 2 wounds", "off the board", "out of coherency", "-1 command points")
+
+	// Broken states are reported once, when they break
+	g.checkPhase("start of Movement Phase")
+	expectViolations(t, g, &seen)
+
+	u.Models[0].CurrentWounds = 0
+	g.checkPhase("end of Movement Phase")
+	expectViolations(t, g, &seen, "alive with no wounds")
+}
+
+func TestInvariants_VictoryPointsNeverFall(t *testing.T) {
+	g := setupInvariantGame()
+	var seen int
+	g.VictoryPoints[2] = 5
+	g.checkPhase("start of Hero Phase")
+	g.VictoryPoints[2] = 3
+	g.checkPhase("end of Hero Phase")
+	expectViolations(t, g, &seen, "P2's victory points fell from 5 to 3")
+}
+
+func TestInvariants_FlagsFollowTheTurn(t *testing.T) {
+	g := setupInvariantGame()
+	mine := g.CreateUnit("Archers", 1, core.Stats{Move: 5, Health: 1}, nil, 1, core.Position{X: 10, Y: 10}, 1.0)
+	theirs := g.CreateUnit("Enemies", 2, core.Stats{Move: 5, Health: 1}, nil, 1, core.Position{X: 30, Y: 10}, 1.0)
+	var seen int
+
+	g.CurrentPhase = phase.PhaseMovement
+	g.checkPhase("start of Movement Phase")
+	mine.HasShot = true
+	theirs.HasMoved = true
+	mine.HasRun = true
+	g.checkPhase("end of Movement Phase")
+	expectViolations(t, g, &seen, "Archers has shot before the Shooting Phase", "run or retreated without moving",
+		"Enemies has moved in its opponent's turn")
+
+	// Piling in is for both players, and in combat the active player
+	// alternates but it is still player 1's turn
+	mine.HasShot, mine.HasRun, theirs.HasMoved = false, false, false
+	g.CurrentPhase = phase.PhaseCombat
+	g.checkPhase("start of Combat Phase")
+	g.ActivePlayer = 1
+	theirs.HasPiledIn = true
+	mine.HasCharged = true
+	g.checkCommand(&command.EndPhaseCommand{OwnerID: 2}, nil)
+	expectViolations(t, g, &seen)
+}
+
+func TestInvariants_FightingTwice(t *testing.T) {
+	g := setupInvariantGame()
+	axe := []core.Weapon{{Name: "Axe", Range: 0, Attacks: 1, ToHit: 6, ToWound: 6, Damage: 1}}
+	brute := g.CreateUnit("Brute", 1, core.Stats{Health: 5}, axe, 1, core.Position{X: 10, Y: 10}, 1.0)
+	victim := g.CreateUnit("Victim", 2, core.Stats{Health: 20, Save: 2}, nil, 1, core.Position{X: 11, Y: 10}, 1.0)
+	fight := &command.FightCommand{OwnerID: 1, AttackerID: brute.ID, TargetID: victim.ID}
+	var seen int
+
+	g.CurrentPhase = phase.PhaseCombat
+	g.checkPhase("start of Combat Phase")
+	if _, err := g.ExecuteCommand(fight); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	brute.HasFought = false // As a rule wrongly letting it fight again would
+	if _, err := g.ExecuteCommand(fight); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectViolations(t, g, &seen, "Brute fought twice this turn")
+	if v := g.Invariants.Violations[0]; v.Command != fight || !strings.Contains(v.Trigger, "FightCommand") {
+		t.Errorf("expected the violation to name the fight, got %+v", v)
+	}
+
+	// A new turn starts a new count
+	g.ActivePlayer = 1
+	g.checkPhase("start of Combat Phase")
+	brute.HasFought = false
+	if _, err := g.ExecuteCommand(fight); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectViolations(t, g, &seen)
+}
+
+func TestInvariants_ObjectiveControl(t *testing.T) {
+	g := setupInvariantGame()
+	g.CurrentPhase = phase.PhaseEndOfTurn
+	g.Board.AddObjective(core.Position{X: 24, Y: 12}, 6.0)
+	g.CreateUnit("P1 Warriors", 1, core.Stats{Move: 5, Control: 2, Health: 1}, nil, 10, core.Position{X: 24, Y: 12}, 1.0)
+	var seen int
+
+	g.ScoreEndOfTurnAuto(1)
+	expectViolations(t, g, &seen)
+
+	g.ObjectiveControl[1] = 2
+	g.checkObjectiveControl("scoring")
+	expectViolations(t, g, &seen, "objective 1 is controlled by player 2 without an alive unit on it")
+}
+
+func TestInvariants_Panic(t *testing.T) {
+	g := setupInvariantGame()
+	g.Invariants = NewInvariantChecker(true)
+	u := g.CreateUnit("Warriors", 1, core.Stats{Move: 5, Health: 1}, nil, 1, core.Position{X: 10, Y: 10}, 1.0)
+	u.Models[0].CurrentWounds = -1
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "has -1 of 1 wounds") {
+			t.Errorf("expected a panic naming the violation, got %v", r)
+		}
+	}()
+	g.checkPhase("start of Hero Phase")
+}
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/phase"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+)
+
+// The battle is a fixed sequence of steps. Between them sit lifecycle
+// windows where rules get to act:
+//
+//	OnBattleRoundStart                 after priority and command points
+//	  OnTurnStart                      for each player turn
+//	    OnPhaseStart / OnPhaseEnd      around each phase of the turn
+//	  OnTurnEnd
+//	OnBattleRoundEnd
+//
+// Each window is evaluated once with ctx.Unit nil, for rules that act on a
+// player or the battle as a whole, then once per unit on the battlefield,
+// in unit ID order, with ctx.Unit set to that unit. Every rule on a
+// lifecycle trigger sees all of these evaluations, so a unit-scoped rule
+// must check that ctx.Unit is its unit, and a player-scoped rule that
+// ctx.Unit is nil, or it fires once per unit. Rules act through the effects
+// they queue on the context (healing the unit, command points for its
+// owner, mortal wounds), which the engine carries out after each
+// evaluation.
+
+// runWindow evaluates a lifecycle window once for the players and then for
+// every unit on the battlefield. p is the phase the window belongs to (""
+// for round and turn windows).
+func (g *Game) runWindow(trigger rules.Trigger, p phase.PhaseType) {
+	if !g.Rules.HasRulesFor(trigger) {
+		return
+	}
+	units := g.aliveUnits()
+	activeID := -1
+	if g.ActivePlayer >= 0 && g.ActivePlayer < len(g.Players) {
+		activeID = g.Players[g.ActivePlayer].ID()
+	}
+	evaluate := func(u *core.Unit) {
+		g.Rules.Evaluate(trigger, &rules.Context{
+			Unit:        u,
+			PhaseType:   string(p),
+			BattleRound: g.BattleRound,
+			AllUnits:    units,
+			Grid:        g.Grid,
+			PlayerID:    activeID,
+		})
+	}
+	evaluate(nil)
+	for _, u := range units {
+		if g.IsOver {
+			return
+		}
+		if u.IsDestroyed() {
+			continue
+		}
+		evaluate(u)
+	}
+}
+
+// aliveUnits returns the units still on the battlefield, in ID order.
+func (g *Game) aliveUnits() []*core.Unit {
+	var units []*core.Unit
+	for _, u := range g.unitsInOrder() {
+		if !u.IsDestroyed() {
+			units = append(units, u)
+		}
+	}
+	return units
+}
+
+// unitsInOrder returns every unit in the game sorted by ID, so that
+// lifecycle windows resolve in the same order on every run.
+func (g *Game) unitsInOrder() []*core.Unit {
+	units := make([]*core.Unit, 0, len(g.Units))
+	for _, u := range g.Units {
+		units = append(units, u)
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i].ID < units[j].ID })
+	return units
+}
@@ -0,0 +1,163 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/phase"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+)
+
+func TestRunGame_LifecycleWindowOrder(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	g.CreateUnit("Warriors", 1, core.Stats{Health: 1}, nil, 1, core.Position{X: 5, Y: 5}, 1.0)
+	g.CreateUnit("Enemies", 2, core.Stats{Health: 1}, nil, 1, core.Position{X: 40, Y: 20}, 1.0)
+
+	var steps []string
+	record := func(trigger rules.Trigger, label string) {
+		g.Rules.AddRule(rules.Rule{
+			Name:    label,
+			Trigger: trigger,
+			Source:  rules.SourceFaction,
+			// Record once per window, not once per unit.
+			Condition: func(ctx *rules.Context) bool { return ctx.Unit == nil },
+			Apply: func(ctx *rules.Context) {
+				step := label
+				if ctx.PhaseType != "" {
+					step += " " + ctx.PhaseType
+				}
+				steps = append(steps, step)
+			},
+		})
+	}
+	record(rules.OnBattleRoundStart, "round start")
+	record(rules.OnTurnStart, "turn start")
+	record(rules.OnPhaseStart, "start")
+	record(rules.OnPhaseEnd, "end")
+	record(rules.OnTurnEnd, "turn end")
+	record(rules.OnBattleRoundEnd, "round end")
+
+	g.RunGame(1)
+
+	phases := phase.StandardTurnSequence()
+	want := []string{"round start"}
+	for turn := 0; turn < 2; turn++ {
+		want = append(want, "turn start")
+		for _, p := range phases {
+			want = append(want, "start "+string(p.Type), "end "+string(p.Type))
+		}
+		want = append(want, "turn end")
+	}
+	want = append(want, "round end")
+
+	if len(steps) != len(want) {
+		t.Fatalf("expected %d windows, got %d: %v", len(want), len(steps), steps)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("window %d: expected %q, got %q", i, want[i], steps[i])
+		}
+	}
+}
+
+func TestRunWindow_CarriesOutEffects(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	g.Commands.InitRound([]int{1, 2}, 4, -1)
+	hero := g.CreateUnit("Hero", 1, core.Stats{Health: 6}, nil, 1, core.Position{X: 5, Y: 5}, 1.0)
+	enemy := g.CreateUnit("Enemies", 2, core.Stats{Health: 2}, nil, 2, core.Position{X: 40, Y: 20}, 1.0)
+	hero.Models[0].CurrentWounds = 2
+
+	heroID := hero.ID
+	g.Rules.AddRule(rules.Rule{
+		Name:    "Rejuvenate",
+		Trigger: rules.OnPhaseStart,
+		Source:  rules.SourceEnhancement,
+		Condition: func(ctx *rules.Context) bool {
+			return ctx.Unit != nil && ctx.Unit.ID == heroID && ctx.PhaseType == string(phase.PhaseHero)
+		},
+		Apply: func(ctx *rules.Context) {
+			ctx.HealWounds(ctx.Unit, 3)
+			ctx.GrantCommandPoints(ctx.Unit.OwnerID, 1)
+		},
+	})
+	g.Rules.AddRule(rules.Rule{
+		Name:    "Blight",
+		Trigger: rules.OnPhaseStart,
+		Source:  rules.SourceFaction,
+		Condition: func(ctx *rules.Context) bool {
+			return ctx.Unit != nil && ctx.Unit.OwnerID != ctx.PlayerID
+		},
+		Apply: func(ctx *rules.Context) {
+			ctx.DealMortalWounds(ctx.Unit, 2)
+		},
+	})
+
+	g.ActivePlayer = 0
+	g.runWindow(rules.OnPhaseStart, phase.PhaseHero)
+
+	if hero.Models[0].CurrentWounds != 5 {
+		t.Errorf("expected hero healed to 5 wounds, got %d", hero.Models[0].CurrentWounds)
+	}
+	if cp := g.Commands.GetState(1).CommandPoints; cp != 5 {
+		t.Errorf("expected 5 CP for player 1, got %d", cp)
+	}
+	if enemy.AliveModels() != 1 {
+		t.Errorf("expected 2 mortal wounds to slay one enemy model, %d alive", enemy.AliveModels())
+	}
+	if !hasLog(g, "Hero heals 3 wound(s)") || !hasLog(g, "Enemies suffers 2 mortal damage") {
+		t.Errorf("expected effects to be logged, got %v", g.Log)
+	}
+
+	// Other phases leave the hero alone.
+	g.runWindow(rules.OnPhaseStart, phase.PhaseMovement)
+	if cp := g.Commands.GetState(1).CommandPoints; cp != 5 {
+		t.Errorf("expected CP unchanged outside the hero phase, got %d", cp)
+	}
+}
+
+func TestRunWindow_PlayerAndUnitScopedRulesFireOnce(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	g.Commands.InitRound([]int{1, 2}, 4, -1)
+	hero := g.CreateUnit("Hero", 1, core.Stats{Health: 6}, nil, 1, core.Position{X: 5, Y: 5}, 1.0)
+	g.CreateUnit("Warriors", 1, core.Stats{Health: 1}, nil, 5, core.Position{X: 10, Y: 5}, 1.0)
+	g.CreateUnit("Enemies", 2, core.Stats{Health: 1}, nil, 5, core.Position{X: 40, Y: 20}, 1.0)
+
+	playerFires, unitFires := 0, 0
+	g.Rules.AddRule(rules.Rule{
+		Name:      "Tithe",
+		Trigger:   rules.OnPhaseStart,
+		Source:    rules.SourceFaction,
+		Condition: func(ctx *rules.Context) bool { return ctx.Unit == nil },
+		Apply: func(ctx *rules.Context) {
+			playerFires++
+			ctx.GrantCommandPoints(ctx.PlayerID, 1)
+		},
+	})
+	heroID := hero.ID
+	g.Rules.AddRule(rules.Rule{
+		Name:      "Inspiring",
+		Trigger:   rules.OnPhaseStart,
+		Source:    rules.SourceUnitAbility,
+		Condition: func(ctx *rules.Context) bool { return ctx.Unit != nil && ctx.Unit.ID == heroID },
+		Apply:     func(ctx *rules.Context) { unitFires++ },
+	})
+
+	g.ActivePlayer = 0
+	g.runWindow(rules.OnPhaseStart, phase.PhaseHero)
+
+	if playerFires != 1 {
+		t.Errorf("expected the player-scoped rule to fire once with 3 units, fired %d times", playerFires)
+	}
+	if unitFires != 1 {
+		t.Errorf("expected the unit-scoped rule to fire once, fired %d times", unitFires)
+	}
+	if cp := g.Commands.GetState(1).CommandPoints; cp != 5 {
+		t.Errorf("expected 5 CP for player 1, got %d", cp)
+	}
+}
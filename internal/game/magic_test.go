@@ -221,7 +221,7 @@ func TestCastSpell_NotAWizard(t *testing.T) {
 }
 
 func TestCastSpell_PowerLevel(t *testing.T) {
-	g, wizard, enemy := setupWizardGame(42)
+	g, wizard, enemy := setupWizardGame(1)
 	wizard.PowerLevel = 2 // Wizard(2) can cast twice
 
 	cmd := &command.CastCommand{
@@ -263,7 +263,7 @@ func TestCastSpell_PowerLevel(t *testing.T) {
 }
 
 func TestCastSpell_SameSpellOncePerTurn(t *testing.T) {
-	g, wizard, enemy := setupWizardGame(42)
+	g, wizard, enemy := setupWizardGame(1)
 	wizard.PowerLevel = 3 // Enough power level
 
 	cmd := &command.CastCommand{
@@ -287,7 +287,7 @@ func TestCastSpell_SameSpellOncePerTurn(t *testing.T) {
 }
 
 func TestCastSpell_UnlimitedSpell(t *testing.T) {
-	g, wizard, enemy := setupWizardGame(42)
+	g, wizard, enemy := setupWizardGame(1)
 	wizard.PowerLevel = 3
 	wizard.Spells[0] = core.Spell{
 		Name: "Minor Bolt", CastingValue: 5, Range: 18,
@@ -0,0 +1,261 @@
+package game
+
+import (
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+)
+
+// "Mathhammer": exact odds of an attack, computed from the same attack
+// profile and per-die rules resolveAttacks rolls against, without rolling.
+// Each attack's dice are independent, so the distribution of one attack is
+// worked out face by face and combined over all attacks by convolution.
+// Stored dice (Destiny Dice) are not considered.
+
+// Distribution is a probability distribution over counts: d[n] is the
+// probability of exactly n.
+type Distribution []float64
+
+// Mean returns the expected count.
+func (d Distribution) Mean() float64 {
+	mean := 0.0
+	for n, p := range d {
+		mean += float64(n) * p
+	}
+	return mean
+}
+
+// AtLeast returns the probability of a count of n or more.
+func (d Distribution) AtLeast(n int) float64 {
+	if n < 0 {
+		n = 0
+	}
+	sum := 0.0
+	for i := n; i < len(d); i++ {
+		sum += d[i]
+	}
+	return sum
+}
+
+// Max returns the largest count with a non-zero probability.
+func (d Distribution) Max() int {
+	for n := len(d) - 1; n > 0; n-- {
+		if d[n] > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// convolve returns the distribution of the sum of independent counts
+// distributed as a and b.
+func convolve(a, b Distribution) Distribution {
+	if len(a) == 0 || len(b) == 0 {
+		return Distribution{1}
+	}
+	sum := make(Distribution, len(a)+len(b)-1)
+	for i, p := range a {
+		if p == 0 {
+			continue
+		}
+		for j, q := range b {
+			sum[i+j] += p * q
+		}
+	}
+	return sum
+}
+
+// WeaponOdds is the exact outcome distribution of one weapon's attacks.
+type WeaponOdds struct {
+	WeaponName string
+	Attacks    int
+	// Damage is the damage dealt, after ward saves.
+	Damage Distribution
+	// Trace lists the rules that applied to the attacks.
+	Trace []rules.Applied
+}
+
+// AttackOdds returns the exact distribution of the damage one weapon deals,
+// as ResolveAttacks would resolve it: hits with re-rolls and Crit abilities,
+// wounds, saves with Rend and Anti-X, damage including mortal wounds, and
+// ward saves, all with the modifiers of engine's rules.
+func AttackOdds(engine *rules.Engine, attacker *core.Unit, defender *core.Unit, weapon *core.Weapon, isShooting bool) WeaponOdds {
+	p := evaluateAttacks(engine, attacker, defender, weapon, isShooting, nil)
+
+	// Chance of each final roll once re-rolls are made.
+	hitFaces := faceOdds(func(roll int) bool { return rerollHit(p.rerollHit, roll, weapon.ToHit, p.hitMod) })
+	woundFaces := faceOdds(func(roll int) bool { return rerollHit(p.rerollWound, roll, weapon.ToWound, p.woundMod) })
+	saveFaces := faceOdds(func(roll int) bool { return rerolls(p.rerollSave, roll, saveFails(roll, p.saveThreshold)) })
+
+	wound, fail := 0.0, 1.0
+	for roll := 1; roll <= 6; roll++ {
+		if woundSucceeds(roll, weapon.ToWound, p.woundMod) {
+			wound += woundFaces[roll]
+		}
+	}
+	if p.saveThreshold <= 6 {
+		fail = 0
+		for roll := 1; roll <= 6; roll++ {
+			if saveFails(roll, p.saveThreshold) {
+				fail += saveFaces[roll]
+			}
+		}
+	}
+	unsaved := Distribution{1 - wound*fail, wound * fail} // a normal hit
+	autoUnsaved := Distribution{1 - fail, fail}           // an auto-wound
+
+	// Damage of a single attack, from each face of its hit roll.
+	perAttack := Distribution{}
+	for roll := 1; roll <= 6; roll++ {
+		var hr hitResult
+		hr.add(roll, weapon.ToHit, p.hitMod, weapon)
+		wounds := Distribution{1}
+		for i := 0; i < hr.Hits; i++ {
+			wounds = convolve(wounds, unsaved)
+		}
+		for i := 0; i < hr.AutoWounds; i++ {
+			wounds = convolve(wounds, autoUnsaved)
+		}
+		for n, q := range wounds {
+			perAttack = addAt(perAttack, n*p.damagePerWound+hr.CritMortals, hitFaces[roll]*q)
+		}
+	}
+
+	pool := Distribution{1}
+	for i := 0; i < p.attacks; i++ {
+		pool = convolve(pool, perAttack)
+	}
+	if p.ruleMortals > 0 {
+		pool = append(make(Distribution, p.ruleMortals), pool...)
+	}
+
+	return WeaponOdds{
+		WeaponName: weapon.Name,
+		Attacks:    p.attacks,
+		Damage:     applyWard(pool, p.ward),
+		Trace:      p.trace,
+	}
+}
+
+// faceOdds returns the chance of each final D6 result (index 1-6) when the
+// first roll is re-rolled once if reroll says so.
+func faceOdds(reroll func(roll int) bool) [7]float64 {
+	var faces [7]float64
+	for roll := 1; roll <= 6; roll++ {
+		if !reroll(roll) {
+			faces[roll] += 1.0 / 6
+			continue
+		}
+		for again := 1; again <= 6; again++ {
+			faces[again] += 1.0 / 36
+		}
+	}
+	return faces
+}
+
+// addAt adds p to d[n], growing d as needed.
+func addAt(d Distribution, n int, p float64) Distribution {
+	for len(d) <= n {
+		d = append(d, 0)
+	}
+	d[n] += p
+	return d
+}
+
+// applyWard returns the damage left of a damage pool after ward saves, one
+// D6 per point of damage (Rule 18.1).
+func applyWard(pool Distribution, ward int) Distribution {
+	if ward <= 0 || ward > 6 {
+		return pool
+	}
+	saved := float64(7-ward) / 6
+	point := Distribution{saved, 1 - saved} // Damage left of one point
+	left := make(Distribution, len(pool))
+	kept := Distribution{1} // Damage left of n points
+	for n, p := range pool {
+		if n > 0 {
+			kept = convolve(kept, point)
+		}
+		for k, q := range kept {
+			left[k] += p * q
+		}
+	}
+	return left
+}
+
+// DamageOdds is the exact outcome distribution of all of a unit's attacks of
+// one kind against another unit.
+type DamageOdds struct {
+	Weapons []WeaponOdds
+	// Damage is the total damage dealt, after ward saves. Weapons are not
+	// used once the defender is destroyed.
+	Damage Distribution
+	// Slain is the number of the defender's models slain.
+	Slain Distribution
+}
+
+// CombatOdds returns the exact odds of ResolveCombat: all of attacker's
+// melee weapons against defender.
+func CombatOdds(engine *rules.Engine, attacker *core.Unit, defender *core.Unit) DamageOdds {
+	return unitOdds(engine, attacker, defender, attacker.MeleeWeapons(), false)
+}
+
+// ShootingOdds returns the exact odds of ResolveShooting: all of attacker's
+// ranged weapons against defender.
+func ShootingOdds(engine *rules.Engine, attacker *core.Unit, defender *core.Unit) DamageOdds {
+	return unitOdds(engine, attacker, defender, attacker.RangedWeapons(), true)
+}
+
+func unitOdds(engine *rules.Engine, attacker *core.Unit, defender *core.Unit, weapons []int, isShooting bool) DamageOdds {
+	var odds DamageOdds
+	slain := slainByDamage(defender)
+	lethal := len(slain) - 1 // Damage that destroys the defender
+
+	total := Distribution{1}
+	for _, idx := range weapons {
+		w := AttackOdds(engine, attacker, defender, &attacker.Weapons[idx], isShooting)
+		odds.Weapons = append(odds.Weapons, w)
+
+		next := Distribution{}
+		for d, p := range total {
+			if p == 0 {
+				continue
+			}
+			if d >= lethal {
+				next = addAt(next, d, p)
+				continue
+			}
+			for n, q := range w.Damage {
+				next = addAt(next, d+n, p*q)
+			}
+		}
+		total = next
+	}
+	odds.Damage = total
+
+	for d, p := range total {
+		if d > lethal {
+			d = lethal
+		}
+		odds.Slain = addAt(odds.Slain, slain[d], p)
+	}
+	return odds
+}
+
+// slainByDamage returns the models of u slain by each amount of damage,
+// from 0 up to the damage that destroys it, allocated as AllocateDamage
+// would.
+func slainByDamage(u *core.Unit) []int {
+	slain, dead := []int{0}, 0
+	for _, m := range u.Models {
+		if !m.IsAlive {
+			continue
+		}
+		for w := m.CurrentWounds; w > 0; w-- {
+			if w == 1 {
+				dead++
+			}
+			slain = append(slain, dead)
+		}
+	}
+	return slain
+}
@@ -0,0 +1,146 @@
+package game
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+	"github.com/jruiznavarro/wargamestactics/pkg/dice"
+)
+
+func closeTo(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func mathhammerUnits(weapon core.Weapon, models int, save int) (*core.Unit, *core.Unit) {
+	attacker := newTestAttacker()
+	attacker.Weapons = []core.Weapon{weapon}
+	defender := newTestDefender()
+	defender.Stats.Save = save
+	defender.Models = nil
+	for i := 0; i < models; i++ {
+		defender.Models = append(defender.Models, core.Model{ID: i, CurrentWounds: 2, MaxWounds: 2, IsAlive: true})
+	}
+	return attacker, defender
+}
+
+func TestAttackOdds_SingleAttack(t *testing.T) {
+	// 4+ to hit, 4+ to wound, no save possible, Damage 2: 1/4 for 2 damage.
+	attacker, defender := mathhammerUnits(core.Weapon{Name: "Blade", Attacks: 1, ToHit: 4, ToWound: 4, Damage: 2}, 1, 7)
+
+	odds := AttackOdds(newTestEngine(), attacker, defender, &attacker.Weapons[0], false)
+
+	if len(odds.Damage) != 3 || !closeTo(odds.Damage[2], 0.25, 1e-12) || !closeTo(odds.Damage[0], 0.75, 1e-12) {
+		t.Errorf("expected 3/4 for 0 and 1/4 for 2 damage, got %v", odds.Damage)
+	}
+}
+
+func TestAttackOdds_RerollsAndModifiers(t *testing.T) {
+	attacker, defender := mathhammerUnits(core.Weapon{Name: "Blade", Attacks: 1, ToHit: 4, ToWound: 2, Damage: 1}, 1, 7)
+	engine := newTestEngine()
+	engine.AddRule(rules.Rule{
+		Name:    "Re-roll Ones",
+		Trigger: rules.BeforeHitRoll,
+		Source:  rules.SourceGlobal,
+		Apply: func(ctx *rules.Context) {
+			ctx.RerollHit = dice.RerollOnes
+			ctx.Modifiers.HitMod += 2 // Capped at +1
+		},
+	})
+
+	odds := AttackOdds(engine, attacker, defender, &attacker.Weapons[0], false)
+
+	// 3+ to hit re-rolling ones: 4/6 + 1/6 * 4/6 = 28/36; 2+ to wound: 5/6.
+	want := 28.0 / 36 * 5 / 6
+	if !closeTo(odds.Damage[1], want, 1e-12) {
+		t.Errorf("expected %.4f for 1 damage, got %.4f", want, odds.Damage[1])
+	}
+	if len(odds.Trace) == 0 {
+		t.Error("expected the rule in the trace")
+	}
+}
+
+func TestAttackOdds_CritMortalAndWard(t *testing.T) {
+	// Hits never succeed normally (7+), so only a 6 does anything: 3 mortal
+	// damage, then a 4+ ward per point.
+	attacker, defender := mathhammerUnits(core.Weapon{Name: "Fang", Attacks: 1, ToHit: 7, ToWound: 4, Damage: 3,
+		Abilities: core.AbilityCritMortal}, 3, 4)
+	defender.WardSave = 4
+
+	odds := AttackOdds(newTestEngine(), attacker, defender, &attacker.Weapons[0], false)
+
+	for n, want := range []float64{5.0/6 + 1.0/48, 3.0 / 48, 3.0 / 48, 1.0 / 48} {
+		if !closeTo(odds.Damage[n], want, 1e-12) {
+			t.Errorf("P(%d damage): expected %.4f, got %.4f", n, want, odds.Damage[n])
+		}
+	}
+}
+
+func TestCombatOdds_MatchesResolveAttacks(t *testing.T) {
+	weapon := core.Weapon{Name: "Claws", Attacks: 4, ToHit: 3, ToWound: 4, Rend: 1, Damage: 2,
+		Abilities: core.AbilityCrit2Hits | core.AbilityAntiInfantry}
+	engine := newTestEngine()
+	engine.AddRule(rules.Rule{
+		Name:    "Re-roll Failed Wounds",
+		Trigger: rules.BeforeWoundRoll,
+		Source:  rules.SourceGlobal,
+		Apply: func(ctx *rules.Context) {
+			ctx.RerollWound = dice.RerollFailed
+		},
+	})
+
+	attacker, defender := mathhammerUnits(weapon, 5, 4)
+	defender.Keywords = []core.Keyword{core.KeywordInfantry}
+	defender.WardSave = 6
+	odds := CombatOdds(engine, attacker, defender)
+
+	total := 0.0
+	for _, p := range odds.Slain {
+		total += p
+	}
+	if !closeTo(total, 1, 1e-9) {
+		t.Fatalf("expected slain probabilities to sum to 1, got %f", total)
+	}
+
+	const trials = 20000
+	damage, slain := 0, 0
+	roller := dice.NewRoller(7)
+	for i := 0; i < trials; i++ {
+		a, d := mathhammerUnits(weapon, 5, 4)
+		d.Keywords = defender.Keywords
+		d.WardSave = 6
+		for _, r := range ResolveCombat(roller, engine, a, d) {
+			damage += r.DamageDealt
+			slain += r.ModelsSlain
+		}
+	}
+	if got := float64(damage) / trials; !closeTo(got, odds.Damage.Mean(), 0.05) {
+		t.Errorf("expected mean damage %.3f, simulated %.3f", odds.Damage.Mean(), got)
+	}
+	if got := float64(slain) / trials; !closeTo(got, odds.Slain.Mean(), 0.03) {
+		t.Errorf("expected mean slain %.3f, simulated %.3f", odds.Slain.Mean(), got)
+	}
+}
+
+func TestCombatOdds_StopsOnceDestroyed(t *testing.T) {
+	// Two weapons of one 2-damage attack against a single 2-wound model: the
+	// second only attacks if the first fails.
+	attacker := newTestAttacker()
+	attacker.Weapons = []core.Weapon{
+		{Name: "First", Attacks: 1, ToHit: 1, ToWound: 1, Damage: 2, Abilities: core.AbilityCritAutoWound},
+		{Name: "Second", Attacks: 1, ToHit: 1, ToWound: 1, Damage: 2},
+	}
+	_, defender := mathhammerUnits(attacker.Weapons[0], 1, 7)
+
+	odds := CombatOdds(newTestEngine(), attacker, defender)
+
+	if odds.Damage.Max() != 2 || odds.Slain.Max() != 1 {
+		t.Errorf("expected at most 2 damage and 1 model slain, got damage %v, slain %v", odds.Damage, odds.Slain)
+	}
+	// Natural 1s fail. The first weapon's 6s wound automatically.
+	first, second := 1.0/6+4.0/6*5.0/6, 5.0/6*5.0/6
+	if want := first + (1-first)*second; !closeTo(odds.Damage[2], want, 1e-12) {
+		t.Errorf("expected %.4f for 2 damage, got %.4f", want, odds.Damage[2])
+	}
+}
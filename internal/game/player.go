@@ -4,6 +4,7 @@ import (
 	"github.com/jruiznavarro/wargamestactics/internal/game/command"
 	"github.com/jruiznavarro/wargamestactics/internal/game/core"
 	"github.com/jruiznavarro/wargamestactics/internal/game/phase"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
 )
 
 // Player is the interface that both human and AI players implement.
@@ -13,6 +14,12 @@ type Player interface {
 	Name() string
 }
 
+// HumanPlayer is implemented by players a person controls. The game keeps
+// asking a human player for commands however many are rejected.
+type HumanPlayer interface {
+	IsHuman() bool
+}
+
 // TerrainView is a read-only view of a terrain feature.
 type TerrainView struct {
 	Name   string
@@ -98,6 +105,9 @@ type GameView struct {
 	CommandPoints   map[int]int // CP remaining per player ID
 	VictoryPoints   map[int]int // VP per player ID
 	BattleTactics   map[int]*BattleTacticsView // playerID -> tactics view (GH 2025-26)
+	DestinyDice     map[int][]int // playerID -> stored dice (nil if no player has a pool)
+	LastResult      string          // Description of the most recent command's result
+	LastTrace       []rules.Applied // Rules that applied to that command, in order
 }
 
 // TerritoryView is a read-only view of a deployment zone.
@@ -134,6 +144,7 @@ type UnitView struct {
 	Prayers       []PrayerView
 	CanCast       bool
 	CanChant      bool
+	Enhancements  []string // Heroic trait / artefact names
 }
 
 // WeaponView is a read-only view of a weapon.
@@ -11,6 +11,8 @@ type Context struct {
 	// Units involved
 	Attacker *core.Unit // The acting unit (shooter, fighter, charger, mover)
 	Defender *core.Unit // The target unit (if applicable)
+	Unit     *core.Unit // The unit a lifecycle window is evaluated for (OnBattleRound*, OnTurn*, OnPhase*); nil for the player-level evaluation
+	Slain    []int      // Indices of the Defender's models slain by the attack (OnModelSlain, OnUnitDestroyed)
 
 	// Weapon being used (combat pipeline triggers)
 	Weapon *core.Weapon
@@ -29,6 +31,7 @@ type Context struct {
 	// Game state for faction rules
 	BattleRound int            // Current battle round number
 	AllUnits    []*core.Unit   // All alive units in the game (for proximity checks)
+	Grid        *core.Grid     // Spatial index of the units, for proximity queries (may be nil)
 	PlayerID    int            // Player whose turn it is
 
 	// Modifier accumulator -- rules write their modifiers here.
@@ -43,11 +46,26 @@ type Context struct {
 	// Ward override (for dynamic ward effects from faction rules)
 	WardOverride int // If > 0, overrides the unit's base ward save (lower = better)
 
+	// Rules that applied, in order, with what each changed (see Applied).
+	Trace []Applied
+
+	// Game actions queued by rules via AddEffect and its helpers, carried
+	// out by the engine's EffectHandler after evaluation.
+	Effects []Effect
+	rule    string // Rule currently being applied
+
 	// Control flags -- rules can set these to block an action.
 	Blocked      bool   // If true, the action is prevented
 	BlockMessage string // Reason for blocking
 }
 
+// Reset clears c for another evaluation, keeping the memory already
+// allocated for its Trace so that a context can be reused without
+// allocating.
+func (c *Context) Reset() {
+	*c = Context{Trace: c.Trace[:0]}
+}
+
 // Modifiers holds numeric modifiers that rules can accumulate.
 type Modifiers struct {
 	AttacksMod   int // Added to number of attacks (can be negative)
@@ -62,6 +80,37 @@ type Modifiers struct {
 	MortalWounds int // Mortal wounds to deal (bypasses saves)
 }
 
+// ModifierField selects one field of Modifiers.
+type ModifierField func(m *Modifiers) *int
+
+// Modifier fields rules commonly ignore the positive or negative part of.
+var (
+	HitModifier   ModifierField = func(m *Modifiers) *int { return &m.HitMod }
+	WoundModifier ModifierField = func(m *Modifiers) *int { return &m.WoundMod }
+	SaveModifier  ModifierField = func(m *Modifiers) *int { return &m.SaveMod }
+)
+
+// IgnoreNegative undoes every negative modifier to field that a rule applied
+// earlier in this evaluation, keeping the positive ones. Use it from a
+// LayerIgnore rule so that every other rule has applied first.
+func (c *Context) IgnoreNegative(field ModifierField) {
+	c.ignore(field, func(d int) bool { return d < 0 })
+}
+
+// IgnorePositive undoes every positive modifier to field that a rule applied
+// during the evaluation, keeping the negative ones.
+func (c *Context) IgnorePositive(field ModifierField) {
+	c.ignore(field, func(d int) bool { return d > 0 })
+}
+
+func (c *Context) ignore(field ModifierField, match func(delta int) bool) {
+	for i := range c.Trace {
+		if d := *field(&c.Trace[i].Delta); match(d) {
+			*field(&c.Modifiers) -= d
+		}
+	}
+}
+
 // Merge combines two modifier sets by adding them together.
 func (m *Modifiers) Merge(other Modifiers) {
 	m.AttacksMod += other.AttacksMod
@@ -0,0 +1,85 @@
+package rules
+
+import "github.com/jruiznavarro/wargamestactics/internal/game/core"
+
+// EffectKind identifies a game action a rule can ask for.
+type EffectKind int
+
+const (
+	EffectMortalWounds  EffectKind = iota // Deal mortal wounds to a unit
+	EffectHeal                            // Heal wounds allocated to a unit
+	EffectCommandPoints                   // Give a player command points
+	EffectSpawnUnit                       // Add a new unit to the battlefield
+	EffectFight                           // Let a unit fight immediately
+)
+
+// Effect is a game action queued by a rule. Rather than change the game
+// state from Apply, rules queue effects on the Context, and the engine hands
+// them to its EffectHandler once every rule for the trigger has been
+// evaluated, in the order they were queued.
+type Effect struct {
+	Kind     EffectKind
+	Rule     string      // Name of the rule that queued the effect (set by the engine)
+	UnitID   core.UnitID // Unit affected: wounded, healed or fighting
+	TargetID core.UnitID // Unit fought (EffectFight)
+	Models   []int       // Slain models of a destroyed unit that fight (EffectFight)
+	PlayerID int         // Player receiving command points
+	Amount   int         // Mortal wounds, wounds healed or command points
+	Spawn    *SpawnSpec  // Unit to add (EffectSpawnUnit)
+
+	// RollNeeded, if > 0, makes the effect happen only if a D6 rolled when
+	// it is carried out is equal to or greater than this value.
+	RollNeeded int
+}
+
+// SpawnSpec describes a unit added to the battlefield by EffectSpawnUnit.
+type SpawnSpec struct {
+	Name     string
+	OwnerID  int
+	Stats    core.Stats
+	Weapons  []core.Weapon
+	Models   int
+	Position core.Position
+	BaseSize float64
+	Keywords []core.Keyword
+	WardSave int
+}
+
+// EffectHandler carries out the effects queued while evaluating a trigger.
+type EffectHandler func(effects []Effect)
+
+// AddEffect queues an effect to be carried out after evaluation.
+func (c *Context) AddEffect(e Effect) {
+	e.Rule = c.rule
+	c.Effects = append(c.Effects, e)
+}
+
+// DealMortalWounds queues amount mortal wounds on target.
+func (c *Context) DealMortalWounds(target *core.Unit, amount int) {
+	c.AddEffect(Effect{Kind: EffectMortalWounds, UnitID: target.ID, Amount: amount})
+}
+
+// HealWounds queues healing amount wounds allocated to target.
+func (c *Context) HealWounds(target *core.Unit, amount int) {
+	c.AddEffect(Effect{Kind: EffectHeal, UnitID: target.ID, Amount: amount})
+}
+
+// GrantCommandPoints queues amount extra command points for a player.
+func (c *Context) GrantCommandPoints(playerID int, amount int) {
+	c.AddEffect(Effect{Kind: EffectCommandPoints, PlayerID: playerID, Amount: amount})
+}
+
+// SpawnUnit queues a new unit being set up on the battlefield.
+func (c *Context) SpawnUnit(spec SpawnSpec) {
+	c.AddEffect(Effect{Kind: EffectSpawnUnit, Spawn: &spec})
+}
+
+// FightImmediately queues unit fighting target with its melee weapons. If
+// unit is the Defender, the models slain by the attack fight too.
+func (c *Context) FightImmediately(unit, target *core.Unit) {
+	e := Effect{Kind: EffectFight, UnitID: unit.ID, TargetID: target.ID}
+	if unit == c.Defender {
+		e.Models = append([]int(nil), c.Slain...)
+	}
+	c.AddEffect(e)
+}
@@ -1,68 +1,203 @@
 package rules
 
+// Handle identifies a registered rule so it can be removed in O(1).
+type Handle int
+
+// entry is a registered rule. Removed entries stay in the per-trigger and
+// per-source lists until enough of them pile up to be worth compacting.
+type entry struct {
+	rule    Rule
+	handle  Handle
+	removed bool
+}
+
+// ruleList holds the rules for one trigger, in the order they apply, or for
+// one source.
+type ruleList struct {
+	entries []*entry
+	live    int
+}
+
 // Engine stores all active rules and evaluates them at hook points.
+// Rules are indexed by trigger, so an evaluation only looks at the rules for
+// its own trigger, and by source, so RemoveRulesBySource only looks at the
+// rules from that source.
 type Engine struct {
-	rules map[Trigger][]Rule
+	rules    []ruleList // Indexed by Trigger
+	bySource map[Source]*ruleList
+	handles  map[Handle]*entry
+	next     Handle
+	count    int
+	effects  EffectHandler
 }
 
 // NewEngine creates an empty rule engine.
 func NewEngine() *Engine {
 	return &Engine{
-		rules: make(map[Trigger][]Rule),
+		bySource: make(map[Source]*ruleList),
+		handles:  make(map[Handle]*entry),
 	}
 }
 
-// AddRule registers a rule in the engine.
-func (e *Engine) AddRule(r Rule) {
-	e.rules[r.Trigger] = append(e.rules[r.Trigger], r)
+// AddRule registers a rule in the engine, after the rules already registered
+// for its trigger with the same or an earlier layer and priority. The
+// returned handle removes it again with RemoveRule.
+func (e *Engine) AddRule(r Rule) Handle {
+	e.next++
+	en := &entry{rule: r, handle: e.next}
+	e.handles[en.handle] = en
+	src := e.bySource[r.Source]
+	if src == nil {
+		src = &ruleList{}
+		e.bySource[r.Source] = src
+	}
+	src.entries = append(src.entries, en)
+	src.live++
+	e.count++
+
+	for int(r.Trigger) >= len(e.rules) {
+		e.rules = append(e.rules, ruleList{})
+	}
+	list := &e.rules[r.Trigger]
+	i := len(list.entries)
+	for i > 0 && applyBefore(r, list.entries[i-1].rule) {
+		i--
+	}
+	// Build a new slice so an evaluation in progress keeps its own view.
+	entries := make([]*entry, 0, len(list.entries)+1)
+	entries = append(entries, list.entries[:i]...)
+	entries = append(entries, en)
+	list.entries = append(entries, list.entries[i:]...)
+	list.live++
+	return en.handle
+}
+
+// applyBefore reports whether rule a applies before rule b.
+func applyBefore(a, b Rule) bool {
+	if a.Layer != b.Layer {
+		return a.Layer < b.Layer
+	}
+	return a.Priority < b.Priority
+}
+
+// SetEffectHandler sets the handler that carries out the effects rules queue
+// on the context. Without one, queued effects stay in ctx.Effects.
+func (e *Engine) SetEffectHandler(h EffectHandler) {
+	e.effects = h
+}
+
+// RemoveRule removes the rule registered with the given handle. Returns
+// false if there is no such rule (it was never added or is already removed).
+func (e *Engine) RemoveRule(h Handle) bool {
+	en, ok := e.handles[h]
+	if !ok {
+		return false
+	}
+	e.remove(en)
+	e.compact(&e.rules[en.rule.Trigger])
+	e.compact(e.bySource[en.rule.Source])
+	return true
 }
 
 // RemoveRulesBySource removes rules from a given source.
 // If name is empty, removes all rules from that source.
 // If name is provided, only removes rules matching both source and name.
 func (e *Engine) RemoveRulesBySource(source Source, name string) {
-	for trigger, ruleList := range e.rules {
-		filtered := ruleList[:0]
-		for _, r := range ruleList {
-			match := r.Source == source && (name == "" || r.Name == name)
-			if !match {
-				filtered = append(filtered, r)
-			}
+	src := e.bySource[source]
+	if src == nil {
+		return
+	}
+	touched := make(map[Trigger]bool)
+	for _, en := range src.entries {
+		if en.removed || (name != "" && en.rule.Name != name) {
+			continue
+		}
+		e.remove(en)
+		touched[en.rule.Trigger] = true
+	}
+	for t := range touched {
+		e.compact(&e.rules[t])
+	}
+	e.compact(src)
+}
+
+func (e *Engine) remove(en *entry) {
+	en.removed = true
+	delete(e.handles, en.handle)
+	e.rules[en.rule.Trigger].live--
+	e.bySource[en.rule.Source].live--
+	e.count--
+}
+
+// compact drops removed rules from a list once they make up half of it, so
+// removal stays amortised O(1). The list gets a new slice so an evaluation
+// in progress keeps its own view.
+func (e *Engine) compact(list *ruleList) {
+	dead := len(list.entries) - list.live
+	if dead == 0 || dead*2 < len(list.entries) {
+		return
+	}
+	live := make([]*entry, 0, list.live)
+	for _, en := range list.entries {
+		if !en.removed {
+			live = append(live, en)
 		}
-		e.rules[trigger] = filtered
 	}
+	list.entries = live
 }
 
-// Evaluate runs all rules for the given trigger against the context.
-// Rules whose condition matches will apply their effect to ctx.
+// Evaluate runs all rules for the given trigger against the context, by layer,
+// then priority, then the order they were added.
+// Rules whose condition matches will apply their effect to ctx, and what each
+// one changed is recorded in ctx.Trace. A rule whose StackKey matches a rule
+// that already applied is skipped. Effects the
+// rules queued are then passed to the effect handler, in rule order, and
+// cleared from ctx.
 // Returns the (possibly modified) context.
 func (e *Engine) Evaluate(trigger Trigger, ctx *Context) *Context {
-	ruleList, ok := e.rules[trigger]
-	if !ok {
+	if int(trigger) >= len(e.rules) || e.rules[trigger].live == 0 {
 		return ctx
 	}
 
-	for _, r := range ruleList {
+	var stacked map[string]bool
+	for _, en := range e.rules[trigger].entries {
+		if en.removed {
+			continue
+		}
+		r := &en.rule
+		if r.StackKey != "" && stacked[r.StackKey] {
+			continue
+		}
 		if r.Condition != nil && !r.Condition(ctx) {
 			continue
 		}
+		if r.StackKey != "" {
+			if stacked == nil {
+				stacked = make(map[string]bool)
+			}
+			stacked[r.StackKey] = true
+		}
+		before := *ctx
+		ctx.rule = r.Name
 		r.Apply(ctx)
+		record(ctx, r, &before)
 	}
+	ctx.rule = ""
 
+	if e.effects != nil && len(ctx.Effects) > 0 {
+		effects := ctx.Effects
+		ctx.Effects = nil
+		e.effects(effects)
+	}
 	return ctx
 }
 
 // HasRulesFor returns true if there are any rules registered for a trigger.
 func (e *Engine) HasRulesFor(trigger Trigger) bool {
-	ruleList, ok := e.rules[trigger]
-	return ok && len(ruleList) > 0
+	return int(trigger) < len(e.rules) && e.rules[trigger].live > 0
 }
 
 // RuleCount returns the total number of registered rules.
 func (e *Engine) RuleCount() int {
-	total := 0
-	for _, ruleList := range e.rules {
-		total += len(ruleList)
-	}
-	return total
+	return e.count
 }
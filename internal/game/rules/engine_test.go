@@ -1,6 +1,8 @@
 package rules
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/jruiznavarro/wargamestactics/internal/game/core"
@@ -143,3 +145,224 @@ func TestEngineNoRulesForTrigger(t *testing.T) {
 		t.Error("expected no modifications with no rules")
 	}
 }
+
+func TestEngineEffectsQueuedInRuleOrder(t *testing.T) {
+	e := NewEngine()
+	target := &core.Unit{ID: 7}
+	e.AddRule(Rule{
+		Name:    "Blast",
+		Trigger: OnUnitDestroyed,
+		Source:  SourceUnitAbility,
+		Apply:   func(ctx *Context) { ctx.DealMortalWounds(target, 2) },
+	})
+	e.AddRule(Rule{
+		Name:    "Inspire",
+		Trigger: OnUnitDestroyed,
+		Source:  SourceFaction,
+		Apply:   func(ctx *Context) { ctx.GrantCommandPoints(1, 1) },
+	})
+
+	// Without a handler, effects stay on the context.
+	ctx := e.Evaluate(OnUnitDestroyed, &Context{})
+	if len(ctx.Effects) != 2 {
+		t.Fatalf("expected 2 queued effects, got %d", len(ctx.Effects))
+	}
+	if ctx.Effects[0].Rule != "Blast" || ctx.Effects[0].Kind != EffectMortalWounds || ctx.Effects[0].UnitID != 7 {
+		t.Errorf("unexpected first effect: %+v", ctx.Effects[0])
+	}
+	if ctx.Effects[1].Rule != "Inspire" || ctx.Effects[1].Kind != EffectCommandPoints {
+		t.Errorf("unexpected second effect: %+v", ctx.Effects[1])
+	}
+
+	var handled []Effect
+	e.SetEffectHandler(func(effects []Effect) { handled = append(handled, effects...) })
+	ctx = e.Evaluate(OnUnitDestroyed, &Context{})
+	if len(handled) != 2 || handled[0].Rule != "Blast" || handled[1].Rule != "Inspire" {
+		t.Errorf("expected handler to receive both effects in order, got %+v", handled)
+	}
+	if len(ctx.Effects) != 0 {
+		t.Errorf("expected handled effects to be cleared, got %+v", ctx.Effects)
+	}
+}
+
+func TestEngineTraceRecordsDeltas(t *testing.T) {
+	e := NewEngine()
+	e.AddRule(Rule{
+		Name:    "All-out Attack",
+		Trigger: BeforeHitRoll,
+		Source:  SourceGlobal,
+		Apply:   func(ctx *Context) { ctx.Modifiers.HitMod++ },
+	})
+	e.AddRule(Rule{
+		Name:      "Obscuring: Dark Woods",
+		Trigger:   BeforeHitRoll,
+		Source:    SourceTerrain,
+		Condition: func(ctx *Context) bool { return ctx.IsShooting },
+		Apply:     func(ctx *Context) { ctx.Modifiers.HitMod-- },
+	})
+
+	ctx := e.Evaluate(BeforeHitRoll, &Context{IsShooting: true})
+	if got, want := FormatTrace(ctx.Trace), "+1 hit (All-out Attack), -1 hit (Obscuring: Dark Woods)"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if ctx.Trace[1].Source != SourceTerrain || ctx.Trace[1].Source.String() != "terrain" {
+		t.Errorf("expected terrain source, got %v", ctx.Trace[1].Source)
+	}
+
+	// Rules whose condition fails are not recorded.
+	ctx = e.Evaluate(BeforeHitRoll, &Context{})
+	if len(ctx.Trace) != 1 {
+		t.Errorf("expected only All-out Attack in the trace, got %v", ctx.Trace)
+	}
+
+	e.AddRule(Rule{
+		Name:    "Amulet",
+		Trigger: BeforeWardSave,
+		Source:  SourceEnhancement,
+		Apply:   func(ctx *Context) { ctx.WardOverride = 5 },
+	})
+	e.AddRule(Rule{
+		Name:    "Impassable",
+		Trigger: BeforeWardSave,
+		Source:  SourceTerrain,
+		Apply: func(ctx *Context) {
+			ctx.Blocked = true
+			ctx.BlockMessage = "wall"
+		},
+	})
+	ctx = e.Evaluate(BeforeWardSave, &Context{})
+	if got, want := FormatTrace(ctx.Trace), "ward 5+ (Amulet), blocked: wall (Impassable)"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestEngineLayersAndPriority(t *testing.T) {
+	e := NewEngine()
+	var order []string
+	add := func(name string, layer Layer, priority int) {
+		e.AddRule(Rule{
+			Name: name, Trigger: BeforeHitRoll, Source: SourceGlobal, Layer: layer, Priority: priority,
+			Apply: func(ctx *Context) { order = append(order, name) },
+		})
+	}
+	add("ignore", LayerIgnore, 0)
+	add("add-late", LayerAdd, 5)
+	add("add-1", LayerAdd, 0)
+	add("clamp", LayerClamp, 0)
+	add("add-2", LayerAdd, 0)
+	add("set", LayerSet, 10)
+
+	e.Evaluate(BeforeHitRoll, &Context{})
+	want := []string{"set", "add-1", "add-2", "add-late", "clamp", "ignore"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("expected %v, got %v", want, order)
+	}
+}
+
+func TestEngineStackKey(t *testing.T) {
+	e := NewEngine()
+	for _, hero := range []string{"Hero A", "Hero B"} {
+		near := hero == "Hero B"
+		e.AddRule(Rule{
+			Name: hero + ": Inspiring Presence", Trigger: BeforeHitRoll, Source: SourceUnitAbility,
+			StackKey:  "Inspiring Presence",
+			Condition: func(ctx *Context) bool { return near || ctx.IsShooting },
+			Apply:     func(ctx *Context) { ctx.Modifiers.HitMod++ },
+		})
+	}
+
+	// Both auras reach the unit: only the first applies.
+	ctx := e.Evaluate(BeforeHitRoll, &Context{IsShooting: true})
+	if ctx.Modifiers.HitMod != 1 || len(ctx.Trace) != 1 || ctx.Trace[0].Rule != "Hero A: Inspiring Presence" {
+		t.Errorf("expected one +1 from Hero A, got %d %v", ctx.Modifiers.HitMod, ctx.Trace)
+	}
+
+	// A rule whose condition fails does not use up the key.
+	ctx = e.Evaluate(BeforeHitRoll, &Context{})
+	if ctx.Modifiers.HitMod != 1 || ctx.Trace[0].Rule != "Hero B: Inspiring Presence" {
+		t.Errorf("expected one +1 from Hero B, got %d %v", ctx.Modifiers.HitMod, ctx.Trace)
+	}
+}
+
+func TestContextIgnoreModifiers(t *testing.T) {
+	e := NewEngine()
+	for _, mod := range []int{+1, -1, -1} {
+		mod := mod
+		e.AddRule(Rule{
+			Name: fmt.Sprintf("%+d", mod), Trigger: BeforeWoundRoll, Source: SourceGlobal,
+			Apply: func(ctx *Context) { ctx.Modifiers.WoundMod += mod },
+		})
+	}
+
+	ctx := e.Evaluate(BeforeWoundRoll, &Context{})
+	ctx.IgnorePositive(WoundModifier)
+	if ctx.Modifiers.WoundMod != -2 {
+		t.Errorf("expected -2 once positives are ignored, got %d", ctx.Modifiers.WoundMod)
+	}
+
+	ctx = e.Evaluate(BeforeWoundRoll, &Context{})
+	ctx.IgnoreNegative(WoundModifier)
+	if ctx.Modifiers.WoundMod != 1 {
+		t.Errorf("expected +1 once negatives are ignored, got %d", ctx.Modifiers.WoundMod)
+	}
+}
+
+func TestEngineRemoveRule(t *testing.T) {
+	e := NewEngine()
+	var order []string
+	var handles []Handle
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("rule %d", i)
+		handles = append(handles, e.AddRule(Rule{
+			Name: name, Trigger: BeforeHitRoll, Source: SourceGlobal,
+			Apply: func(ctx *Context) { order = append(order, name) },
+		}))
+	}
+
+	// Remove most of them, enough to compact the lists.
+	for i, h := range handles {
+		if i != 2 && i != 7 && !e.RemoveRule(h) {
+			t.Errorf("expected rule %d to be removed", i)
+		}
+	}
+	if e.RemoveRule(handles[0]) {
+		t.Error("expected removing a rule twice to fail")
+	}
+	if e.RuleCount() != 2 {
+		t.Errorf("expected 2 rules left, got %d", e.RuleCount())
+	}
+
+	e.Evaluate(BeforeHitRoll, &Context{})
+	if want := []string{"rule 2", "rule 7"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("expected %v, got %v", want, order)
+	}
+
+	e.RemoveRulesBySource(SourceGlobal, "rule 7")
+	e.RemoveRule(handles[2])
+	if e.HasRulesFor(BeforeHitRoll) || e.RuleCount() != 0 {
+		t.Errorf("expected no rules left, got %d", e.RuleCount())
+	}
+}
+
+func BenchmarkEvaluate(b *testing.B) {
+	e := NewEngine()
+	for i := 0; i < 40; i++ {
+		id := core.UnitID(i)
+		e.AddRule(Rule{
+			Name:      "Hard to Hit",
+			Trigger:   BeforeHitRoll,
+			Source:    SourceUnitAbility,
+			Condition: func(ctx *Context) bool { return ctx.Defender != nil && ctx.Defender.ID == id },
+			Apply:     func(ctx *Context) { ctx.Modifiers.HitMod-- },
+		})
+	}
+	defender := &core.Unit{ID: 3}
+
+	ctx := &Context{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ctx.Reset()
+		ctx.Defender = defender
+		e.Evaluate(BeforeHitRoll, ctx)
+	}
+}
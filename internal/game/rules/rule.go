@@ -13,6 +13,19 @@ const (
 	SourceEnhancement               // Rule from enhancement (artefact/heroic trait)
 )
 
+// Layer is the step of the modifier pipeline a rule applies in. Within an
+// evaluation every rule in an earlier layer applies before any rule in a
+// later one: values are set, then added to, then clamped, and finally
+// positive or negative modifiers are ignored.
+type Layer int
+
+const (
+	LayerSet    Layer = iota - 1 // Set a value outright
+	LayerAdd                     // Add to the modifiers (the default)
+	LayerClamp                   // Cap the accumulated modifiers
+	LayerIgnore                  // Ignore positive or negative modifiers (Cold-blooded)
+)
+
 // Rule defines a single game rule that hooks into the engine.
 type Rule struct {
 	// Name is a human-readable identifier for logging/debugging.
@@ -24,6 +37,18 @@ type Rule struct {
 	// Source identifies where this rule comes from.
 	Source Source
 
+	// Layer is the pipeline step the rule applies in. Defaults to LayerAdd.
+	Layer Layer
+
+	// Priority orders rules within a layer: lower values apply first. Rules
+	// with the same layer and priority apply in the order they were added.
+	Priority int
+
+	// StackKey, if set, stops the rule stacking with itself: of the rules
+	// sharing a key, only the first whose condition matches applies in an
+	// evaluation (e.g. the same aura from two heroes).
+	StackKey string
+
 	// Condition returns true if this rule should apply given the current context.
 	// If nil, the rule always applies.
 	Condition func(ctx *Context) bool
@@ -0,0 +1,159 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jruiznavarro/wargamestactics/pkg/dice"
+)
+
+// Applied records a rule that applied during an evaluation and what it
+// changed on the context.
+type Applied struct {
+	Rule   string
+	Source Source
+	Delta  Modifiers // Change the rule made to ctx.Modifiers
+
+	Ward    int    // Ward save the rule granted, if it changed WardOverride
+	Blocked string // Block message, if the rule blocked the action
+	Rerolls []string
+	Effects int // Number of effects the rule queued
+}
+
+func (a Applied) String() string {
+	parts := a.changes()
+	if len(parts) == 0 {
+		return fmt.Sprintf("applied (%s)", a.Rule)
+	}
+	for i, p := range parts {
+		parts[i] = fmt.Sprintf("%s (%s)", p, a.Rule)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// changes lists what the rule did, e.g. "+1 hit" or "ward 5+".
+func (a Applied) changes() []string {
+	var parts []string
+	for _, d := range []struct {
+		value int
+		label string
+	}{
+		{a.Delta.AttacksMod, "attacks"},
+		{a.Delta.HitMod, "hit"},
+		{a.Delta.WoundMod, "wound"},
+		{a.Delta.SaveMod, "save"},
+		{a.Delta.RendMod, "rend"},
+		{a.Delta.DamageMod, "damage"},
+		{a.Delta.MoveMod, "move"},
+		{a.Delta.ChargeMod, "charge"},
+		{a.Delta.PileInMod, "pile-in"},
+		{a.Delta.MortalWounds, "mortal wounds"},
+	} {
+		if d.value != 0 {
+			parts = append(parts, fmt.Sprintf("%+d %s", d.value, d.label))
+		}
+	}
+	if a.Ward > 0 {
+		parts = append(parts, fmt.Sprintf("ward %d+", a.Ward))
+	}
+	for _, r := range a.Rerolls {
+		parts = append(parts, r)
+	}
+	if a.Blocked != "" {
+		parts = append(parts, "blocked: "+a.Blocked)
+	}
+	if a.Effects > 0 {
+		parts = append(parts, fmt.Sprintf("%d effect(s)", a.Effects))
+	}
+	return parts
+}
+
+// FormatTrace renders a trace on one line, e.g.
+// "+1 hit (All-out Attack), -1 hit (Obscuring: Dark Woods)".
+func FormatTrace(trace []Applied) string {
+	parts := make([]string, len(trace))
+	for i, a := range trace {
+		parts[i] = a.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (s Source) String() string {
+	switch s {
+	case SourceTerrain:
+		return "terrain"
+	case SourceUnitAbility:
+		return "unit ability"
+	case SourceWeapon:
+		return "weapon"
+	case SourceGlobal:
+		return "global"
+	case SourceFaction:
+		return "battle trait"
+	case SourceFormation:
+		return "formation"
+	case SourceEnhancement:
+		return "enhancement"
+	default:
+		return "unknown"
+	}
+}
+
+// record appends to ctx.Trace what rule r changed compared with before, a
+// copy of ctx taken just before r was applied.
+func record(ctx *Context, r *Rule, before *Context) {
+	a := Applied{
+		Rule:    r.Name,
+		Source:  r.Source,
+		Delta:   ctx.Modifiers.minus(before.Modifiers),
+		Effects: len(ctx.Effects) - len(before.Effects),
+	}
+	if ctx.WardOverride != before.WardOverride {
+		a.Ward = ctx.WardOverride
+	}
+	if ctx.Blocked && !before.Blocked {
+		a.Blocked = ctx.BlockMessage
+	}
+	for _, rr := range []struct {
+		now, was dice.RerollType
+		roll     string
+	}{
+		{ctx.RerollHit, before.RerollHit, "hit"},
+		{ctx.RerollWound, before.RerollWound, "wound"},
+		{ctx.RerollSave, before.RerollSave, "save"},
+	} {
+		if rr.now != rr.was {
+			a.Rerolls = append(a.Rerolls, rerollLabel(rr.now, rr.roll))
+		}
+	}
+	ctx.Trace = append(ctx.Trace, a)
+}
+
+func rerollLabel(t dice.RerollType, roll string) string {
+	switch t {
+	case dice.RerollFailed:
+		return "re-roll failed " + roll + " rolls"
+	case dice.RerollAll:
+		return "re-roll " + roll + " rolls"
+	case dice.RerollOnes:
+		return "re-roll " + roll + " rolls of 1"
+	default:
+		return "no " + roll + " re-roll"
+	}
+}
+
+// minus returns the field-by-field difference m - other.
+func (m Modifiers) minus(other Modifiers) Modifiers {
+	return Modifiers{
+		AttacksMod:   m.AttacksMod - other.AttacksMod,
+		HitMod:       m.HitMod - other.HitMod,
+		WoundMod:     m.WoundMod - other.WoundMod,
+		SaveMod:      m.SaveMod - other.SaveMod,
+		RendMod:      m.RendMod - other.RendMod,
+		DamageMod:    m.DamageMod - other.DamageMod,
+		MoveMod:      m.MoveMod - other.MoveMod,
+		ChargeMod:    m.ChargeMod - other.ChargeMod,
+		PileInMod:    m.PileInMod - other.PileInMod,
+		MortalWounds: m.MortalWounds - other.MortalWounds,
+	}
+}
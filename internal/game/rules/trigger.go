@@ -32,4 +32,12 @@ const (
 	OnModelSlain    // When a model is killed
 	OnUnitDestroyed // When a unit is fully destroyed
 	OnBattleRoundStart // When a new battle round begins
+
+	// Lifecycle triggers (see Game.runWindow for the order)
+	OnBattleRoundEnd // After both player turns of a battle round
+	OnTurnStart      // Start of a player turn
+	OnTurnEnd        // End of a player turn
+
+	// Magic triggers
+	AfterCast // After a spell is successfully cast, before unbinding
 )
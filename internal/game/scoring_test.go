@@ -977,3 +977,39 @@ func TestGhyranite_VPAccumulates(t *testing.T) {
 		t.Errorf("expected VP to accumulate to 4, got %d", g.VictoryPoints[1])
 	}
 }
+
+func TestRunGame_ScoresEachTurnAndDecidesWinner(t *testing.T) {
+	g := setupScoringGame(1)
+	g.MaxBattleRounds = 2
+	g.Board.AddObjective(core.Position{X: 24, Y: 12}, 6.0)
+	g.CreateUnit("P1 Warriors", 1, core.Stats{Move: 5, Save: 4, Control: 2, Health: 1}, nil, 10, core.Position{X: 24, Y: 12}, 1.0)
+	g.CreateUnit("P2 Warriors", 2, core.Stats{Move: 5, Save: 4, Control: 2, Health: 1}, nil, 10, core.Position{X: 2, Y: 2}, 1.0)
+
+	g.RunGame(2)
+
+	// 1 VP for holding an objective and 1 for holding more than P2, each turn
+	if g.VictoryPoints[1] != 4 || g.VictoryPoints[2] != 0 {
+		t.Errorf("expected 4-0 VP, got %d-%d", g.VictoryPoints[1], g.VictoryPoints[2])
+	}
+	if !g.IsOver || g.Winner != 1 {
+		t.Errorf("expected P1 to win on VP, got over=%v winner=%d", g.IsOver, g.Winner)
+	}
+}
+
+func TestRunGame_DrawOnEqualVP(t *testing.T) {
+	g := setupScoringGame(1)
+	g.MaxBattleRounds = 1
+	g.Board.AddObjective(core.Position{X: 6, Y: 6}, 3.0)
+	g.Board.AddObjective(core.Position{X: 42, Y: 18}, 3.0)
+	g.CreateUnit("P1 Warriors", 1, core.Stats{Move: 5, Save: 4, Control: 2, Health: 1}, nil, 10, core.Position{X: 6, Y: 6}, 1.0)
+	g.CreateUnit("P2 Warriors", 2, core.Stats{Move: 5, Save: 4, Control: 2, Health: 1}, nil, 10, core.Position{X: 42, Y: 18}, 1.0)
+
+	g.RunGame(1)
+
+	if g.VictoryPoints[1] != 1 || g.VictoryPoints[2] != 1 {
+		t.Errorf("expected 1-1 VP, got %d-%d", g.VictoryPoints[1], g.VictoryPoints[2])
+	}
+	if !g.IsOver || g.Winner != -1 {
+		t.Errorf("expected a draw, got over=%v winner=%d", g.IsOver, g.Winner)
+	}
+}
@@ -0,0 +1,111 @@
+package game
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/command"
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+	"github.com/jruiznavarro/wargamestactics/pkg/dice"
+)
+
+// Games played on a physical table don't need coordinates. Players declare
+// where a unit stands instead ("engaged with unit 7", "on objective 2"), and
+// the game moves the unit somewhere that makes every position-based rule
+// agree: within engagement range of its enemy, within range of the
+// objective, or back where it was deployed when it is clear of both.
+
+const (
+	// declaredEngagedGap is how far from the enemy's position a unit
+	// declared engaged is placed, well within the 3" engagement range.
+	declaredEngagedGap = 1.0
+	// declaredObjectiveDepth is how far towards the objective's centre,
+	// as a fraction of its radius, a unit declared on it is placed. Units
+	// coming from opposite sides then stay out of engagement range.
+	declaredObjectiveDepth = 0.9
+)
+
+func (g *Game) executeDeclare(cmd *command.DeclareCommand) (command.Result, error) {
+	unit := g.GetUnit(cmd.UnitID)
+	if unit == nil || unit.IsDestroyed() {
+		return command.Result{}, fmt.Errorf("unit %d not found", cmd.UnitID)
+	}
+	if unit.OwnerID != cmd.OwnerID {
+		return command.Result{}, fmt.Errorf("unit %d does not belong to player %d", cmd.UnitID, cmd.OwnerID)
+	}
+	if g.deployedAt == nil {
+		g.deployedAt = make(map[core.UnitID]core.Position)
+	}
+	home, ok := g.deployedAt[unit.ID]
+	if !ok {
+		home = unit.Position()
+		g.deployedAt[unit.ID] = home
+	}
+
+	var pos core.Position
+	var desc string
+	switch {
+	case cmd.EngagedWith != 0:
+		enemy := g.GetUnit(cmd.EngagedWith)
+		if enemy == nil || enemy.IsDestroyed() {
+			return command.Result{}, fmt.Errorf("unit %d not found", cmd.EngagedWith)
+		}
+		if enemy.OwnerID == unit.OwnerID {
+			return command.Result{}, fmt.Errorf("%s and %s are on the same side", unit.Name, enemy.Name)
+		}
+		pos = towards(enemy.Position(), unit.Position(), declaredEngagedGap)
+		desc = fmt.Sprintf("%s is engaged with %s", unit.Name, enemy.Name)
+	case cmd.Objective != 0:
+		var obj *core.Position
+		var radius float64
+		for _, o := range g.Board.Objectives {
+			if o.ID == cmd.Objective {
+				obj, radius = &o.Position, o.Radius
+			}
+		}
+		if obj == nil {
+			return command.Result{}, fmt.Errorf("objective %d not found", cmd.Objective)
+		}
+		pos = towards(*obj, home, radius*declaredObjectiveDepth)
+		desc = fmt.Sprintf("%s is on objective %d", unit.Name, cmd.Objective)
+	default:
+		pos = home
+		desc = fmt.Sprintf("%s is clear of combat and objectives", unit.Name)
+	}
+
+	g.placeUnit(unit, pos)
+	g.Logf("%s", desc)
+	return command.Result{Description: desc, Success: true}, nil
+}
+
+// towards returns the point d inches from from in the direction of to.
+func towards(from, to core.Position, d float64) core.Position {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	dist := math.Hypot(dx, dy)
+	if dist == 0 {
+		dx, dy, dist = 1, 0, 1
+	}
+	return core.Position{X: from.X + dx/dist*d, Y: from.Y + dy/dist*d}
+}
+
+func (g *Game) executeTactic(cmd *command.TacticCommand) (command.Result, error) {
+	tier := BattleTacticTier(cmd.Tier)
+	if tier < TierAffray || tier > TierDomination {
+		return command.Result{}, fmt.Errorf("unknown battle tactic tier %d", cmd.Tier)
+	}
+	if err := g.SelectBattleTactic(cmd.OwnerID, BattleTacticCardID(cmd.Card), tier); err != nil {
+		return command.Result{}, err
+	}
+	t := g.BattleTactics[cmd.OwnerID].ActiveTactic.Tactic
+	desc := fmt.Sprintf("%s picks battle tactic %s (%s)", g.playerName(cmd.OwnerID), t.Name, t.Tier)
+	return command.Result{Description: desc, Success: true}, nil
+}
+
+// remind shows the person rolling which rules modify the coming roll, for
+// dice sources that take reminders (see dice.Reminder).
+func remind(roller *dice.Roller, what string, trace []rules.Applied) {
+	if len(trace) > 0 && roller.Reminds() {
+		roller.Remind(what + ": " + rules.FormatTrace(trace))
+	}
+}
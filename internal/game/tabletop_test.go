@@ -0,0 +1,193 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/command"
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+	"github.com/jruiznavarro/wargamestactics/pkg/dice"
+)
+
+// --- Tabletop Companion Tests ---
+
+func setupTabletopGame() (*Game, *core.Unit, *core.Unit) {
+	g := setupScoringGame(1)
+	g.Board.AddObjective(core.Position{X: 24, Y: 12}, 6.0)
+	ours := g.CreateUnit("P1 Warriors", 1,
+		core.Stats{Move: 5, Save: 4, Control: 1, Health: 1},
+		nil, 5, core.Position{X: 4, Y: 4}, 1.0)
+	theirs := g.CreateUnit("P2 Warriors", 2,
+		core.Stats{Move: 5, Save: 4, Control: 1, Health: 1},
+		nil, 8, core.Position{X: 44, Y: 20}, 1.0)
+	return g, ours, theirs
+}
+
+func TestDeclare_Engaged(t *testing.T) {
+	g, ours, theirs := setupTabletopGame()
+
+	_, err := g.ExecuteCommand(&command.DeclareCommand{OwnerID: 1, UnitID: ours.ID, EngagedWith: theirs.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !g.isEngaged(ours) || !g.isEngaged(theirs) {
+		t.Error("expected both units to be engaged after declaring it")
+	}
+}
+
+func TestDeclare_EngagedWithOwnUnit(t *testing.T) {
+	g, ours, _ := setupTabletopGame()
+	friend := g.CreateUnit("P1 Guard", 1,
+		core.Stats{Move: 5, Save: 4, Control: 1, Health: 1},
+		nil, 5, core.Position{X: 20, Y: 20}, 1.0)
+
+	_, err := g.ExecuteCommand(&command.DeclareCommand{OwnerID: 1, UnitID: ours.ID, EngagedWith: friend.ID})
+	if err == nil {
+		t.Error("expected error declaring a unit engaged with a friendly unit")
+	}
+}
+
+func TestDeclare_OtherPlayersUnit(t *testing.T) {
+	g, _, theirs := setupTabletopGame()
+
+	_, err := g.ExecuteCommand(&command.DeclareCommand{OwnerID: 1, UnitID: theirs.ID, Objective: 1})
+	if err == nil {
+		t.Error("expected error declaring the position of an enemy unit")
+	}
+}
+
+func TestDeclare_OnObjective(t *testing.T) {
+	g, ours, theirs := setupTabletopGame()
+
+	g.ExecuteCommand(&command.DeclareCommand{OwnerID: 1, UnitID: ours.ID, Objective: 1})
+	g.CalculateObjectiveControl()
+	if g.ObjectiveControl[1] != 1 {
+		t.Fatalf("expected P1 to control objective 1, got controller %d", g.ObjectiveControl[1])
+	}
+
+	// Units declared on the same objective from opposite sides contest it
+	// without being placed in combat; P2's 8 models take it.
+	g.ExecuteCommand(&command.DeclareCommand{OwnerID: 2, UnitID: theirs.ID, Objective: 1})
+	g.CalculateObjectiveControl()
+	if g.ObjectiveControl[1] != 2 {
+		t.Errorf("expected P2 to take objective 1, got controller %d", g.ObjectiveControl[1])
+	}
+	if g.isEngaged(ours) {
+		t.Error("expected units on an objective not to be engaged")
+	}
+}
+
+func TestDeclare_UnknownObjective(t *testing.T) {
+	g, ours, _ := setupTabletopGame()
+
+	_, err := g.ExecuteCommand(&command.DeclareCommand{OwnerID: 1, UnitID: ours.ID, Objective: 9})
+	if err == nil {
+		t.Error("expected error for unknown objective")
+	}
+}
+
+func TestDeclare_ClearReturnsToDeployment(t *testing.T) {
+	g, ours, theirs := setupTabletopGame()
+	home := ours.Position()
+
+	g.ExecuteCommand(&command.DeclareCommand{OwnerID: 1, UnitID: ours.ID, EngagedWith: theirs.ID})
+	g.ExecuteCommand(&command.DeclareCommand{OwnerID: 1, UnitID: ours.ID, Objective: 1})
+	g.ExecuteCommand(&command.DeclareCommand{OwnerID: 1, UnitID: ours.ID})
+
+	if ours.Position() != home {
+		t.Errorf("expected unit back at %v, got %v", home, ours.Position())
+	}
+	if g.isEngaged(ours) {
+		t.Error("expected cleared unit not to be engaged")
+	}
+	if got, _ := g.Grid.Nearest(home, nil); got != ours {
+		t.Error("expected the grid to follow the declared position")
+	}
+}
+
+func TestTacticCommand_SelectsTactic(t *testing.T) {
+	g := setupBattleTacticGame(1)
+
+	result, err := g.ExecuteCommand(&command.TacticCommand{OwnerID: 1, Card: int(CardBrokenRanks), Tier: int(TierStrike)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Description, "Shatter the Lines") {
+		t.Errorf("expected result to name the tactic, got %q", result.Description)
+	}
+	if g.BattleTactics[1].ActiveTactic == nil {
+		t.Error("expected active tactic after the command")
+	}
+}
+
+func TestTacticCommand_InvalidTier(t *testing.T) {
+	g := setupBattleTacticGame(1)
+
+	_, err := g.ExecuteCommand(&command.TacticCommand{OwnerID: 1, Card: int(CardBrokenRanks), Tier: 7})
+	if err == nil {
+		t.Error("expected error for invalid tier")
+	}
+}
+
+// remindedScript is a Script that also records reminders.
+type remindedScript struct {
+	*dice.Script
+	notes []string
+}
+
+func (s *remindedScript) Remind(note string) {
+	s.notes = append(s.notes, note)
+}
+
+func TestResolveAttacks_RemindsModifiers(t *testing.T) {
+	src := &remindedScript{Script: dice.NewScript(6, 6, 6, 6, 6, 6, 1, 1, 1)}
+	roller := dice.NewRollerFromSource(src)
+	engine := newTestEngine()
+	engine.AddRule(rules.Rule{
+		Name:    "All-out Attack",
+		Trigger: rules.BeforeHitRoll,
+		Source:  rules.SourceGlobal,
+		Apply: func(ctx *rules.Context) {
+			ctx.Modifiers.HitMod++
+		},
+	})
+	attacker := newTestAttacker()
+
+	ResolveAttacks(roller, engine, attacker, newTestDefender(), &attacker.Weapons[0], false)
+
+	if len(src.notes) != 1 || !strings.HasPrefix(src.notes[0], "hit: ") || !strings.Contains(src.notes[0], "All-out Attack") {
+		t.Errorf("expected one hit reminder naming All-out Attack, got %q", src.notes)
+	}
+}
+
+func TestRemindsWardModifiers(t *testing.T) {
+	wardRule := rules.Rule{
+		Name:    "Amulet of Destiny",
+		Trigger: rules.BeforeWardSave,
+		Source:  rules.SourceEnhancement,
+		Apply: func(ctx *rules.Context) {
+			ctx.WardOverride = 5
+		},
+	}
+
+	// Three unsaved wounds of 2 damage each: six ward rolls.
+	src := &remindedScript{Script: dice.NewScript(6, 6, 6, 6, 6, 6, 1, 1, 1, 1, 1, 1, 1, 1, 1)}
+	engine := newTestEngine()
+	engine.AddRule(wardRule)
+	attacker := newTestAttacker()
+	ResolveAttacks(dice.NewRollerFromSource(src), engine, attacker, newTestDefender(), &attacker.Weapons[0], false)
+	if len(src.notes) != 1 || !strings.HasPrefix(src.notes[0], "ward: ") || !strings.Contains(src.notes[0], "Amulet of Destiny") {
+		t.Errorf("expected one ward reminder naming Amulet of Destiny in combat, got %q", src.notes)
+	}
+
+	g := NewGame(42, 48, 24)
+	src = &remindedScript{Script: dice.NewScript(1, 1)}
+	g.Roller = dice.NewRollerFromSource(src)
+	g.Rules.AddRule(wardRule)
+	target := g.CreateUnit("Hero", 2, core.Stats{Health: 5}, nil, 1, core.Position{X: 10, Y: 10}, 1.0)
+	g.applyMortalWounds(nil, target, 2, DamageOther)
+	if len(src.notes) != 1 || !strings.HasPrefix(src.notes[0], "ward: ") || !strings.Contains(src.notes[0], "Amulet of Destiny") {
+		t.Errorf("expected one ward reminder naming Amulet of Destiny for mortal wounds, got %q", src.notes)
+	}
+}
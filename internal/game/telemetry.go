@@ -0,0 +1,226 @@
+package game
+
+import (
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+)
+
+// DamageKind is how damage was inflicted.
+type DamageKind int
+
+const (
+	DamageMelee    DamageKind = iota // Melee attacks
+	DamageShooting                   // Shooting attacks
+	DamageSpells                     // Spells and prayers, including miscasts
+	DamageOther                      // Abilities, commands and retreating
+	NumDamageKinds
+)
+
+func (k DamageKind) String() string {
+	switch k {
+	case DamageMelee:
+		return "Melee"
+	case DamageShooting:
+		return "Shooting"
+	case DamageSpells:
+		return "Spells"
+	default:
+		return "Other"
+	}
+}
+
+// UnitRecord is what one unit did over one game.
+type UnitRecord struct {
+	UnitID      core.UnitID
+	OwnerID     int
+	Name        string
+	WarscrollID string
+	Points      int
+
+	DamageDealt    [NumDamageKinds]int // Damage inflicted on enemy units
+	DamageTaken    [NumDamageKinds]int // Damage suffered, whoever inflicted it
+	ModelsSlain    int                 // Enemy models slain
+	UnitsDestroyed int                 // Enemy units destroyed
+	ObjectiveTurns int                 // Turns ended contesting an objective
+	ControlVP      float64             // Objective VP scored, shared by control score
+	TacticsEnabled int                 // Completed battle tactics it counted towards
+	DestroyedRound int                 // Battle round it was destroyed in, 0 if it survived
+}
+
+// TotalDealt returns the damage the unit inflicted of every kind.
+func (r *UnitRecord) TotalDealt() int {
+	total := 0
+	for _, d := range r.DamageDealt {
+		total += d
+	}
+	return total
+}
+
+// TotalTaken returns the damage the unit suffered of every kind.
+func (r *UnitRecord) TotalTaken() int {
+	total := 0
+	for _, d := range r.DamageTaken {
+		total += d
+	}
+	return total
+}
+
+// Telemetry records what each unit does over a game. A game records
+// nothing unless its Telemetry is set.
+type Telemetry struct {
+	units     map[core.UnitID]*UnitRecord
+	turnKills map[core.UnitID]int // Enemy units each unit destroyed this turn
+}
+
+// NewTelemetry returns an empty Telemetry.
+func NewTelemetry() *Telemetry {
+	return &Telemetry{
+		units:     make(map[core.UnitID]*UnitRecord),
+		turnKills: make(map[core.UnitID]int),
+	}
+}
+
+func (t *Telemetry) unit(u *core.Unit) *UnitRecord {
+	r, ok := t.units[u.ID]
+	if !ok {
+		r = &UnitRecord{UnitID: u.ID, OwnerID: u.OwnerID, Name: u.Name, WarscrollID: u.WarscrollID, Points: u.Points}
+		t.units[u.ID] = r
+	}
+	return r
+}
+
+// UnitRecords returns the record of every unit of the game in ID order, or
+// nil if the game has no Telemetry.
+func (g *Game) UnitRecords() []UnitRecord {
+	if g.Telemetry == nil {
+		return nil
+	}
+	var records []UnitRecord
+	for _, u := range g.unitsInOrder() {
+		records = append(records, *g.Telemetry.unit(u))
+	}
+	return records
+}
+
+// recordDamage records damage inflicted on target by source, which is nil
+// if no unit inflicted it.
+func (g *Game) recordDamage(source, target *core.Unit, kind DamageKind, damage, slain int) {
+	if g.Telemetry == nil || damage == 0 {
+		return
+	}
+	t := g.Telemetry.unit(target)
+	t.DamageTaken[kind] += damage
+	destroyed := target.IsDestroyed() && t.DestroyedRound == 0
+	if destroyed {
+		t.DestroyedRound = g.BattleRound
+	}
+	if source == nil || source.OwnerID == target.OwnerID {
+		return
+	}
+	s := g.Telemetry.unit(source)
+	s.DamageDealt[kind] += damage
+	s.ModelsSlain += slain
+	if destroyed {
+		s.UnitsDestroyed++
+		g.Telemetry.turnKills[source.ID]++
+	}
+}
+
+// recordObjectives records, at the end of a player's turn, every unit
+// contesting an objective, and shares the objective VP the player scored
+// between its units on the objectives it controls by control score.
+func (g *Game) recordObjectives(playerID, scored int) {
+	if g.Telemetry == nil {
+		return
+	}
+	shares := make(map[*UnitRecord]int)
+	total := 0
+	for _, u := range g.unitsInOrder() {
+		obj, ok := g.contesting[u.ID]
+		if !ok || u.IsDestroyed() {
+			continue
+		}
+		r := g.Telemetry.unit(u)
+		r.ObjectiveTurns++
+		if u.OwnerID == playerID && g.ObjectiveControl[obj] == playerID {
+			share := u.AliveModels() * u.Stats.Control
+			shares[r] += share
+			total += share
+		}
+	}
+	if scored == 0 || total == 0 {
+		return
+	}
+	for r, share := range shares {
+		r.ControlVP += float64(scored) * float64(share) / float64(total)
+	}
+}
+
+// recordTactic credits a completed battle tactic to the units that counted
+// towards it.
+func (g *Game) recordTactic(playerID int, tactic BattleTactic) {
+	if g.Telemetry == nil {
+		return
+	}
+	for _, u := range g.unitsInOrder() {
+		if u.OwnerID == playerID && !u.IsDestroyed() && g.countsTowards(u, tactic) {
+			g.Telemetry.unit(u).TacticsEnabled++
+		}
+	}
+}
+
+// countsTowards reports whether a unit helps meet a battle tactic's
+// condition, mirroring EvaluateBattleTactic.
+func (g *Game) countsTowards(u *core.Unit, tactic BattleTactic) bool {
+	mine, enemy, hasTerritories := g.territoriesOf(u.OwnerID)
+	inEnemy := hasTerritories && enemy.Contains(u.Position())
+
+	switch tactic.CardID {
+	case CardBrokenRanks:
+		return g.Telemetry.turnKills[u.ID] > 0
+	case CardConquerAndHold:
+		return inEnemy
+	case CardFerocousAdvance:
+		switch tactic.Tier {
+		case TierAffray:
+			return u.HasRun || u.HasCharged
+		case TierStrike:
+			return u.HasFought
+		default:
+			return u.HasCharged || g.Telemetry.turnKills[u.ID] > 0
+		}
+	case CardScoutingForce:
+		if u.HasKeyword(core.KeywordHero) || !hasTerritories {
+			return false
+		}
+		if tactic.Tier == TierAffray {
+			return !mine.Contains(u.Position())
+		}
+		return inEnemy
+	case CardAttunedToGhyran:
+		if tactic.Tier == TierAffray {
+			centre := core.Position{X: g.Board.Width / 2, Y: g.Board.Height / 2}
+			return !g.isEngaged(u) && core.Distance(u.Position(), centre) <= 12.0
+		}
+	}
+	// Tactics won by controlling objectives.
+	obj, ok := g.contesting[u.ID]
+	return ok && g.ObjectiveControl[obj] == u.OwnerID
+}
+
+// territoriesOf returns a player's territory and the enemy's, if the game
+// has a battleplan.
+func (g *Game) territoriesOf(playerID int) (mine, enemy board.Territory, ok bool) {
+	if g.Battleplan == nil || len(g.Players) < 2 {
+		return mine, enemy, false
+	}
+	if g.Players[1].ID() == playerID {
+		return g.Battleplan.Territories[1], g.Battleplan.Territories[0], true
+	}
+	return g.Battleplan.Territories[0], g.Battleplan.Territories[1], true
+}
+
+// startTurn clears the per-turn counts.
+func (t *Telemetry) startTurn() {
+	clear(t.turnKills)
+}
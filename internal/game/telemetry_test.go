@@ -0,0 +1,150 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/command"
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+)
+
+func TestTelemetry_RecordsShootingDamage(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.Telemetry = NewTelemetry()
+	bow := []core.Weapon{{Name: "Bow", Range: 18, Attacks: 4, ToHit: 3, ToWound: 3, Damage: 1}}
+	archers := g.CreateUnit("Archers", 1, core.Stats{Move: 5, Save: 5, Control: 1, Health: 1}, bow, 5, core.Position{X: 10, Y: 10}, 1.0)
+	target := g.CreateUnit("Target", 2, core.Stats{Move: 4, Save: 6, Control: 1, Health: 2}, nil, 10, core.Position{X: 20, Y: 10}, 1.0)
+	before := target.TotalCurrentWounds()
+
+	if _, err := g.ExecuteCommand(&command.ShootCommand{OwnerID: 1, ShooterID: archers.ID, TargetID: target.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records := g.UnitRecords()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	dealt := before - target.TotalCurrentWounds()
+	if dealt == 0 {
+		t.Fatal("expected the volley to cause damage")
+	}
+	if got := records[0].DamageDealt[DamageShooting]; got != dealt || records[0].TotalDealt() != dealt {
+		t.Errorf("expected %d shooting damage dealt, got %+v", dealt, records[0].DamageDealt)
+	}
+	if got := records[1].DamageTaken[DamageShooting]; got != dealt || records[1].TotalTaken() != dealt {
+		t.Errorf("expected %d shooting damage taken, got %+v", dealt, records[1].DamageTaken)
+	}
+	if records[1].ModelsSlain != 0 || records[0].ModelsSlain != 10-target.AliveModels() {
+		t.Errorf("expected %d models slain by the archers, got %d", 10-target.AliveModels(), records[0].ModelsSlain)
+	}
+}
+
+func TestTelemetry_RecordsDestruction(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.Telemetry = NewTelemetry()
+	g.BattleRound = 2
+	axe := []core.Weapon{{Name: "Axe", Range: 0, Attacks: 10, ToHit: 2, ToWound: 2, Rend: 3, Damage: 3}}
+	attacker := g.CreateUnit("Brute", 1, core.Stats{Health: 5}, axe, 1, core.Position{X: 10, Y: 10}, 1.0)
+	victim := g.CreateUnit("Victim", 2, core.Stats{Health: 2, Save: 6}, nil, 1, core.Position{X: 11, Y: 10}, 1.0)
+
+	if _, err := g.ExecuteCommand(&command.FightCommand{OwnerID: 1, AttackerID: attacker.ID, TargetID: victim.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !victim.IsDestroyed() {
+		t.Fatal("expected the victim to be destroyed")
+	}
+
+	records := g.UnitRecords()
+	if records[1].DestroyedRound != 2 {
+		t.Errorf("expected the victim destroyed in round 2, got %d", records[1].DestroyedRound)
+	}
+	if records[0].UnitsDestroyed != 1 || records[0].ModelsSlain != 1 || records[0].DamageDealt[DamageMelee] == 0 {
+		t.Errorf("expected the kill credited to the brute, got %+v", records[0])
+	}
+	if records[0].DestroyedRound != 0 {
+		t.Errorf("expected the brute to survive, got round %d", records[0].DestroyedRound)
+	}
+}
+
+func TestTelemetry_SpellDamage(t *testing.T) {
+	for seed := int64(1); seed < 200; seed++ {
+		g, wizard, enemy := setupWizardGame(seed)
+		g.Telemetry = NewTelemetry()
+		result, err := g.ExecuteCommand(&command.CastCommand{OwnerID: 1, CasterID: wizard.ID, SpellIndex: 0, TargetID: enemy.ID})
+		if err != nil {
+			t.Fatalf("seed %d: unexpected error: %v", seed, err)
+		}
+		if !result.Success {
+			continue
+		}
+		records := g.UnitRecords()
+		if records[0].DamageDealt[DamageSpells] == 0 || records[1].DamageTaken[DamageSpells] != records[0].DamageDealt[DamageSpells] {
+			t.Errorf("seed %d: expected spell damage from caster to target, got %+v and %+v",
+				seed, records[0].DamageDealt, records[1].DamageTaken)
+		}
+		return
+	}
+	t.Fatal("no seed found where damage spell succeeds within 200 attempts")
+}
+
+func TestTelemetry_SharesObjectiveVPByControl(t *testing.T) {
+	g := setupScoringGame(1)
+	g.Telemetry = NewTelemetry()
+	g.Board.AddObjective(core.Position{X: 24, Y: 12}, 6.0)
+	g.CreateUnit("Few", 1, core.Stats{Control: 1, Health: 1}, nil, 2, core.Position{X: 24, Y: 12}, 1.0)
+	g.CreateUnit("Many", 1, core.Stats{Control: 1, Health: 1}, nil, 6, core.Position{X: 25, Y: 12}, 1.0)
+	g.CreateUnit("Outnumbered", 2, core.Stats{Control: 1, Health: 1}, nil, 1, core.Position{X: 23, Y: 12}, 1.0)
+	g.CreateUnit("Elsewhere", 1, core.Stats{Control: 1, Health: 1}, nil, 5, core.Position{X: 2, Y: 2}, 1.0)
+
+	if scored := g.ScoreEndOfTurnAuto(1); scored != 2 {
+		t.Fatalf("expected 2 VP, got %d", scored)
+	}
+
+	records := g.UnitRecords()
+	if !closeTo(records[0].ControlVP, 0.5, 1e-9) || !closeTo(records[1].ControlVP, 1.5, 1e-9) {
+		t.Errorf("expected 0.5 and 1.5 VP, got %.2f and %.2f", records[0].ControlVP, records[1].ControlVP)
+	}
+	for i, want := range []int{1, 1, 1, 0} {
+		if records[i].ObjectiveTurns != want {
+			t.Errorf("%s: expected %d objective turns, got %d", records[i].Name, want, records[i].ObjectiveTurns)
+		}
+	}
+	if records[2].ControlVP != 0 {
+		t.Errorf("expected no VP for the outnumbered unit, got %.2f", records[2].ControlVP)
+	}
+}
+
+func TestTelemetry_CreditsBattleTactic(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.AddPlayer(&stubPlayer{id: 1, name: "P1"})
+	g.AddPlayer(&stubPlayer{id: 2, name: "P2"})
+	g.InitBattleTactics()
+	g.Telemetry = NewTelemetry()
+	axe := []core.Weapon{{Name: "Axe", Range: 0, Attacks: 10, ToHit: 2, ToWound: 2, Rend: 3, Damage: 3}}
+	brute := g.CreateUnit("Brute", 1, core.Stats{Health: 5}, axe, 1, core.Position{X: 10, Y: 10}, 1.0)
+	g.CreateUnit("Idler", 1, core.Stats{Health: 5}, nil, 1, core.Position{X: 30, Y: 10}, 1.0)
+	victim := g.CreateUnit("Victim", 2, core.Stats{Health: 2, Save: 6}, nil, 1, core.Position{X: 11, Y: 10}, 1.0)
+
+	if _, err := g.ExecuteCommand(&command.FightCommand{OwnerID: 1, AttackerID: brute.ID, TargetID: victim.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	g.UnitsDestroyedThisTurnMap[1] = 1
+	if err := g.SelectBattleTactic(1, CardBrokenRanks, TierAffray); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vp := g.EvaluateAndScoreBattleTactic(1); vp == 0 {
+		t.Fatal("expected the battle tactic to be completed")
+	}
+
+	records := g.UnitRecords()
+	if records[0].TacticsEnabled != 1 || records[1].TacticsEnabled != 0 {
+		t.Errorf("expected only the brute credited, got %d and %d", records[0].TacticsEnabled, records[1].TacticsEnabled)
+	}
+}
+
+func TestTelemetry_OffByDefault(t *testing.T) {
+	g := NewGame(42, 48, 24)
+	g.CreateUnit("Unit", 1, core.Stats{Health: 1}, nil, 1, core.Position{X: 10, Y: 10}, 1.0)
+	if records := g.UnitRecords(); records != nil {
+		t.Errorf("expected no records without telemetry, got %+v", records)
+	}
+}
@@ -0,0 +1,43 @@
+package setup
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/ai"
+	"github.com/jruiznavarro/wargamestactics/internal/game"
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+)
+
+// BenchmarkRunGame plays whole AI-vs-AI games between two shipped rosters
+// and reports games per second.
+func BenchmarkRunGame(b *testing.B) {
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(filepath.Join("..", "..", "data", "factions")); err != nil {
+		b.Fatalf("loading factions: %v", err)
+	}
+	var rosters [2]*army.ArmyRoster
+	for i, name := range []string{"seraphon_sunclaw.json", "tzeentch_arcanite.json"} {
+		r, err := army.LoadRoster(filepath.Join("..", "..", "data", "rosters", name))
+		if err != nil {
+			b.Fatalf("loading roster: %v", err)
+		}
+		rosters[i] = r
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		g := game.NewGameFromBattleplan(int64(i+1), board.GetBattleplan(board.BattleplanTable1, 1))
+		g.AddPlayer(ai.NewAIPlayer(1, "AI-1"))
+		g.AddPlayer(ai.NewAIPlayer(2, "AI-2"))
+		for j, r := range rosters {
+			if _, err := DeployRoster(g, registry.GetFaction(r.FactionID), r, j+1); err != nil {
+				b.Fatalf("deploying roster: %v", err)
+			}
+		}
+		g.RegisterTerrainRules()
+		g.RunGame(5)
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "games/s")
+}
@@ -0,0 +1,149 @@
+// Package setup turns validated army rosters into deployed units in a game.
+// It bridges the data-driven army package and the game engine, which are
+// deliberately kept independent of each other.
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game"
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+)
+
+// Deployment spacing in inches between unit anchor points.
+const (
+	unitSpacing = 6.0
+	rowSpacing  = 4.0
+	frontOffset = 3.0 // Distance of the first row behind the territory's front edge
+)
+
+// Army records what was deployed for one player.
+type Army struct {
+	Faction     *army.Faction
+	Roster      *army.ArmyRoster
+	Units       []*core.Unit
+	Formation   *army.BattleFormation // nil if the faction has no formations
+	HeroicTrait *army.Enhancement     // nil if none selected
+	Artefact    *army.Enhancement     // nil if none selected
+}
+
+// DeployRoster validates the roster against its faction and, if legal, creates
+// its units in the player's battleplan territory. Faction battle traits, the
+// chosen battle formation, warscroll abilities and enhancements are
+// registered with the game's rules engine. Validation errors are returned
+// joined; nothing is deployed in that case.
+func DeployRoster(g *game.Game, faction *army.Faction, roster *army.ArmyRoster, ownerID int) (*Army, error) {
+	if errs := roster.Validate(faction); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	specs := roster.BuildUnits(faction, ownerID, DeploymentPositions(g, ownerID, len(roster.Entries)))
+	dep := &Army{Faction: faction, Roster: roster}
+
+	for _, spec := range specs {
+		u := g.CreateUnit(spec.ToUnitParams())
+		spec.ApplyToUnit(u)
+		army.RegisterWarscrollAbilityRules(g.Rules, u, spec.Warscroll)
+		dep.Units = append(dep.Units, u)
+	}
+
+	ApplyBattleTraits(g, faction, ownerID)
+	if len(faction.Formations) > 0 {
+		army.RegisterFormationRules(g.Rules, faction, roster.FormationIndex, ownerID)
+		dep.Formation = &faction.Formations[roster.FormationIndex]
+	}
+
+	if traits := faction.AvailableHeroicTraits(); roster.HeroicTraitIdx >= 0 && roster.HeroicTraitIdx < len(traits) {
+		for i, spec := range specs {
+			if spec.IsGeneral {
+				dep.HeroicTrait = &traits[roster.HeroicTraitIdx]
+				army.RegisterEnhancementRules(g.Rules, dep.Units[i], dep.HeroicTrait)
+				break
+			}
+		}
+	}
+
+	if artefacts := faction.AvailableArtefacts(); roster.ArtefactIdx >= 0 && roster.ArtefactIdx < len(artefacts) {
+		if bearer := roster.ArtefactBearer(); bearer >= 0 {
+			dep.Artefact = &artefacts[roster.ArtefactIdx]
+			army.RegisterEnhancementRules(g.Rules, dep.Units[bearer], dep.Artefact)
+		}
+	}
+
+	return dep, nil
+}
+
+// ApplyBattleTraits registers a faction's battle traits for a player. Most
+// become rules; traits that need game state of their own (the Tzeentch
+// Destiny Dice pool) are set up on the game directly.
+func ApplyBattleTraits(g *game.Game, faction *army.Faction, ownerID int) {
+	army.RegisterFactionRules(g.Rules, faction, ownerID)
+	for _, t := range faction.BattleTraits {
+		if t.Effect == "destinyDice" {
+			g.SetDestinyDice(ownerID, army.NewDestinyDicePool(ownerID, nil), t.Value)
+		}
+	}
+}
+
+// DeploymentPositions returns n anchor positions inside the player's
+// territory, laid out in rows along the territory's long edge starting from
+// the edge nearest the centre of the board. Without a battleplan the player's
+// board half is used.
+func DeploymentPositions(g *game.Game, ownerID int, n int) []core.Position {
+	zone := territoryFor(g, ownerID)
+	width := zone.MaxPos.X - zone.MinPos.X
+	height := zone.MaxPos.Y - zone.MinPos.Y
+
+	// Rows run along the long edge; "depth" is measured away from the front.
+	horizontal := width >= height
+	length, depth := width, height
+	if !horizontal {
+		length, depth = height, width
+	}
+	perRow := int(math.Max(1, math.Floor(length/unitSpacing)))
+	margin := (length - float64(perRow-1)*unitSpacing) / 2
+
+	centre := core.Position{X: g.Board.Width / 2, Y: g.Board.Height / 2}
+	front, dir := zone.MaxPos.Y, -1.0
+	if horizontal && zone.MinPos.Y >= centre.Y {
+		front, dir = zone.MinPos.Y, 1.0
+	}
+	if !horizontal {
+		front, dir = zone.MaxPos.X, -1.0
+		if zone.MinPos.X >= centre.X {
+			front, dir = zone.MinPos.X, 1.0
+		}
+	}
+
+	positions := make([]core.Position, n)
+	for i := range positions {
+		row, col := i/perRow, i%perRow
+		along := margin + float64(col)*unitSpacing
+		away := math.Min(depth-1, frontOffset+rowSpacing*float64(row))
+		if horizontal {
+			positions[i] = g.Board.Clamp(core.Position{X: zone.MinPos.X + along, Y: front + dir*away})
+		} else {
+			positions[i] = g.Board.Clamp(core.Position{X: front + dir*away, Y: zone.MinPos.Y + along})
+		}
+	}
+	return positions
+}
+
+// territoryFor returns the deployment zone for a player (1 or 2).
+func territoryFor(g *game.Game, ownerID int) board.Territory {
+	idx := ownerID - 1
+	if g.Battleplan != nil && idx >= 0 && idx < len(g.Battleplan.Territories) {
+		return g.Battleplan.Territories[idx]
+	}
+	half := g.Board.Height / 2
+	if ownerID == 2 {
+		return board.Territory{Name: fmt.Sprintf("Player %d half", ownerID),
+			MinPos: core.Position{X: 0, Y: half}, MaxPos: core.Position{X: g.Board.Width, Y: g.Board.Height}}
+	}
+	return board.Territory{Name: fmt.Sprintf("Player %d half", ownerID),
+		MinPos: core.Position{X: 0, Y: 0}, MaxPos: core.Position{X: g.Board.Width, Y: half}}
+}
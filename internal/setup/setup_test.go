@@ -0,0 +1,197 @@
+package setup
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/ai"
+	"github.com/jruiznavarro/wargamestactics/internal/game"
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+	"github.com/jruiznavarro/wargamestactics/internal/game/rules"
+)
+
+func testFaction() *army.Faction {
+	return &army.Faction{
+		ID:   "test",
+		Name: "Test",
+		Warscrolls: []army.Warscroll{
+			{ID: "boss", Name: "Boss", Faction: "test", Points: 100, UnitSize: 1, BaseSizeMM: 40, Keywords: []string{"Hero", "Infantry"},
+				Stats:   army.WarscrollStats{Move: 5, Save: 4, Control: 2, Health: 5},
+				Weapons: []army.WarscrollWeapon{{Name: "Axe", Attacks: 3, ToHit: 3, ToWound: 3, Rend: 1, Damage: 2}}},
+			{ID: "grunts", Name: "Grunts", Faction: "test", Points: 100, UnitSize: 10, MaxSize: 20, BaseSizeMM: 32, Keywords: []string{"Infantry"},
+				Stats:   army.WarscrollStats{Move: 5, Save: 5, Control: 1, Health: 1},
+				Weapons: []army.WarscrollWeapon{{Name: "Club", Attacks: 1, ToHit: 4, ToWound: 4, Damage: 1}}},
+		},
+		HeroicTraits: []army.Enhancement{{Name: "Tough", Effect: "ward", Value: 5}},
+		Artefacts:    []army.Enhancement{{Name: "Big Axe", Effect: "extraDamage", Value: 1}},
+	}
+}
+
+func TestDeployRoster_PlacesUnitsInTerritory(t *testing.T) {
+	bp := board.GetBattleplan(board.BattleplanTable1, 1)
+	g := game.NewGameFromBattleplan(1, bp)
+	roster := &army.ArmyRoster{
+		FactionID:      "test",
+		HeroicTraitIdx: -1,
+		ArtefactIdx:    0,
+		Entries: []army.RosterEntry{
+			{WarscrollID: "boss", IsGeneral: true},
+			{WarscrollID: "grunts", Reinforced: true},
+			{WarscrollID: "grunts"},
+		},
+	}
+
+	for ownerID := 1; ownerID <= 2; ownerID++ {
+		dep, err := DeployRoster(g, testFaction(), roster, ownerID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(dep.Units) != 3 {
+			t.Fatalf("expected 3 units, got %d", len(dep.Units))
+		}
+		zone := bp.Territories[ownerID-1]
+		for _, u := range dep.Units {
+			if !zone.Contains(u.Position()) {
+				t.Errorf("player %d: %s at %v is outside %s", ownerID, u.Name, u.Position(), zone.Name)
+			}
+		}
+		if n := len(dep.Units[1].Models); n != 20 {
+			t.Errorf("expected reinforced unit to have 20 models, got %d", n)
+		}
+	}
+}
+
+func TestDeployRoster_AppliesEnhancements(t *testing.T) {
+	g := game.NewGame(1, 60, 44)
+	roster := &army.ArmyRoster{
+		FactionID:      "test",
+		HeroicTraitIdx: 0,
+		ArtefactIdx:    0,
+		ArtefactUnitID: "boss",
+		Entries:        []army.RosterEntry{{WarscrollID: "boss"}, {WarscrollID: "boss", IsGeneral: true}},
+	}
+	dep, err := DeployRoster(g, testFaction(), roster, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bearer, general := dep.Units[0], dep.Units[1]
+	if !general.IsGeneral {
+		t.Error("expected the second boss to be the general")
+	}
+	if len(general.Enhancements) != 1 || general.Enhancements[0] != "Tough" {
+		t.Errorf("expected the general to carry Tough, got %v", general.Enhancements)
+	}
+	if len(bearer.Enhancements) != 1 || bearer.Enhancements[0] != "Big Axe" {
+		t.Errorf("expected the bearer to carry Big Axe, got %v", bearer.Enhancements)
+	}
+	ctx := g.Rules.Evaluate(rules.BeforeWardSave, &rules.Context{Defender: general})
+	if ctx.WardOverride != 5 {
+		t.Errorf("expected heroic trait ward 5, got %d", ctx.WardOverride)
+	}
+	ctx = g.Rules.Evaluate(rules.BeforeDamage, &rules.Context{Attacker: bearer, Weapon: &bearer.Weapons[0]})
+	if ctx.Modifiers.DamageMod != 1 {
+		t.Errorf("expected artefact to add 1 damage, got %d", ctx.Modifiers.DamageMod)
+	}
+}
+
+func TestDeployRoster_ScourgeEnhancementWithoutFactionLists(t *testing.T) {
+	g := game.NewGame(1, 60, 44)
+	faction := testFaction()
+	faction.HeroicTraits, faction.Artefacts = nil, nil
+	roster := &army.ArmyRoster{
+		FactionID:      "test",
+		HeroicTraitIdx: 0,
+		ArtefactIdx:    -1,
+		Entries:        []army.RosterEntry{{WarscrollID: "boss", IsGeneral: true}},
+	}
+	dep, err := DeployRoster(g, faction, roster, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dep.HeroicTrait == nil || dep.HeroicTrait.Name != "Battle-hardened" {
+		t.Fatalf("expected the Scourge of Ghyran trait Battle-hardened, got %+v", dep.HeroicTrait)
+	}
+	ctx := g.Rules.Evaluate(rules.BeforeWardSave, &rules.Context{Defender: dep.Units[0]})
+	if ctx.WardOverride != 6 {
+		t.Errorf("expected ward 6, got %d", ctx.WardOverride)
+	}
+}
+
+func TestDeployRoster_TwoEnhancementsOnOneHero(t *testing.T) {
+	g := game.NewGame(1, 60, 44)
+	roster := &army.ArmyRoster{
+		FactionID:      "test",
+		HeroicTraitIdx: 0,
+		ArtefactIdx:    0,
+		Entries:        []army.RosterEntry{{WarscrollID: "boss", IsGeneral: true}, {WarscrollID: "boss"}},
+	}
+	if _, err := DeployRoster(g, testFaction(), roster, 1); err == nil {
+		t.Fatal("expected an error when the general takes both a heroic trait and an artefact")
+	}
+	roster.ArtefactUnitID = "boss"
+	roster.Entries[0], roster.Entries[1] = roster.Entries[1], roster.Entries[0]
+	if _, err := DeployRoster(g, testFaction(), roster, 1); err != nil {
+		t.Errorf("expected the artefact on a second hero to be legal: %v", err)
+	}
+}
+
+func TestDeployRoster_IllegalRoster(t *testing.T) {
+	g := game.NewGame(1, 60, 44)
+	roster := &army.ArmyRoster{
+		FactionID:   "test",
+		PointsLimit: 150,
+		Entries:     []army.RosterEntry{{WarscrollID: "grunts"}, {WarscrollID: "boss"}},
+	}
+	_, err := DeployRoster(g, testFaction(), roster, 1)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if len(g.Units) != 0 {
+		t.Errorf("expected no units deployed for an illegal roster, got %d", len(g.Units))
+	}
+}
+
+func TestShippedRostersAreLegal(t *testing.T) {
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(filepath.Join("..", "..", "data", "factions")); err != nil {
+		t.Skipf("faction data not available: %v", err)
+	}
+	paths, _ := filepath.Glob(filepath.Join("..", "..", "data", "rosters", "*.json"))
+	for _, path := range paths {
+		roster, err := army.LoadRoster(path)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		f := registry.GetFaction(roster.FactionID)
+		if f == nil {
+			t.Errorf("%s: unknown faction %q", path, roster.FactionID)
+			continue
+		}
+		for _, e := range roster.Validate(f) {
+			t.Errorf("%s: %v", path, e)
+		}
+	}
+}
+
+func TestApplyBattleTraits_DestinyDice(t *testing.T) {
+	g := game.NewGame(1, 60, 44)
+	faction := testFaction()
+	faction.BattleTraits = []army.FactionTrait{{Name: "Masters of Destiny", Effect: "destinyDice", Value: 9}}
+	ApplyBattleTraits(g, faction, 2)
+
+	pool, ok := g.DestinyDice[2]
+	if !ok {
+		t.Fatal("expected player 2 to get a destiny dice pool")
+	}
+	if _, ok := g.DestinyDice[1]; ok {
+		t.Error("expected no pool for player 1")
+	}
+	g.AddPlayer(ai.NewAIPlayer(1, "P1"))
+	g.AddPlayer(ai.NewAIPlayer(2, "P2"))
+	g.RollDestinyDice()
+	if n := len(pool.Values()); n != 9 {
+		t.Errorf("expected 9 destiny dice at battle start, got %d", n)
+	}
+}
@@ -0,0 +1,202 @@
+package simulation
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/jruiznavarro/wargamestactics/internal/ai"
+	"github.com/jruiznavarro/wargamestactics/internal/game"
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+	"github.com/jruiznavarro/wargamestactics/internal/setup"
+)
+
+// Matchup is what a batch of games plays: two rosters, player 1's first,
+// on a battleplan.
+type Matchup struct {
+	Factions   [2]*army.Faction
+	Rosters    [2]*army.ArmyRoster
+	Battleplan *board.Battleplan
+	Rounds     int // Battle rounds per game
+}
+
+// NewMatchup pairs two rosters, looking their factions up in registry and
+// checking that both are legal. It plays 5 rounds of the first battleplan.
+func NewMatchup(registry *army.FactionRegistry, roster1, roster2 *army.ArmyRoster) (*Matchup, error) {
+	m := &Matchup{
+		Rosters:    [2]*army.ArmyRoster{roster1, roster2},
+		Battleplan: board.GetBattleplan(board.BattleplanTable1, 1),
+		Rounds:     5,
+	}
+	for i, r := range m.Rosters {
+		f := registry.GetFaction(r.FactionID)
+		if f == nil {
+			return nil, fmt.Errorf("player %d: unknown faction '%s'", i+1, r.FactionID)
+		}
+		if errs := r.Validate(f); len(errs) > 0 {
+			return nil, fmt.Errorf("player %d: illegal roster: %v", i+1, errs[0])
+		}
+		m.Factions[i] = f
+	}
+	return m, nil
+}
+
+// Names returns the names the players are reported under: their factions'.
+func (m *Matchup) Names() (string, string) {
+	return m.Factions[0].Name, m.Factions[1].Name
+}
+
+// NewGame sets up an AI-vs-AI game of the matchup with the given seed,
+// ready to run and recording unit telemetry.
+func (m *Matchup) NewGame(seed int64) (*game.Game, error) {
+	g := game.NewGameFromBattleplan(seed, m.Battleplan)
+	g.Telemetry = game.NewTelemetry()
+	g.AddPlayer(ai.NewAIPlayer(1, "AI-1"))
+	g.AddPlayer(ai.NewAIPlayer(2, "AI-2"))
+	for i, r := range m.Rosters {
+		if _, err := setup.DeployRoster(g, m.Factions[i], r, i+1); err != nil {
+			return nil, fmt.Errorf("deploying player %d roster: %w", i+1, err)
+		}
+	}
+	g.RegisterTerrainRules()
+	return g, nil
+}
+
+// Play plays one game of the matchup with the given seed.
+func (m *Matchup) Play(seed int64) (GameResult, error) {
+	start := time.Now()
+	g, err := m.NewGame(seed)
+	if err != nil {
+		return GameResult{}, err
+	}
+	g.RunGame(m.Rounds)
+	return resultOf(g, seed, m.Rounds, time.Since(start)), nil
+}
+
+// resultOf records the outcome of a finished game.
+func resultOf(g *game.Game, seed int64, rounds int, d time.Duration) GameResult {
+	r := GameResult{
+		Seed:          seed,
+		Winner:        g.Winner,
+		VictoryPoints: make(map[int]int),
+		FinalRound:    g.BattleRound,
+		TotalRounds:   rounds,
+		Duration:      d,
+		UnitsAlive:    make(map[int]int),
+	}
+	for _, p := range g.Players {
+		id := p.ID()
+		r.VictoryPoints[id] = g.VictoryPoints[id]
+		r.UnitsAlive[id] = len(g.UnitsForPlayer(id))
+	}
+	r.Units = g.UnitRecords()
+	for id, alive := range r.UnitsAlive {
+		if id != g.Winner && alive == 0 && g.Winner >= 0 {
+			r.ImmediateWin = true
+		}
+	}
+	return r
+}
+
+// GameSeed returns the seed of game i of a batch run with the given master
+// seed. Every game's seed depends only on the master seed and its index, so
+// any game of a batch can be replayed on its own.
+func GameSeed(master int64, i int) int64 {
+	// SplitMix64 finaliser over the master seed advanced i times.
+	z := uint64(master) + uint64(i+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// Run plays games games of the matchup across workers goroutines (0 uses
+// one per CPU) and returns their statistics. Game i is seeded with
+// GameSeed(seed, i) and results are added in game order, so the statistics
+// are the same whatever the number of workers. Each worker sets up its own
+// games and dice; the matchup's factions and rosters are only read.
+func (m *Matchup) Run(games int, seed int64, workers int) (*MatchupStats, error) {
+	results, err := m.play(0, games, seed, workers)
+	if err != nil {
+		return nil, err
+	}
+	name1, name2 := m.Names()
+	stats := NewMatchupStats(1, name1, 2, name2)
+	for _, r := range results {
+		stats.AddResult(r)
+	}
+	return stats, nil
+}
+
+// Precision is when RunUntil may stop: once both players' win rate
+// intervals are no wider than Width.
+type Precision struct {
+	Width      float64 // Widest acceptable Wilson interval, e.g. 0.05
+	Confidence float64 // Confidence level of the intervals, e.g. 0.95
+	Every      int     // Games played between checks; 0 checks every 100
+}
+
+// RunUntil plays the matchup like Run, checking the win rate intervals
+// every p.Every games and stopping once they are narrow enough or maxGames
+// have been played. Checks fall on fixed game counts, so for a given seed
+// it stops after the same games whatever the number of workers.
+func (m *Matchup) RunUntil(maxGames int, p Precision, seed int64, workers int) (*MatchupStats, error) {
+	every := p.Every
+	if every <= 0 {
+		every = 100
+	}
+	name1, name2 := m.Names()
+	stats := NewMatchupStats(1, name1, 2, name2)
+	for stats.TotalGames < maxGames {
+		results, err := m.play(stats.TotalGames, min(every, maxGames-stats.TotalGames), seed, workers)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			stats.AddResult(r)
+		}
+		if stats.WinRateInterval(1, p.Confidence).Width() <= p.Width &&
+			stats.WinRateInterval(2, p.Confidence).Width() <= p.Width {
+			break
+		}
+	}
+	return stats, nil
+}
+
+// play plays games first to first+games-1 and returns their results in
+// order, or the error of the first game that failed.
+func (m *Matchup) play(first, games int, seed int64, workers int) ([]GameResult, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > games {
+		workers = games
+	}
+
+	results := make([]GameResult, games)
+	errs := make([]error, games)
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], errs[i] = m.Play(GameSeed(seed, first+i))
+			}
+		}()
+	}
+	for i := 0; i < games; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("game %d (seed %d): %w", first+i, GameSeed(seed, first+i), err)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,110 @@
+package simulation
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+)
+
+func testMatchup(t *testing.T) *Matchup {
+	t.Helper()
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(filepath.Join("..", "..", "data", "factions")); err != nil {
+		t.Fatalf("loading factions: %v", err)
+	}
+	var rosters [2]*army.ArmyRoster
+	for i, name := range []string{"seraphon_sunclaw.json", "tzeentch_arcanite.json"} {
+		r, err := army.LoadRoster(filepath.Join("..", "..", "data", "rosters", name))
+		if err != nil {
+			t.Fatalf("loading roster: %v", err)
+		}
+		rosters[i] = r
+	}
+	m, err := NewMatchup(registry, rosters[0], rosters[1])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return m
+}
+
+// outcomes strips the wall-clock time from results.
+func outcomes(stats *MatchupStats) []GameResult {
+	var rs []GameResult
+	for _, r := range stats.Results {
+		r.Duration = 0
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+func TestRun_SameResultsForAnyWorkerCount(t *testing.T) {
+	m := testMatchup(t)
+
+	one, err := m.Run(6, 42, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	four, err := m.Run(6, 42, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if one.TotalGames != 6 {
+		t.Fatalf("expected 6 games, got %d", one.TotalGames)
+	}
+	if !reflect.DeepEqual(outcomes(one), outcomes(four)) {
+		t.Errorf("expected the same results with 1 and 4 workers:\n%+v\n%+v", outcomes(one), outcomes(four))
+	}
+	for i, r := range one.Results {
+		if r.Seed != GameSeed(42, i) {
+			t.Errorf("game %d: expected seed %d, got %d", i, GameSeed(42, i), r.Seed)
+		}
+	}
+}
+
+func TestPlay_ReplaysAGameOfABatch(t *testing.T) {
+	m := testMatchup(t)
+	stats, err := m.Run(3, 7, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r, err := m.Play(GameSeed(7, 2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r.Duration = 0
+	if want := outcomes(stats)[2]; !reflect.DeepEqual(r, want) {
+		t.Errorf("expected replay %+v, got %+v", want, r)
+	}
+}
+
+func TestGameSeed_Distinct(t *testing.T) {
+	seen := make(map[int64]bool)
+	for i := 0; i < 1000; i++ {
+		s := GameSeed(1, i)
+		if seen[s] {
+			t.Fatalf("seed %d repeated at game %d", s, i)
+		}
+		seen[s] = true
+	}
+	if GameSeed(1, 0) == GameSeed(2, 0) {
+		t.Error("expected different master seeds to give different game seeds")
+	}
+}
+
+func TestNewMatchup_RejectsIllegalRoster(t *testing.T) {
+	m := testMatchup(t)
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(filepath.Join("..", "..", "data", "factions")); err != nil {
+		t.Fatalf("loading factions: %v", err)
+	}
+	illegal := *m.Rosters[0]
+	illegal.Entries = nil
+
+	if _, err := NewMatchup(registry, &illegal, m.Rosters[1]); err == nil {
+		t.Error("expected error for a roster without units")
+	}
+}
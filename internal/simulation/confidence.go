@@ -0,0 +1,253 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// Interval is an estimate with a confidence interval around it.
+type Interval struct {
+	Estimate float64
+	Low      float64
+	High     float64
+}
+
+// Width returns High - Low.
+func (iv Interval) Width() float64 {
+	return iv.High - iv.Low
+}
+
+// Contains reports whether x lies within the interval.
+func (iv Interval) Contains(x float64) bool {
+	return iv.Low <= x && x <= iv.High
+}
+
+// zScore returns the two-sided normal quantile for a confidence level, e.g.
+// 1.96 for 0.95.
+func zScore(confidence float64) float64 {
+	return math.Sqrt2 * math.Erfinv(confidence)
+}
+
+// WilsonInterval returns the Wilson score interval for a proportion of
+// successes out of n trials. It stays within [0, 1] and behaves well for
+// small n and rates near 0 or 1, unlike the normal approximation.
+func WilsonInterval(successes, n int, confidence float64) Interval {
+	if n == 0 {
+		return Interval{0, 0, 1}
+	}
+	p := float64(successes) / float64(n)
+	z := zScore(confidence)
+	z2 := z * z
+	nf := float64(n)
+	centre := (p + z2/(2*nf)) / (1 + z2/nf)
+	half := z / (1 + z2/nf) * math.Sqrt(p*(1-p)/nf+z2/(4*nf*nf))
+	return Interval{p, math.Max(0, centre-half), math.Min(1, centre+half)}
+}
+
+// ClopperPearsonInterval returns the exact binomial interval for a
+// proportion of successes out of n trials. It is conservative: its coverage
+// is at least the confidence level, at the cost of being wider than the
+// Wilson interval.
+func ClopperPearsonInterval(successes, n int, confidence float64) Interval {
+	if n == 0 {
+		return Interval{0, 0, 1}
+	}
+	alpha := 1 - confidence
+	x, nf := float64(successes), float64(n)
+	iv := Interval{x / nf, 0, 1}
+	if successes > 0 {
+		iv.Low = betaQuantile(alpha/2, x, nf-x+1)
+	}
+	if successes < n {
+		iv.High = betaQuantile(1-alpha/2, x+1, nf-x)
+	}
+	return iv
+}
+
+// betaQuantile returns the q quantile of the Beta(a, b) distribution, by
+// bisection on its CDF.
+func betaQuantile(q, a, b float64) float64 {
+	lo, hi := 0.0, 1.0
+	for i := 0; i < 64; i++ {
+		mid := (lo + hi) / 2
+		if regIncBeta(mid, a, b) < q {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// regIncBeta returns the regularized incomplete beta function I_x(a, b),
+// the CDF of Beta(a, b) at x.
+func regIncBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a + b)
+	lb, _ := math.Lgamma(a)
+	lc, _ := math.Lgamma(b)
+	front := math.Exp(la - lb - lc + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges quickly below the mean; use the
+	// symmetry I_x(a, b) = 1 - I_{1-x}(b, a) above it.
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(x, a, b) / a
+	}
+	return 1 - front*betaFraction(1-x, b, a)/b
+}
+
+// betaFraction evaluates the continued fraction of the incomplete beta
+// function by the modified Lentz method.
+func betaFraction(x, a, b float64) float64 {
+	const tiny = 1e-300
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= 300; m++ {
+		mf := float64(m)
+		for _, num := range []float64{
+			mf * (b - mf) * x / ((a + 2*mf - 1) * (a + 2*mf)),
+			-(a + mf) * (a + b + mf) * x / ((a + 2*mf) * (a + 2*mf + 1)),
+		} {
+			d = 1 + num*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + num/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < 1e-15 {
+			break
+		}
+	}
+	return h
+}
+
+// BootstrapMean returns the mean of values with a percentile bootstrap
+// interval from the given number of resamples. The resampling is seeded, so
+// the interval is reproducible.
+func BootstrapMean(values []float64, confidence float64, resamples int, seed int64) Interval {
+	if len(values) == 0 {
+		return Interval{}
+	}
+	n := len(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	iv := Interval{Estimate: sum / float64(n)}
+
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	means := make([]float64, resamples)
+	for r := range means {
+		sum := 0.0
+		for range values {
+			sum += values[rng.IntN(n)]
+		}
+		means[r] = sum / float64(n)
+	}
+	sort.Float64s(means)
+	alpha := 1 - confidence
+	iv.Low = means[int(alpha/2*float64(resamples-1))]
+	iv.High = means[int(math.Ceil((1-alpha/2)*float64(resamples-1)))]
+	return iv
+}
+
+// bootstrapResamples is the number of resamples of the VP margin interval.
+const bootstrapResamples = 2000
+
+// WinRateInterval returns a player's win rate with its Wilson interval.
+func (s *MatchupStats) WinRateInterval(playerID int, confidence float64) Interval {
+	return WilsonInterval(s.wins(playerID), s.TotalGames, confidence)
+}
+
+// WinRateExact returns a player's win rate with its Clopper-Pearson
+// interval.
+func (s *MatchupStats) WinRateExact(playerID int, confidence float64) Interval {
+	return ClopperPearsonInterval(s.wins(playerID), s.TotalGames, confidence)
+}
+
+func (s *MatchupStats) wins(playerID int) int {
+	if playerID == s.Player1ID {
+		return s.Player1Wins
+	}
+	return s.Player2Wins
+}
+
+// VPMarginInterval returns player 1's average VP lead over player 2 across
+// all games, draws included and negative when player 2 scores more, with a
+// bootstrap interval. The resampling is seeded from the games, so the
+// interval is reproducible for the same results.
+func (s *MatchupStats) VPMarginInterval(confidence float64) Interval {
+	margins := make([]float64, len(s.Results))
+	var seed int64
+	for i, r := range s.Results {
+		margins[i] = float64(r.VictoryPoints[s.Player1ID] - r.VictoryPoints[s.Player2ID])
+		seed ^= r.Seed
+	}
+	return BootstrapMean(margins, confidence, bootstrapResamples, seed)
+}
+
+// Comparison is the difference between two win rates, such as two lists'
+// win rates against the same opponent.
+type Comparison struct {
+	Difference Interval // First rate minus second, with its interval
+	Z          float64  // Two-proportion z statistic
+	PValue     float64  // Two-sided p-value of no difference
+}
+
+// Significant reports whether the difference is significant at level alpha,
+// e.g. 0.05.
+func (c Comparison) Significant(alpha float64) bool {
+	return c.PValue < alpha
+}
+
+// String describes the comparison, e.g. "+12.0% (+3.1% to +20.4%), p=0.008".
+func (c Comparison) String() string {
+	d := c.Difference
+	return fmt.Sprintf("%+.1f%% (%+.1f%% to %+.1f%%), p=%.3f", 100*d.Estimate, 100*d.Low, 100*d.High, c.PValue)
+}
+
+// CompareWinRates compares player a's win rate in stats sa with player b's
+// in sb. The p-value is from a pooled two-proportion z-test and the interval
+// is Newcombe's, built from the two Wilson intervals.
+func CompareWinRates(sa *MatchupStats, a int, sb *MatchupStats, b int, confidence float64) Comparison {
+	x1, n1 := sa.wins(a), sa.TotalGames
+	x2, n2 := sb.wins(b), sb.TotalGames
+	w1 := WilsonInterval(x1, n1, confidence)
+	w2 := WilsonInterval(x2, n2, confidence)
+
+	d := w1.Estimate - w2.Estimate
+	c := Comparison{
+		Difference: Interval{
+			Estimate: d,
+			Low:      d - math.Hypot(w1.Estimate-w1.Low, w2.High-w2.Estimate),
+			High:     d + math.Hypot(w1.High-w1.Estimate, w2.Estimate-w2.Low),
+		},
+		PValue: 1,
+	}
+	if n1 == 0 || n2 == 0 {
+		return c
+	}
+	pooled := float64(x1+x2) / float64(n1+n2)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(n1) + 1/float64(n2)))
+	if se == 0 {
+		return c
+	}
+	c.Z = d / se
+	c.PValue = math.Erfc(math.Abs(c.Z) / math.Sqrt2)
+	return c
+}
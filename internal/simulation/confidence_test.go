@@ -0,0 +1,125 @@
+package simulation
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func closeTo(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestWilsonInterval(t *testing.T) {
+	iv := WilsonInterval(7, 10, 0.95)
+	if !closeTo(iv.Low, 0.3968, 1e-4) || !closeTo(iv.High, 0.8922, 1e-4) || iv.Estimate != 0.7 {
+		t.Errorf("expected 0.7 (0.3968-0.8922), got %+v", iv)
+	}
+	if iv := WilsonInterval(0, 20, 0.95); iv.Low != 0 || iv.High <= 0 {
+		t.Errorf("expected interval from 0 for no successes, got %+v", iv)
+	}
+	if iv := WilsonInterval(0, 0, 0.95); iv.Low != 0 || iv.High != 1 {
+		t.Errorf("expected [0, 1] without trials, got %+v", iv)
+	}
+}
+
+func TestClopperPearsonInterval(t *testing.T) {
+	iv := ClopperPearsonInterval(7, 10, 0.95)
+	if !closeTo(iv.Low, 0.3475, 1e-4) || !closeTo(iv.High, 0.9333, 1e-4) {
+		t.Errorf("expected 0.3475-0.9333, got %+v", iv)
+	}
+	// With no successes the upper bound solves (1-p)^n = alpha/2.
+	iv = ClopperPearsonInterval(0, 10, 0.95)
+	if want := 1 - math.Pow(0.025, 0.1); iv.Low != 0 || !closeTo(iv.High, want, 1e-9) {
+		t.Errorf("expected 0-%.4f, got %+v", want, iv)
+	}
+	exact, wilson := ClopperPearsonInterval(7, 10, 0.95), WilsonInterval(7, 10, 0.95)
+	if exact.Width() <= wilson.Width() {
+		t.Errorf("expected the exact interval to be wider than Wilson's: %+v vs %+v", exact, wilson)
+	}
+}
+
+func TestBootstrapMean(t *testing.T) {
+	values := []float64{3, -1, 4, 1, -5, 9, 2, 6, -5, 3}
+	iv := BootstrapMean(values, 0.95, 1000, 1)
+	if !closeTo(iv.Estimate, 1.7, 1e-9) || !iv.Contains(1.7) || iv.Width() <= 0 {
+		t.Errorf("expected an interval around 1.7, got %+v", iv)
+	}
+	if again := BootstrapMean(values, 0.95, 1000, 1); !reflect.DeepEqual(iv, again) {
+		t.Errorf("expected the same interval for the same seed, got %+v and %+v", iv, again)
+	}
+	if iv := BootstrapMean([]float64{2, 2, 2}, 0.95, 100, 1); iv.Low != 2 || iv.High != 2 {
+		t.Errorf("expected a zero-width interval for constant values, got %+v", iv)
+	}
+}
+
+func statsWithWins(wins, losses, draws int) *MatchupStats {
+	s := NewMatchupStats(1, "A", 2, "B")
+	for i := 0; i < wins+losses+draws; i++ {
+		r := GameResult{Seed: int64(i), Winner: -1, VictoryPoints: map[int]int{1: 0, 2: 0}}
+		switch {
+		case i < wins:
+			r.Winner, r.VictoryPoints[1] = 1, 5
+		case i < wins+losses:
+			r.Winner, r.VictoryPoints[2] = 2, 3
+		}
+		s.AddResult(r)
+	}
+	return s
+}
+
+func TestCompareWinRates(t *testing.T) {
+	a, b := statsWithWins(60, 40, 0), statsWithWins(40, 60, 0)
+
+	c := CompareWinRates(a, 1, b, 1, 0.95)
+	if !closeTo(c.Z, 2.8284, 1e-4) || !closeTo(c.PValue, 0.00468, 1e-5) {
+		t.Errorf("expected z 2.83 and p 0.0047, got %+v", c)
+	}
+	if !c.Significant(0.05) || c.Difference.Low <= 0 || !c.Difference.Contains(0.2) {
+		t.Errorf("expected a significant difference of 0.2, got %v", c)
+	}
+
+	same := CompareWinRates(a, 1, statsWithWins(58, 42, 0), 1, 0.95)
+	if same.Significant(0.05) || !same.Difference.Contains(0) {
+		t.Errorf("expected no significant difference, got %v", same)
+	}
+}
+
+func TestMatchupStats_Intervals(t *testing.T) {
+	s := statsWithWins(3, 1, 4)
+
+	if iv := s.WinRateInterval(2, 0.95); iv != WilsonInterval(1, 8, 0.95) {
+		t.Errorf("expected player 2's Wilson interval, got %+v", iv)
+	}
+	if iv := s.WinRateExact(1, 0.95); iv != ClopperPearsonInterval(3, 8, 0.95) {
+		t.Errorf("expected player 1's exact interval, got %+v", iv)
+	}
+	// Player 1 leads by 5 in 3 games and trails by 3 in 1.
+	if vp := s.VPMarginInterval(0.95); !closeTo(vp.Estimate, 12.0/8, 1e-9) || !vp.Contains(1.5) {
+		t.Errorf("expected a VP lead of 1.5, got %+v", vp)
+	}
+}
+
+func TestRunUntil_StopsAtPrecision(t *testing.T) {
+	m := testMatchup(t)
+
+	wide, err := m.RunUntil(10, Precision{Width: 1, Confidence: 0.95, Every: 4}, 42, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wide.TotalGames != 4 {
+		t.Errorf("expected to stop at the first check after 4 games, got %d", wide.TotalGames)
+	}
+
+	all, err := m.RunUntil(10, Precision{Width: 0.01, Confidence: 0.95, Every: 4}, 42, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	run, err := m.Run(10, 42, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(outcomes(all), outcomes(run)) {
+		t.Errorf("expected the games of a full run:\n%+v\n%+v", outcomes(all), outcomes(run))
+	}
+}
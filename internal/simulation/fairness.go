@@ -0,0 +1,420 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+)
+
+// Outcome is the measure of how well a list did in a game that the points
+// estimator fits.
+type Outcome int
+
+const (
+	OutcomeVPLead     Outcome = iota // Victory points scored minus the opponent's
+	OutcomeScore                     // 1 for a win, 1/2 for a draw, 0 for a loss
+	OutcomeDamageLead                // Damage the list's units inflicted minus the damage they suffered
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeScore:
+		return "score"
+	case OutcomeDamageLead:
+		return "damage"
+	default:
+		return "vp"
+	}
+}
+
+// ParseOutcome parses an outcome by name: vp, score or damage.
+func ParseOutcome(s string) (Outcome, error) {
+	for o := OutcomeVPLead; o <= OutcomeDamageLead; o++ {
+		if o.String() == s {
+			return o, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown outcome '%s' (want vp, score or damage)", s)
+}
+
+// of returns the outcome of a game for the list played as player.
+func (o Outcome) of(r GameResult, player int) float64 {
+	switch o {
+	case OutcomeScore:
+		switch {
+		case r.Winner == player:
+			return 1
+		case r.IsDraw():
+			return 0.5
+		}
+		return 0
+	case OutcomeDamageLead:
+		lead := 0
+		for _, u := range r.Units {
+			if u.OwnerID == player {
+				lead += u.TotalDealt()
+			} else {
+				lead -= u.TotalDealt()
+			}
+		}
+		return float64(lead)
+	default:
+		lead := 0
+		for id, vp := range r.VictoryPoints {
+			if id == player {
+				lead += vp
+			} else {
+				lead -= vp
+			}
+		}
+		return float64(lead)
+	}
+}
+
+// PointsEstimator estimates what each warscroll of a faction is worth in
+// points. It plays many random legal lists against the opponents and fits
+// a regression of the lists' average outcome on how many of each warscroll
+// they field. Lists are drawn at random sizes, from half the points limit
+// up to it, so that the outcome of spending more points can be told apart
+// from the units the points are spent on.
+type PointsEstimator struct {
+	Faction     *army.Faction
+	PointsLimit int
+	Opponents   []Contender
+	Battleplan  *board.Battleplan
+	Rounds      int
+	Outcome     Outcome
+
+	Lists      int     // Random lists played
+	Games      int     // Games of each list against each opponent, half from each side
+	Ridge      float64 // Ridge penalty steadying the fit of rarely fielded warscrolls
+	Resamples  int     // Bootstrap resamples of the lists for the intervals
+	Confidence float64 // Confidence level of the intervals
+	Workers    int
+}
+
+// NewPointsEstimator returns an estimator for the warscrolls of faction in
+// lists of up to pointsLimit, fitting the VP lead over 5 rounds of the
+// first battleplan against each opponent.
+func NewPointsEstimator(faction *army.Faction, pointsLimit int, opponents []Contender) *PointsEstimator {
+	return &PointsEstimator{
+		Faction:     faction,
+		PointsLimit: pointsLimit,
+		Opponents:   opponents,
+		Battleplan:  board.GetBattleplan(board.BattleplanTable1, 1),
+		Rounds:      5,
+		Lists:       200,
+		Games:       10,
+		Ridge:       1,
+		Resamples:   200,
+		Confidence:  0.95,
+	}
+}
+
+// WarscrollCost is a warscroll's estimated worth.
+type WarscrollCost struct {
+	WarscrollID string
+	Name        string
+	Points      int      // Cost in the faction data
+	Fielded     int      // Units fielded across the lists, reinforced ones counting twice
+	Value       float64  // Outcome per unit fielded, the rest of the list being equal
+	Suggested   Interval // Points the unit is worth at the faction's average outcome per point
+}
+
+// Change returns the suggested change to the warscroll's cost.
+func (c WarscrollCost) Change() float64 {
+	return c.Suggested.Estimate - float64(c.Points)
+}
+
+// PointsEstimate is the outcome of a points estimator run. Suggested costs
+// are priced at the outcome per point of the faction as a whole, so over
+// the lists played they add up to the points actually spent: they move
+// points between warscrolls rather than make the faction cheaper or
+// dearer.
+type PointsEstimate struct {
+	Faction    *army.Faction
+	Opponents  []string
+	Outcome    Outcome
+	Lists      int
+	Games      int // Games per list
+	Confidence float64
+	PerPoint   float64 // Average outcome per point spent
+	Costs      []WarscrollCost
+}
+
+// Run plays the lists and fits the costs. Every list is played on the same
+// game seeds, so that lists are compared on the same dice, and the result
+// depends only on seed.
+func (e *PointsEstimator) Run(seed int64) (*PointsEstimate, error) {
+	if len(e.Opponents) == 0 {
+		return nil, fmt.Errorf("no opponents to play")
+	}
+	if e.Lists < 2 || e.Games < 1 {
+		return nil, fmt.Errorf("need at least 2 lists and 1 game per list")
+	}
+	ws := e.Faction.Warscrolls
+	index := make(map[string]int, len(ws))
+	for i := range ws {
+		index[ws[i].ID] = i
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(seed), 2))
+	gen := army.NewRosterGenerator(seed)
+	gameSeed := GameSeed(seed, 0)
+	counts := make([][]float64, 0, e.Lists)
+	outcomes := make([]float64, 0, e.Lists)
+	for attempts := 0; len(counts) < e.Lists && attempts < 10*e.Lists; attempts++ {
+		limit := e.PointsLimit/2 + rng.IntN(e.PointsLimit/2+1)
+		r, err := gen.Generate(e.Faction, limit)
+		if err != nil {
+			continue
+		}
+		r.PointsLimit = e.PointsLimit
+
+		x := make([]float64, len(ws))
+		for _, entry := range r.Entries {
+			if entry.Reinforced {
+				x[index[entry.WarscrollID]] += 2
+			} else {
+				x[index[entry.WarscrollID]]++
+			}
+		}
+		total, games := 0.0, 0
+		err = playGauntlet(e.Faction, r, e.Opponents, e.Battleplan, e.Rounds, e.Games, gameSeed, e.Workers,
+			func(res GameResult, player int) {
+				total += e.Outcome.of(res, player)
+				games++
+			})
+		if err != nil {
+			return nil, err
+		}
+		counts = append(counts, x)
+		outcomes = append(outcomes, total/float64(games))
+	}
+	if len(counts) < 2 {
+		return nil, fmt.Errorf("no legal %s lists fit in %d points", e.Faction.Name, e.PointsLimit)
+	}
+
+	points := make([]float64, len(ws))
+	for i := range ws {
+		points[i] = float64(ws[i].Points)
+	}
+	values, perPoint, ok := fitCosts(counts, outcomes, points, e.Ridge)
+	if !ok || perPoint <= 0 {
+		return nil, fmt.Errorf("the %s outcome did not grow with points spent over %d lists; "+
+			"play more lists or games, or fit another outcome", e.Outcome, len(counts))
+	}
+
+	// Bootstrap the suggested costs by refitting resampled lists.
+	samples := make([][]float64, len(ws))
+	rx := make([][]float64, len(counts))
+	ry := make([]float64, len(counts))
+	brng := rand.New(rand.NewPCG(uint64(seed), 3))
+	for b := 0; b < e.Resamples; b++ {
+		for i := range rx {
+			j := brng.IntN(len(counts))
+			rx[i], ry[i] = counts[j], outcomes[j]
+		}
+		v, pp, ok := fitCosts(rx, ry, points, e.Ridge)
+		if !ok || pp <= 0 {
+			continue
+		}
+		for w := range ws {
+			if fieldedIn(rx, w) {
+				samples[w] = append(samples[w], v[w]/pp)
+			}
+		}
+	}
+
+	res := &PointsEstimate{
+		Faction:    e.Faction,
+		Outcome:    e.Outcome,
+		Lists:      len(counts),
+		Games:      e.Games * len(e.Opponents),
+		Confidence: e.Confidence,
+		PerPoint:   perPoint,
+	}
+	for _, c := range e.Opponents {
+		res.Opponents = append(res.Opponents, c.Name)
+	}
+	alpha := 1 - e.Confidence
+	for w := range ws {
+		c := WarscrollCost{WarscrollID: ws[w].ID, Name: ws[w].Name, Points: ws[w].Points}
+		for _, x := range counts {
+			c.Fielded += int(x[w])
+		}
+		if c.Fielded > 0 {
+			c.Value = values[w]
+			c.Suggested = Interval{Estimate: values[w] / perPoint, Low: math.NaN(), High: math.NaN()}
+			if s := samples[w]; len(s) > 0 {
+				sort.Float64s(s)
+				c.Suggested.Low = s[int(alpha/2*float64(len(s)-1))]
+				c.Suggested.High = s[int(math.Ceil((1-alpha/2)*float64(len(s)-1)))]
+			}
+		}
+		res.Costs = append(res.Costs, c)
+	}
+	return res, nil
+}
+
+// fitCosts fits outcomes = intercept + counts·values by ridge regression,
+// leaving the intercept and warscrolls no list fielded unpenalised and at
+// zero, and returns the values with the outcome per point spent: the
+// values of everything fielded over its cost in points. Reports false if
+// the fit is singular.
+func fitCosts(counts [][]float64, outcomes, points []float64, ridge float64) (values []float64, perPoint float64, ok bool) {
+	k := len(points)
+	fielded := make([]float64, k)
+	for _, x := range counts {
+		for w, n := range x {
+			fielded[w] += n
+		}
+	}
+	var cols []int
+	for w := range points {
+		if fielded[w] > 0 {
+			cols = append(cols, w)
+		}
+	}
+
+	// Normal equations over the intercept and the fielded warscrolls.
+	n := len(cols) + 1
+	a := make([][]float64, n)
+	for i := range a {
+		a[i] = make([]float64, n+1)
+	}
+	row := make([]float64, n)
+	for i, x := range counts {
+		row[0] = 1
+		for j, w := range cols {
+			row[j+1] = x[w]
+		}
+		for p := 0; p < n; p++ {
+			for q := 0; q < n; q++ {
+				a[p][q] += row[p] * row[q]
+			}
+			a[p][n] += row[p] * outcomes[i]
+		}
+	}
+	for p := 1; p < n; p++ {
+		a[p][p] += ridge
+	}
+	beta, ok := solveAugmented(a)
+	if !ok {
+		return nil, 0, false
+	}
+
+	values = make([]float64, k)
+	value, cost := 0.0, 0.0
+	for j, w := range cols {
+		values[w] = beta[j+1]
+		value += fielded[w] * beta[j+1]
+		cost += fielded[w] * points[w]
+	}
+	return values, value / cost, true
+}
+
+// fieldedIn reports whether any of the lists fields warscroll w.
+func fieldedIn(counts [][]float64, w int) bool {
+	for _, x := range counts {
+		if x[w] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// solveAugmented solves the linear system held in the augmented matrix a
+// by Gaussian elimination with partial pivoting, overwriting a. Reports
+// false if the system is singular.
+func solveAugmented(a [][]float64) ([]float64, bool) {
+	n := len(a)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for r := col + 1; r < n; r++ {
+			f := a[r][col] / a[col][col]
+			for c := col; c <= n; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+		}
+	}
+	x := make([]float64, n)
+	for r := n - 1; r >= 0; r-- {
+		sum := a[r][n]
+		for c := r + 1; c < n; c++ {
+			sum -= a[r][c] * x[c]
+		}
+		x[r] = sum / a[r][r]
+	}
+	return x, true
+}
+
+// WriteMarkdown writes each warscroll's suggested cost against its cost in
+// the faction data, biggest suggested changes first.
+func (p *PointsEstimate) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Suggested %s points against %s\n\n", p.Faction.Name, strings.Join(p.Opponents, ", "))
+	fmt.Fprintf(&b, "Fitted on the %s outcome of %d random lists, %d games each: %.4f per point. %.0f%% bootstrap intervals.\n\n",
+		p.Outcome, p.Lists, p.Games, p.PerPoint, 100*p.Confidence)
+	b.WriteString("| Warscroll | Points | Fielded | Value | Suggested | Change |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, c := range p.byChange() {
+		if c.Fielded == 0 {
+			fmt.Fprintf(&b, "| %s | %d | 0 | - | - | - |\n", c.Name, c.Points)
+			continue
+		}
+		fmt.Fprintf(&b, "| %s | %d | %d | %+.3f | %.0f (%.0f to %.0f) | %+.0f |\n",
+			c.Name, c.Points, c.Fielded, c.Value, c.Suggested.Estimate, c.Suggested.Low, c.Suggested.High, c.Change())
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// byChange returns the costs by the size of their suggested change,
+// warscrolls no list fielded last.
+func (p *PointsEstimate) byChange() []WarscrollCost {
+	costs := append([]WarscrollCost(nil), p.Costs...)
+	sort.SliceStable(costs, func(i, j int) bool {
+		if (costs[i].Fielded == 0) != (costs[j].Fielded == 0) {
+			return costs[j].Fielded == 0
+		}
+		return math.Abs(costs[i].Change()) > math.Abs(costs[j].Change())
+	})
+	return costs
+}
+
+// WriteCSV writes the costs in faction order as CSV with a header row.
+// Warscrolls no list fielded have empty estimates.
+func (p *PointsEstimate) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"warscroll_id", "name", "points", "fielded", "value", "suggested", "suggested_low", "suggested_high", "change"})
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+	for _, c := range p.Costs {
+		rec := []string{c.WarscrollID, c.Name, strconv.Itoa(c.Points), strconv.Itoa(c.Fielded)}
+		if c.Fielded == 0 {
+			rec = append(rec, "", "", "", "", "")
+		} else {
+			rec = append(rec, f(c.Value), f(c.Suggested.Estimate), f(c.Suggested.Low), f(c.Suggested.High), f(c.Change()))
+		}
+		cw.Write(rec)
+	}
+	cw.Flush()
+	return cw.Error()
+}
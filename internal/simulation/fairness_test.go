@@ -0,0 +1,121 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestFitCosts_RecoversUnderpricedWarscroll(t *testing.T) {
+	// Three warscrolls costing 100, 100 and 200; the second is worth twice
+	// its points. Lists of random sizes score 0.01 per point of worth.
+	points := []float64{100, 100, 200}
+	worth := []float64{100, 200, 200}
+	rng := rand.New(rand.NewPCG(1, 1))
+	var counts [][]float64
+	var outcomes []float64
+	for i := 0; i < 200; i++ {
+		x := make([]float64, len(points))
+		y := 0.0
+		for w := range x {
+			x[w] = float64(rng.IntN(4))
+			y += 0.01 * worth[w] * x[w]
+		}
+		counts = append(counts, x)
+		outcomes = append(outcomes, y)
+	}
+
+	values, perPoint, ok := fitCosts(counts, outcomes, points, 0)
+	if !ok {
+		t.Fatal("expected a fit")
+	}
+	// Priced at the faction's average worth per point, by units fielded.
+	fieldedWorth, fieldedPoints := 0.0, 0.0
+	for _, x := range counts {
+		for w, n := range x {
+			fieldedWorth += n * worth[w]
+			fieldedPoints += n * points[w]
+		}
+	}
+	for w := range points {
+		got := values[w] / perPoint
+		want := worth[w] * fieldedPoints / fieldedWorth
+		if !closeTo(got, want, 1e-6) {
+			t.Errorf("warscroll %d: expected %.1f points, got %.1f", w, want, got)
+		}
+	}
+}
+
+func TestFitCosts_SkipsUnfieldedWarscrolls(t *testing.T) {
+	counts := [][]float64{{1, 0}, {2, 0}, {3, 0}}
+	values, perPoint, ok := fitCosts(counts, []float64{1, 2, 3}, []float64{100, 50}, 0)
+	if !ok {
+		t.Fatal("expected a fit")
+	}
+	if values[1] != 0 || !closeTo(perPoint, 0.01, 1e-9) {
+		t.Errorf("expected no value for the unfielded warscroll and 0.01 per point, got %v and %v", values, perPoint)
+	}
+}
+
+func TestSolveAugmented_Singular(t *testing.T) {
+	if _, ok := solveAugmented([][]float64{{1, 2, 3}, {2, 4, 6}}); ok {
+		t.Error("expected a singular system to fail")
+	}
+}
+
+func TestParseOutcome(t *testing.T) {
+	for _, o := range []Outcome{OutcomeVPLead, OutcomeScore, OutcomeDamageLead} {
+		if got, err := ParseOutcome(o.String()); err != nil || got != o {
+			t.Errorf("%s: got %v, %v", o, got, err)
+		}
+	}
+	if _, err := ParseOutcome("kills"); err == nil {
+		t.Error("expected error for an unknown outcome")
+	}
+}
+
+func TestPointsEstimator_Run(t *testing.T) {
+	m := testMatchup(t)
+	e := NewPointsEstimator(m.Factions[0], 2000, []Contender{{Name: "Tzeentch", Faction: m.Factions[1], Roster: m.Rosters[1]}})
+	e.Outcome = OutcomeDamageLead
+	e.Lists, e.Games, e.Resamples, e.Workers = 20, 2, 20, 1
+
+	p, err := e.Run(4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Lists != 20 || len(p.Costs) != len(m.Factions[0].Warscrolls) || p.PerPoint <= 0 {
+		t.Fatalf("unexpected estimate: %d lists, %d costs, %.4f per point", p.Lists, len(p.Costs), p.PerPoint)
+	}
+	spent, suggested := 0.0, 0.0
+	for _, c := range p.Costs {
+		spent += float64(c.Fielded * c.Points)
+		suggested += float64(c.Fielded) * c.Suggested.Estimate
+	}
+	if !closeTo(spent, suggested, 1e-6*spent) {
+		t.Errorf("expected suggested costs to add up to the %.0f points spent, got %.0f", spent, suggested)
+	}
+
+	var md, data bytes.Buffer
+	if err := p.WriteMarkdown(&md); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(md.String(), "# Suggested Seraphon points against Tzeentch") ||
+		!strings.Contains(md.String(), "damage outcome of 20 random lists") {
+		t.Errorf("unexpected report:\n%s", md.String())
+	}
+	if err := p.WriteCSV(&data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records, err := csv.NewReader(&data).ReadAll()
+	if err != nil || len(records) != len(p.Costs)+1 {
+		t.Errorf("expected %d CSV records, got %d (%v)", len(p.Costs)+1, len(records), err)
+	}
+
+	e.Opponents = nil
+	if _, err := e.Run(4); err == nil {
+		t.Error("expected error without opponents")
+	}
+}
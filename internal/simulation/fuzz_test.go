@@ -0,0 +1,55 @@
+package simulation
+
+import (
+	"math/rand/v2"
+	"path/filepath"
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game"
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+)
+
+// FuzzInvariants plays AI-vs-AI games of random rosters on random
+// battleplans with the invariant checker on. Run it beyond the seed corpus
+// with `go test -fuzz FuzzInvariants ./internal/simulation`.
+func FuzzInvariants(f *testing.F) {
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(filepath.Join("..", "..", "data", "factions")); err != nil {
+		f.Fatalf("loading factions: %v", err)
+	}
+	factions := registry.AllFactions()
+	plans := append(board.Table1Battleplans(), board.Table2Battleplans()...)
+	for seed := int64(1); seed <= 8; seed++ {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+		rng := rand.New(rand.NewPCG(uint64(seed), 4))
+		gen := army.NewRosterGenerator(seed)
+		var rosters [2]*army.ArmyRoster
+		for i := range rosters {
+			limit := []int{1000, 1500, 2000}[rng.IntN(3)]
+			r, err := gen.Generate(factions[rng.IntN(len(factions))], limit)
+			if err != nil {
+				t.Fatalf("generating roster: %v", err)
+			}
+			rosters[i] = r
+		}
+		m, err := NewMatchup(registry, rosters[0], rosters[1])
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		m.Battleplan = &plans[rng.IntN(len(plans))]
+
+		g, err := m.NewGame(seed)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		g.Invariants = game.NewInvariantChecker(false)
+		g.RunGame(m.Rounds)
+		if err := g.Invariants.Err(); err != nil {
+			t.Fatalf("%s (%s vs %s): %v", m.Battleplan.Name, rosters[0].FactionID, rosters[1].FactionID, err)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package simulation
+
+import (
+	"fmt"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+)
+
+// Contender is an army entered in a matrix: a legal roster under a name.
+type Contender struct {
+	Name    string
+	Faction *army.Faction
+	Roster  *army.ArmyRoster
+}
+
+// NewContender enters roster under name, looking its faction up in
+// registry and checking that it is legal.
+func NewContender(registry *army.FactionRegistry, name string, roster *army.ArmyRoster) (Contender, error) {
+	f := registry.GetFaction(roster.FactionID)
+	if f == nil {
+		return Contender{}, fmt.Errorf("%s: unknown faction '%s'", name, roster.FactionID)
+	}
+	if errs := roster.Validate(f); len(errs) > 0 {
+		return Contender{}, fmt.Errorf("%s: illegal roster: %v", name, errs[0])
+	}
+	return Contender{Name: name, Faction: f, Roster: roster}, nil
+}
+
+// Matrix holds the results of every pair of contenders on every
+// battleplan. Each pairing is played from both sides, half the games each,
+// and is recorded from both armies' points of view.
+type Matrix struct {
+	Contenders  []string // Contender names, in order
+	Battleplans []string // Battleplan names, in order
+	GamesPerRun int      // Games per pair and battleplan
+	Confidence  float64  // Confidence level of reported intervals
+
+	// Sides records every game from the players' seats rather than the
+	// armies', to show whether going in the player 1 territory matters.
+	Sides *MatchupStats
+
+	stats map[matrixKey]*MatchupStats
+}
+
+// matrixKey identifies an army's record against an opponent on a
+// battleplan, or on every battleplan if plan is allPlans.
+type matrixKey struct {
+	army, opponent, plan int
+}
+
+const allPlans = -1
+
+// RunMatrix plays games games of every pair of contenders on every
+// battleplan, with sides swapped for half of them, using workers
+// goroutines for each run of games. Like Matchup.Run, the results depend
+// only on seed.
+func RunMatrix(contenders []Contender, plans []board.Battleplan, games, rounds int, seed int64, workers int) (*Matrix, error) {
+	if len(contenders) < 2 {
+		return nil, fmt.Errorf("a matrix needs at least 2 contenders, got %d", len(contenders))
+	}
+	m := &Matrix{
+		GamesPerRun: games,
+		Confidence:  0.95,
+		Sides:       NewMatchupStats(1, "Player 1", 2, "Player 2"),
+		stats:       make(map[matrixKey]*MatchupStats),
+	}
+	for _, c := range contenders {
+		m.Contenders = append(m.Contenders, c.Name)
+	}
+	for _, bp := range plans {
+		m.Battleplans = append(m.Battleplans, bp.Name)
+	}
+
+	run := 0
+	for a := range contenders {
+		for b := a + 1; b < len(contenders); b++ {
+			for p := range plans {
+				// Contender a plays as player 1 in the first half of the
+				// games and as player 2 in the second.
+				for side, n := range []int{(games + 1) / 2, games / 2} {
+					first, second := contenders[a], contenders[b]
+					if side == 1 {
+						first, second = second, first
+					}
+					matchup := &Matchup{
+						Factions:   [2]*army.Faction{first.Faction, second.Faction},
+						Rosters:    [2]*army.ArmyRoster{first.Roster, second.Roster},
+						Battleplan: &plans[p],
+						Rounds:     rounds,
+					}
+					results, err := matchup.play(0, n, GameSeed(seed, run), workers)
+					if err != nil {
+						return nil, fmt.Errorf("%s vs %s on %s: %w", first.Name, second.Name, plans[p].Name, err)
+					}
+					run++
+					for _, r := range results {
+						m.Sides.AddResult(r)
+						if side == 1 {
+							r = r.Swapped()
+						}
+						m.add(a, b, p, r)
+					}
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+// add records a game won, lost or drawn by army as player 1 against
+// opponent as player 2, from both points of view.
+func (m *Matrix) add(army, opponent, plan int, r GameResult) {
+	for _, key := range []matrixKey{{army, opponent, plan}, {army, opponent, allPlans}} {
+		m.cell(key).AddResult(r)
+	}
+	swapped := r.Swapped()
+	for _, key := range []matrixKey{{opponent, army, plan}, {opponent, army, allPlans}} {
+		m.cell(key).AddResult(swapped)
+	}
+}
+
+func (m *Matrix) cell(key matrixKey) *MatchupStats {
+	s, ok := m.stats[key]
+	if !ok {
+		s = NewMatchupStats(1, m.Contenders[key.army], 2, m.Contenders[key.opponent])
+		m.stats[key] = s
+	}
+	return s
+}
+
+// Stats returns contender army's record against contender opponent on
+// battleplan plan (an index into Battleplans), army being player 1 of the
+// stats whichever side it played. Returns nil if they did not play.
+func (m *Matrix) Stats(army, opponent, plan int) *MatchupStats {
+	return m.stats[matrixKey{army, opponent, plan}]
+}
+
+// Overall returns contender army's record against contender opponent on
+// every battleplan. Returns nil if they did not play.
+func (m *Matrix) Overall(army, opponent int) *MatchupStats {
+	return m.stats[matrixKey{army, opponent, allPlans}]
+}
+
+// Swapped returns r with players 1 and 2 exchanged.
+func (r GameResult) Swapped() GameResult {
+	other := map[int]int{1: 2, 2: 1}
+	s := r
+	if w, ok := other[r.Winner]; ok {
+		s.Winner = w
+	}
+	s.VictoryPoints = make(map[int]int, len(r.VictoryPoints))
+	for id, vp := range r.VictoryPoints {
+		s.VictoryPoints[other[id]] = vp
+	}
+	s.UnitsAlive = make(map[int]int, len(r.UnitsAlive))
+	for id, n := range r.UnitsAlive {
+		s.UnitsAlive[other[id]] = n
+	}
+	return s
+}
+
+// Compare compares army's win rate against opponent with other's win rate
+// against the same opponent, over every battleplan.
+func (m *Matrix) Compare(army, other, opponent int) (Comparison, error) {
+	sa, sb := m.Overall(army, opponent), m.Overall(other, opponent)
+	if sa == nil || sb == nil {
+		return Comparison{}, fmt.Errorf("%s and %s did not both play %s",
+			m.Contenders[army], m.Contenders[other], m.Contenders[opponent])
+	}
+	return CompareWinRates(sa, sa.Player1ID, sb, sb.Player1ID, m.Confidence), nil
+}
@@ -0,0 +1,120 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+)
+
+func testMatrix(t *testing.T, games int) *Matrix {
+	t.Helper()
+	m := testMatchup(t)
+	contenders := []Contender{
+		{Name: "Seraphon", Faction: m.Factions[0], Roster: m.Rosters[0]},
+		{Name: "Tzeentch", Faction: m.Factions[1], Roster: m.Rosters[1]},
+	}
+	matrix, err := RunMatrix(contenders, board.Table1Battleplans()[:2], games, 5, 11, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return matrix
+}
+
+func TestRunMatrix_BothPointsOfView(t *testing.T) {
+	m := testMatrix(t, 3)
+
+	if m.Sides.TotalGames != 6 {
+		t.Fatalf("expected 6 games, got %d", m.Sides.TotalGames)
+	}
+	for p := range m.Battleplans {
+		s, o := m.Stats(0, 1, p), m.Stats(1, 0, p)
+		if s.TotalGames != 3 {
+			t.Errorf("plan %d: expected 3 games, got %d", p, s.TotalGames)
+		}
+		if s.Player1Wins != o.Player2Wins || s.Player2Wins != o.Player1Wins || s.Draws != o.Draws {
+			t.Errorf("plan %d: records disagree: %d-%d-%d vs %d-%d-%d", p,
+				s.Player1Wins, s.Player2Wins, s.Draws, o.Player1Wins, o.Player2Wins, o.Draws)
+		}
+		if s.AvgVP(1) != o.AvgVP(2) {
+			t.Errorf("plan %d: expected average VP %.2f from both sides, got %.2f", p, s.AvgVP(1), o.AvgVP(2))
+		}
+	}
+	if got := m.Overall(0, 1).TotalGames; got != 6 {
+		t.Errorf("expected 6 games over all battleplans, got %d", got)
+	}
+	if m.Stats(0, 0, 0) != nil {
+		t.Error("expected no record of an army against itself")
+	}
+}
+
+func TestRunMatrix_RejectsSingleContender(t *testing.T) {
+	if _, err := RunMatrix([]Contender{{Name: "Alone"}}, board.AllBattleplans(), 1, 5, 1, 1); err == nil {
+		t.Error("expected error for a single contender")
+	}
+}
+
+func TestNewContender_RejectsIllegalRoster(t *testing.T) {
+	m := testMatchup(t)
+	registry := army.NewRegistry()
+	if err := registry.LoadAllFactions(filepath.Join("..", "..", "data", "factions")); err != nil {
+		t.Fatalf("loading factions: %v", err)
+	}
+	illegal := *m.Rosters[0]
+	illegal.Entries = nil
+
+	if _, err := NewContender(registry, "Empty", &illegal); err == nil {
+		t.Error("expected error for a roster without units")
+	}
+	if _, err := NewContender(army.NewRegistry(), "Tzeentch", m.Rosters[1]); err == nil {
+		t.Error("expected error for an unknown faction")
+	}
+}
+
+func TestGameResult_Swapped(t *testing.T) {
+	r := GameResult{Winner: 1, VictoryPoints: map[int]int{1: 12, 2: 7}, UnitsAlive: map[int]int{1: 3, 2: 0}}
+	s := r.Swapped()
+	if s.Winner != 2 || s.VictoryPoints[2] != 12 || s.VictoryPoints[1] != 7 || s.UnitsAlive[2] != 3 || s.UnitsAlive[1] != 0 {
+		t.Errorf("unexpected swapped result %+v", s)
+	}
+	if r.VictoryPoints[1] != 12 {
+		t.Error("expected the original result to be unchanged")
+	}
+	if d := (GameResult{Winner: -1}).Swapped(); d.Winner != -1 {
+		t.Errorf("expected a draw to stay a draw, got winner %d", d.Winner)
+	}
+}
+
+func TestMatrix_Reports(t *testing.T) {
+	m := testMatrix(t, 2)
+
+	var buf bytes.Buffer
+	if err := m.WriteCSV(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	// Header, then 2 battleplans and the total for each point of view.
+	if len(rows) != 1+2*3 {
+		t.Fatalf("expected 7 rows, got %d", len(rows))
+	}
+	if rows[3][0] != "Seraphon" || rows[3][2] != AllBattleplans || rows[3][3] != "4" {
+		t.Errorf("unexpected total row %v", rows[3])
+	}
+
+	buf.Reset()
+	if err := m.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"| Seraphon | - |", "### Seraphon vs Tzeentch", "| " + m.Battleplans[1] + " |"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected Markdown report to contain %q:\n%s", want, buf.String())
+		}
+	}
+}
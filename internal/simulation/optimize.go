@@ -0,0 +1,469 @@
+package simulation
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+	"github.com/jruiznavarro/wargamestactics/internal/game/board"
+)
+
+// Optimizer searches for strong lists of a faction against a set of
+// opponent lists. It is a (mu+lambda) evolutionary search: every generation
+// the surviving rosters are mutated into new legal ones, each is scored by
+// simulated games against the opponents, and the best rosters found so far
+// survive. The finalists are then replayed on fresh seeds so that the
+// reported results are not flattered by the search having picked lucky
+// dice.
+type Optimizer struct {
+	Faction     *army.Faction
+	PointsLimit int
+	Opponents   []Contender
+	Battleplan  *board.Battleplan
+	Rounds      int
+
+	Games       int                // Games against each opponent per roster, half from each side
+	Generations int                // Generations of mutation after the first
+	Population  int                // Rosters surviving each generation
+	Offspring   int                // New rosters tried each generation
+	Confirm     int                // Games against each opponent replaying the finalists
+	Top         int                // Finalists reported
+	Confidence  float64            // Confidence level of reported intervals
+	Start       []*army.ArmyRoster // Legal rosters to start from; random ones make up the population
+	Workers     int
+
+	// Progress, if set, is called after each generation with the best
+	// roster so far.
+	Progress func(generation int, best Candidate)
+}
+
+// NewOptimizer returns an optimizer for lists of faction within
+// pointsLimit, playing 5 rounds of the first battleplan against each
+// opponent.
+func NewOptimizer(faction *army.Faction, pointsLimit int, opponents []Contender) *Optimizer {
+	return &Optimizer{
+		Faction:     faction,
+		PointsLimit: pointsLimit,
+		Opponents:   opponents,
+		Battleplan:  board.GetBattleplan(board.BattleplanTable1, 1),
+		Rounds:      5,
+		Games:       20,
+		Generations: 10,
+		Population:  6,
+		Offspring:   12,
+		Confirm:     200,
+		Top:         3,
+		Confidence:  0.95,
+	}
+}
+
+// Candidate is a roster and how it fared against the opponents, with the
+// roster as player 1 of Stats whichever side it played from.
+type Candidate struct {
+	Roster *army.ArmyRoster
+	Stats  *MatchupStats
+}
+
+// Score returns the candidate's match points per game: 1 for a win and 1/2
+// for a draw.
+func (c Candidate) Score() float64 {
+	if c.Stats.TotalGames == 0 {
+		return 0
+	}
+	return (float64(c.Stats.Player1Wins) + float64(c.Stats.Draws)/2) / float64(c.Stats.TotalGames)
+}
+
+// better reports whether a ranks above b: by score, then by VP lead.
+func better(a, b Candidate) bool {
+	if sa, sb := a.Score(), b.Score(); sa != sb {
+		return sa > sb
+	}
+	return a.Stats.AvgVPMargin() > b.Stats.AvgVPMargin()
+}
+
+// Optimization is the outcome of a search.
+type Optimization struct {
+	Faction     *army.Faction
+	Opponents   []string
+	Confidence  float64
+	Evaluated   int       // Distinct rosters played during the search
+	BestScores  []float64 // Best search score after each generation
+	Finalists   []Candidate
+	Baseline    *Candidate // The first starting roster on the finalists' seeds, if any
+	SearchGames int        // Games per roster during the search
+}
+
+// Run searches for the best rosters. The search and its results depend only
+// on seed: every roster is scored on the same game seeds, so that rosters
+// are compared on the same dice, and the finalists are replayed on seeds
+// the search never saw.
+func (o *Optimizer) Run(seed int64) (*Optimization, error) {
+	if len(o.Opponents) == 0 {
+		return nil, fmt.Errorf("no opponents to optimize against")
+	}
+	if o.Population < 1 || o.Games < 1 {
+		return nil, fmt.Errorf("population and games must be at least 1")
+	}
+	for i, r := range o.Start {
+		if len(r.Entries) == 0 {
+			return nil, fmt.Errorf("starting roster %d has no units", i+1)
+		}
+		if errs := r.Validate(o.Faction); len(errs) > 0 {
+			return nil, fmt.Errorf("starting roster %d: illegal roster: %v", i+1, errs[0])
+		}
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(seed), 1))
+	searchSeed, confirmSeed := GameSeed(seed, 0), GameSeed(seed, 1)
+	seen := make(map[string]Candidate)
+	res := &Optimization{Faction: o.Faction, Confidence: o.Confidence, SearchGames: o.Games * len(o.Opponents)}
+	for _, c := range o.Opponents {
+		res.Opponents = append(res.Opponents, c.Name)
+	}
+
+	// try scores a roster unless it has been played before, and reports
+	// whether it was new.
+	try := func(r *army.ArmyRoster) (Candidate, bool, error) {
+		key := rosterKey(r)
+		if c, ok := seen[key]; ok {
+			return c, false, nil
+		}
+		stats, err := o.evaluate(r, o.Games, searchSeed)
+		if err != nil {
+			return Candidate{}, false, err
+		}
+		c := Candidate{Roster: r, Stats: stats}
+		seen[key] = c
+		return c, true, nil
+	}
+
+	var population []Candidate
+	add := func(r *army.ArmyRoster) error {
+		c, fresh, err := try(r)
+		if err == nil && fresh {
+			population = append(population, c)
+		}
+		return err
+	}
+	for _, r := range o.Start {
+		if err := add(canonicalRoster(r)); err != nil {
+			return nil, err
+		}
+	}
+	gen := army.NewRosterGenerator(seed)
+	for attempts := 0; len(population) < o.Population && attempts < 10*o.Population; attempts++ {
+		r, err := gen.Generate(o.Faction, o.PointsLimit)
+		if err != nil {
+			continue
+		}
+		if err := add(canonicalRoster(r)); err != nil {
+			return nil, err
+		}
+	}
+	if len(population) == 0 {
+		return nil, fmt.Errorf("no legal %s roster fits in %d points", o.Faction.Name, o.PointsLimit)
+	}
+
+	for gen := 0; gen <= o.Generations; gen++ {
+		if gen > 0 {
+			parents := population
+			for i := 0; i < o.Offspring; i++ {
+				child := o.mutate(parents[rng.IntN(len(parents))].Roster, rng)
+				if child == nil {
+					continue
+				}
+				if err := add(child); err != nil {
+					return nil, err
+				}
+			}
+		}
+		sort.SliceStable(population, func(i, j int) bool { return better(population[i], population[j]) })
+		if len(population) > o.Population {
+			population = population[:o.Population]
+		}
+		res.BestScores = append(res.BestScores, population[0].Score())
+		if o.Progress != nil {
+			o.Progress(gen, population[0])
+		}
+	}
+	res.Evaluated = len(seen)
+
+	top := o.Top
+	if top < 1 || top > len(population) {
+		top = len(population)
+	}
+	for _, c := range population[:top] {
+		stats, err := o.evaluate(c.Roster, o.Confirm, confirmSeed)
+		if err != nil {
+			return nil, err
+		}
+		res.Finalists = append(res.Finalists, Candidate{Roster: c.Roster, Stats: stats})
+	}
+	sort.SliceStable(res.Finalists, func(i, j int) bool { return better(res.Finalists[i], res.Finalists[j]) })
+	if len(o.Start) > 0 {
+		start := canonicalRoster(o.Start[0])
+		stats, err := o.evaluate(start, o.Confirm, confirmSeed)
+		if err != nil {
+			return nil, err
+		}
+		res.Baseline = &Candidate{Roster: start, Stats: stats}
+	}
+	return res, nil
+}
+
+// evaluate plays games games of the roster against each opponent and
+// records them all with the roster as player 1.
+func (o *Optimizer) evaluate(r *army.ArmyRoster, games int, seed int64) (*MatchupStats, error) {
+	stats := NewMatchupStats(1, o.Faction.Name, 2, "Opponents")
+	err := playGauntlet(o.Faction, r, o.Opponents, o.Battleplan, o.Rounds, games, seed, o.Workers,
+		func(res GameResult, player int) {
+			if player == 2 {
+				res = res.Swapped()
+			}
+			stats.AddResult(res)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// playGauntlet plays games games of a roster against each opponent, the
+// first half as player 1 and the rest as player 2, and passes every result
+// to record in order with the player the roster was. Run j against the
+// opponents is seeded with GameSeed(seed, j), so every roster played with
+// the same seed meets the same dice.
+func playGauntlet(f *army.Faction, r *army.ArmyRoster, opponents []Contender, plan *board.Battleplan,
+	rounds, games int, seed int64, workers int, record func(res GameResult, player int)) error {
+	run := 0
+	for _, opp := range opponents {
+		for side, n := range []int{(games + 1) / 2, games / 2} {
+			m := &Matchup{
+				Factions:   [2]*army.Faction{f, opp.Faction},
+				Rosters:    [2]*army.ArmyRoster{r, opp.Roster},
+				Battleplan: plan,
+				Rounds:     rounds,
+			}
+			if side == 1 {
+				m.Factions[0], m.Factions[1] = m.Factions[1], m.Factions[0]
+				m.Rosters[0], m.Rosters[1] = m.Rosters[1], m.Rosters[0]
+			}
+			results, err := m.play(0, n, GameSeed(seed, run), workers)
+			if err != nil {
+				return fmt.Errorf("against %s: %w", opp.Name, err)
+			}
+			run++
+			for _, res := range results {
+				record(res, side+1)
+			}
+		}
+	}
+	return nil
+}
+
+// mutationAttempts is how many random changes mutate tries before giving
+// up on finding a legal one.
+const mutationAttempts = 50
+
+// mutate returns a legal roster one random change away from r, or nil if
+// none was found.
+func (o *Optimizer) mutate(r *army.ArmyRoster, rng *rand.Rand) *army.ArmyRoster {
+	key := rosterKey(r)
+	for i := 0; i < mutationAttempts; i++ {
+		c := cloneRoster(r)
+		mutations[rng.IntN(len(mutations))](c, o.Faction, rng)
+		c = canonicalRoster(c)
+		if len(c.Entries) > 0 && len(c.Validate(o.Faction)) == 0 && rosterKey(c) != key {
+			return c
+		}
+	}
+	return nil
+}
+
+// mutations are the random changes mutate makes. Each may leave the roster
+// illegal; mutate only keeps the legal results. Those that pick a unit or
+// warscroll do nothing if there is none to pick.
+var mutations = []func(r *army.ArmyRoster, f *army.Faction, rng *rand.Rand){
+	addUnit,
+	removeUnit,
+	replaceUnit,
+	replaceUnit,
+	toggleReinforced,
+	moveGeneral,
+	changeFormation,
+	changeTrait,
+	changeArtefact,
+}
+
+// addUnit adds a random unit, reinforced a third of the time if it can be.
+func addUnit(r *army.ArmyRoster, f *army.Faction, rng *rand.Rand) {
+	if len(f.Warscrolls) == 0 {
+		return
+	}
+	ws := &f.Warscrolls[rng.IntN(len(f.Warscrolls))]
+	r.Entries = append(r.Entries, army.RosterEntry{WarscrollID: ws.ID, Reinforced: ws.MaxSize > 0 && rng.IntN(3) == 0})
+}
+
+func removeUnit(r *army.ArmyRoster, f *army.Faction, rng *rand.Rand) {
+	if len(r.Entries) == 0 {
+		return
+	}
+	i := rng.IntN(len(r.Entries))
+	removed := r.Entries[i]
+	r.Entries = append(r.Entries[:i], r.Entries[i+1:]...)
+	if removed.WarscrollID == r.ArtefactUnitID && r.ArtefactBearer() < 0 {
+		r.ArtefactUnitID = ""
+	}
+	if removed.IsGeneral {
+		moveGeneral(r, f, rng)
+	}
+}
+
+func replaceUnit(r *army.ArmyRoster, f *army.Faction, rng *rand.Rand) {
+	if len(r.Entries) == 0 || len(f.Warscrolls) == 0 {
+		return
+	}
+	e := &r.Entries[rng.IntN(len(r.Entries))]
+	e.WarscrollID = f.Warscrolls[rng.IntN(len(f.Warscrolls))].ID
+	e.Reinforced = false
+}
+
+func toggleReinforced(r *army.ArmyRoster, f *army.Faction, rng *rand.Rand) {
+	if len(r.Entries) == 0 {
+		return
+	}
+	e := &r.Entries[rng.IntN(len(r.Entries))]
+	e.Reinforced = !e.Reinforced
+}
+
+// moveGeneral makes a random hero the general.
+func moveGeneral(r *army.ArmyRoster, f *army.Faction, rng *rand.Rand) {
+	var heroes []int
+	for i := range r.Entries {
+		r.Entries[i].IsGeneral = false
+		if ws := f.GetWarscroll(r.Entries[i].WarscrollID); ws != nil && ws.HasKeyword("Hero") {
+			heroes = append(heroes, i)
+		}
+	}
+	if len(heroes) > 0 {
+		r.Entries[heroes[rng.IntN(len(heroes))]].IsGeneral = true
+	}
+}
+
+func changeFormation(r *army.ArmyRoster, f *army.Faction, rng *rand.Rand) {
+	if n := len(f.Formations); n > 0 {
+		r.FormationIndex = rng.IntN(n)
+	}
+}
+
+// changeTrait picks a random heroic trait for the general, or none.
+func changeTrait(r *army.ArmyRoster, f *army.Faction, rng *rand.Rand) {
+	r.HeroicTraitIdx = rng.IntN(len(f.AvailableHeroicTraits())+1) - 1
+}
+
+// changeArtefact picks a random artefact, or none, for a random unit.
+func changeArtefact(r *army.ArmyRoster, f *army.Faction, rng *rand.Rand) {
+	if len(r.Entries) == 0 {
+		return
+	}
+	r.ArtefactIdx = rng.IntN(len(f.AvailableArtefacts())+1) - 1
+	r.ArtefactUnitID = r.Entries[rng.IntN(len(r.Entries))].WarscrollID
+}
+
+func cloneRoster(r *army.ArmyRoster) *army.ArmyRoster {
+	c := *r
+	c.Entries = append([]army.RosterEntry(nil), r.Entries...)
+	return &c
+}
+
+// canonicalRoster returns a copy of r with the general first and the other
+// entries by warscroll ID, reinforced last, so that the same list always
+// reads and plays the same way.
+func canonicalRoster(r *army.ArmyRoster) *army.ArmyRoster {
+	c := cloneRoster(r)
+	sort.SliceStable(c.Entries, func(i, j int) bool {
+		a, b := c.Entries[i], c.Entries[j]
+		if a.IsGeneral != b.IsGeneral {
+			return a.IsGeneral
+		}
+		if a.WarscrollID != b.WarscrollID {
+			return a.WarscrollID < b.WarscrollID
+		}
+		return !a.Reinforced && b.Reinforced
+	})
+	return c
+}
+
+// rosterKey identifies a canonical roster's selections.
+func rosterKey(r *army.ArmyRoster) string {
+	var b strings.Builder
+	for _, e := range r.Entries {
+		b.WriteString(e.WarscrollID)
+		if e.Reinforced {
+			b.WriteString("+")
+		}
+		if e.IsGeneral {
+			b.WriteString("*")
+		}
+		b.WriteString(",")
+	}
+	fmt.Fprintf(&b, "%d/%d/%d/%s", r.FormationIndex, r.HeroicTraitIdx, r.ArtefactIdx, r.ArtefactUnitID)
+	return b.String()
+}
+
+// WriteMarkdown writes the finalists' replayed results, best first, with
+// each list in the app's text format.
+func (res *Optimization) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	pct := func(v float64) string { return fmt.Sprintf("%.1f%%", 100*v) }
+	fmt.Fprintf(&b, "# Best %s lists against %s\n\n", res.Faction.Name, strings.Join(res.Opponents, ", "))
+	fmt.Fprintf(&b, "%d rosters tried over %d generations at %d games each. Finalists replayed on fresh seeds, with %.0f%% intervals.\n\n",
+		res.Evaluated, len(res.BestScores)-1, res.SearchGames, 100*res.Confidence)
+
+	b.WriteString("| Rank | Points | Games | Score | Win rate | Draws | VP lead |")
+	if res.Baseline != nil {
+		b.WriteString(" Win rate vs start |")
+	}
+	b.WriteString("\n| --- | --- | --- | --- | --- | --- | --- |")
+	if res.Baseline != nil {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+	row := func(label string, c Candidate) {
+		win := c.Stats.WinRateInterval(c.Stats.Player1ID, res.Confidence)
+		lead := c.Stats.VPMarginInterval(res.Confidence)
+		fmt.Fprintf(&b, "| %s | %d | %d | %.3f | %s (%s-%s) | %s | %+.2f (%+.2f to %+.2f) |",
+			label, c.Roster.TotalPoints(res.Faction), c.Stats.TotalGames, c.Score(),
+			pct(win.Estimate), pct(win.Low), pct(win.High), pct(c.Stats.DrawRate()),
+			lead.Estimate, lead.Low, lead.High)
+		if res.Baseline != nil {
+			if rosterKey(c.Roster) == rosterKey(res.Baseline.Roster) {
+				b.WriteString(" |")
+			} else {
+				cmp := CompareWinRates(c.Stats, c.Stats.Player1ID, res.Baseline.Stats, res.Baseline.Stats.Player1ID, res.Confidence)
+				fmt.Fprintf(&b, " %s |", cmp)
+			}
+		}
+		b.WriteString("\n")
+	}
+	for i, c := range res.Finalists {
+		row(strconv.Itoa(i+1), c)
+	}
+	if res.Baseline != nil {
+		row("Start", *res.Baseline)
+	}
+
+	for i, c := range res.Finalists {
+		fmt.Fprintf(&b, "\n## %d.\n\n```\n", i+1)
+		if err := army.FormatListText(&b, c.Roster, res.Faction); err != nil {
+			return err
+		}
+		b.WriteString("```\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
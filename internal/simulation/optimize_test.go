@@ -0,0 +1,134 @@
+package simulation
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+)
+
+func testOptimizer(t *testing.T) *Optimizer {
+	t.Helper()
+	m := testMatchup(t)
+	opponent := Contender{Name: "Tzeentch", Faction: m.Factions[1], Roster: m.Rosters[1]}
+	o := NewOptimizer(m.Factions[0], 1000, []Contender{opponent})
+	o.Games, o.Generations, o.Population, o.Offspring, o.Confirm, o.Top = 2, 2, 2, 3, 4, 2
+	o.Workers = 1
+	return o
+}
+
+func TestOptimizer_MutationsStayLegal(t *testing.T) {
+	o := testOptimizer(t)
+	rng := rand.New(rand.NewPCG(3, 1))
+	gen := army.NewRosterGenerator(3)
+	for i := 0; i < 20; i++ {
+		r, err := gen.Generate(o.Faction, o.PointsLimit)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for j := 0; j < 10 && r != nil; j++ {
+			if errs := r.Validate(o.Faction); len(errs) > 0 {
+				t.Fatalf("illegal roster %s: %v", rosterKey(r), errs)
+			}
+			if r.TotalPoints(o.Faction) > 1000 {
+				t.Fatalf("roster %s over the points limit", rosterKey(r))
+			}
+			r = o.mutate(r, rng)
+		}
+	}
+}
+
+func TestOptimizer_Run(t *testing.T) {
+	o := testOptimizer(t)
+	m := testMatchup(t)
+	start := m.Rosters[0]
+	start.PointsLimit = 2000
+	o.PointsLimit = 2000
+	o.Start = []*army.ArmyRoster{start}
+	var generations []int
+	o.Progress = func(gen int, best Candidate) { generations = append(generations, gen) }
+
+	res, err := o.Run(5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(generations, []int{0, 1, 2}) || len(res.BestScores) != 3 {
+		t.Errorf("expected progress for generations 0-2, got %v", generations)
+	}
+	for i := 1; i < len(res.BestScores); i++ {
+		if res.BestScores[i] < res.BestScores[i-1] {
+			t.Errorf("best score fell from %.2f to %.2f", res.BestScores[i-1], res.BestScores[i])
+		}
+	}
+	if len(res.Finalists) != 2 || res.Baseline == nil {
+		t.Fatalf("expected 2 finalists and a baseline, got %d", len(res.Finalists))
+	}
+	for _, c := range res.Finalists {
+		if errs := c.Roster.Validate(o.Faction); len(errs) > 0 {
+			t.Errorf("illegal finalist: %v", errs)
+		}
+		if c.Stats.TotalGames != 4 {
+			t.Errorf("expected finalists replayed over 4 games, got %d", c.Stats.TotalGames)
+		}
+	}
+
+	again, err := o.Run(5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rosterKey(again.Finalists[0].Roster) != rosterKey(res.Finalists[0].Roster) {
+		t.Error("expected the same search for the same seed")
+	}
+
+	var buf bytes.Buffer
+	if err := res.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"# Best Seraphon lists against Tzeentch", "| Start |", "## 1.", "• General"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in report:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestOptimizer_Errors(t *testing.T) {
+	o := testOptimizer(t)
+	o.Opponents = nil
+	if _, err := o.Run(1); err == nil {
+		t.Error("expected error without opponents")
+	}
+
+	o = testOptimizer(t)
+	o.PointsLimit = 10
+	if _, err := o.Run(1); err == nil {
+		t.Error("expected error when nothing fits the points limit")
+	}
+
+	o = testOptimizer(t)
+	o.Start = []*army.ArmyRoster{{FactionID: o.Faction.ID, Entries: []army.RosterEntry{{WarscrollID: "nobody"}}}}
+	if _, err := o.Run(1); err == nil {
+		t.Error("expected error for an illegal starting roster")
+	}
+
+	o = testOptimizer(t)
+	o.Start = []*army.ArmyRoster{{FactionID: o.Faction.ID, PointsLimit: o.PointsLimit}}
+	if _, err := o.Run(1); err == nil || !strings.Contains(err.Error(), "no units") {
+		t.Errorf("expected error for an empty starting roster, got %v", err)
+	}
+}
+
+func TestOptimizer_MutationsOfAnEmptyRoster(t *testing.T) {
+	f := &army.Faction{ID: "empty"}
+	rng := rand.New(rand.NewPCG(1, 1))
+	for i, m := range mutations {
+		r := &army.ArmyRoster{FactionID: f.ID, HeroicTraitIdx: -1, ArtefactIdx: -1}
+		m(r, f, rng) // Must not panic
+		if len(r.Entries) != 0 {
+			t.Errorf("mutation %d added a unit from a faction with no warscrolls", i)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// AllBattleplans is the battleplan name of records covering every
+// battleplan.
+const AllBattleplans = "All"
+
+// MatrixRecord is one army's record against one opponent on one
+// battleplan, or on all of them.
+type MatrixRecord struct {
+	Army          string  `json:"army"`
+	Opponent      string  `json:"opponent"`
+	Battleplan    string  `json:"battleplan"` // AllBattleplans for the total
+	Games         int     `json:"games"`
+	Wins          int     `json:"wins"`
+	Losses        int     `json:"losses"`
+	Draws         int     `json:"draws"`
+	WinRate       float64 `json:"winRate"`
+	WinRateLow    float64 `json:"winRateLow"` // Wilson interval at the matrix's confidence
+	WinRateHigh   float64 `json:"winRateHigh"`
+	DrawRate      float64 `json:"drawRate"`
+	AvgVP         float64 `json:"avgVP"`
+	OpponentAvgVP float64 `json:"opponentAvgVP"`
+}
+
+func recordOf(s *MatchupStats, battleplan string, confidence float64) MatrixRecord {
+	iv := s.WinRateInterval(s.Player1ID, confidence)
+	return MatrixRecord{
+		Army:          s.Player1Name,
+		Opponent:      s.Player2Name,
+		Battleplan:    battleplan,
+		Games:         s.TotalGames,
+		Wins:          s.Player1Wins,
+		Losses:        s.Player2Wins,
+		Draws:         s.Draws,
+		WinRate:       iv.Estimate,
+		WinRateLow:    iv.Low,
+		WinRateHigh:   iv.High,
+		DrawRate:      s.DrawRate(),
+		AvgVP:         s.AvgVP(s.Player1ID),
+		OpponentAvgVP: s.AvgVP(s.Player2ID),
+	}
+}
+
+// Records returns every army's record against every opponent, for each
+// battleplan and then for all of them.
+func (m *Matrix) Records() []MatrixRecord {
+	var records []MatrixRecord
+	for a := range m.Contenders {
+		for b := range m.Contenders {
+			if m.Overall(a, b) == nil {
+				continue
+			}
+			for p, name := range m.Battleplans {
+				records = append(records, recordOf(m.Stats(a, b, p), name, m.Confidence))
+			}
+			records = append(records, recordOf(m.Overall(a, b), AllBattleplans, m.Confidence))
+		}
+	}
+	return records
+}
+
+// armyOnPlan returns the wins and games of contender a against every
+// opponent on battleplan plan.
+func (m *Matrix) armyOnPlan(a, plan int) (wins, games int) {
+	for b := range m.Contenders {
+		if s := m.stats[matrixKey{a, b, plan}]; s != nil {
+			wins += s.Player1Wins
+			games += s.TotalGames
+		}
+	}
+	return wins, games
+}
+
+// WriteCSV writes the records as CSV with a header row.
+func (m *Matrix) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"army", "opponent", "battleplan", "games", "wins", "losses", "draws",
+		"win_rate", "win_rate_low", "win_rate_high", "draw_rate", "avg_vp", "opponent_avg_vp"})
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+	for _, r := range m.Records() {
+		cw.Write([]string{r.Army, r.Opponent, r.Battleplan,
+			strconv.Itoa(r.Games), strconv.Itoa(r.Wins), strconv.Itoa(r.Losses), strconv.Itoa(r.Draws),
+			f(r.WinRate), f(r.WinRateLow), f(r.WinRateHigh), f(r.DrawRate), f(r.AvgVP), f(r.OpponentAvgVP)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the contenders, battleplans and records as JSON.
+func (m *Matrix) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Contenders  []string       `json:"contenders"`
+		Battleplans []string       `json:"battleplans"`
+		GamesPerRun int            `json:"gamesPerRun"`
+		Confidence  float64        `json:"confidence"`
+		Records     []MatrixRecord `json:"records"`
+	}{m.Contenders, m.Battleplans, m.GamesPerRun, m.Confidence, m.Records()})
+}
+
+// WriteMarkdown writes the matrix as Markdown tables: overall win rates,
+// each army's win rate per battleplan, and a breakdown of every pairing.
+func (m *Matrix) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	pct := func(v float64) string { return fmt.Sprintf("%.1f%%", 100*v) }
+	row := func(cells ...string) { b.WriteString("| " + strings.Join(cells, " | ") + " |\n") }
+	rule := func(n int) { row(strings.Split(strings.Repeat("---,", n-1)+"---", ",")...) }
+
+	fmt.Fprintf(&b, "# Matchup matrix\n\n%d games per pairing and battleplan, half of them with sides swapped. ", m.GamesPerRun)
+	fmt.Fprintf(&b, "Player 1 won %s of games, player 2 %s, with %s drawn.\n\n",
+		pct(m.Sides.WinRate(1)), pct(m.Sides.WinRate(2)), pct(m.Sides.DrawRate()))
+
+	fmt.Fprintf(&b, "## Win rates\n\nWin rate of the row army against the column army, on every battleplan, with its %.0f%% interval.\n\n", 100*m.Confidence)
+	row(append([]string{""}, m.Contenders...)...)
+	rule(len(m.Contenders) + 1)
+	for a, name := range m.Contenders {
+		cells := []string{name}
+		for o := range m.Contenders {
+			if s := m.Overall(a, o); s != nil {
+				iv := s.WinRateInterval(s.Player1ID, m.Confidence)
+				cells = append(cells, fmt.Sprintf("%s (%s-%s)", pct(iv.Estimate), pct(iv.Low), pct(iv.High)))
+			} else {
+				cells = append(cells, "-")
+			}
+		}
+		row(cells...)
+	}
+
+	b.WriteString("\n## Battleplans\n\nWin rate of each army on each battleplan, against every opponent.\n\n")
+	row(append([]string{"Battleplan"}, m.Contenders...)...)
+	rule(len(m.Contenders) + 1)
+	for p, plan := range append(append([]string(nil), m.Battleplans...), AllBattleplans) {
+		if plan == AllBattleplans {
+			p = allPlans
+		}
+		cells := []string{plan}
+		for a := range m.Contenders {
+			wins, games := m.armyOnPlan(a, p)
+			cells = append(cells, pct(float64(wins)/float64(max(games, 1))))
+		}
+		row(cells...)
+	}
+
+	b.WriteString("\n## Pairings\n")
+	for a, army := range m.Contenders {
+		for o := a + 1; o < len(m.Contenders); o++ {
+			opponent := m.Contenders[o]
+			fmt.Fprintf(&b, "\n### %s vs %s\n\n", army, opponent)
+			row("Battleplan", "Games", army+" wins", opponent+" wins", "Draws", army+" avg VP", opponent+" avg VP")
+			rule(7)
+			for _, r := range m.Records() {
+				if r.Army != army || r.Opponent != opponent {
+					continue
+				}
+				row(r.Battleplan, strconv.Itoa(r.Games), pct(r.WinRate), pct(float64(r.Losses)/float64(max(r.Games, 1))),
+					pct(r.DrawRate), fmt.Sprintf("%.1f", r.AvgVP), fmt.Sprintf("%.1f", r.OpponentAvgVP))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
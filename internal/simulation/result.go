@@ -3,18 +3,21 @@ package simulation
 import (
 	"fmt"
 	"time"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game"
 )
 
 // GameResult holds the outcome of a single simulated game.
 type GameResult struct {
-	Seed          int64          // RNG seed used
-	Winner        int            // Player ID of winner (-1 = draw)
-	VictoryPoints map[int]int    // Final VP per player
-	FinalRound    int            // Round at which game ended
-	TotalRounds   int            // Max rounds configured
-	Duration      time.Duration  // Wall-clock time for this game
-	ImmediateWin  bool           // True if won by destroying all enemy units
-	UnitsAlive    map[int]int    // Alive units per player at game end
+	Seed          int64             // RNG seed used
+	Winner        int               // Player ID of winner (-1 = draw)
+	VictoryPoints map[int]int       // Final VP per player
+	FinalRound    int               // Round at which game ended
+	TotalRounds   int               // Max rounds configured
+	Duration      time.Duration     // Wall-clock time for this game
+	ImmediateWin  bool              // True if won by destroying all enemy units
+	UnitsAlive    map[int]int       // Alive units per player at game end
+	Units         []game.UnitRecord // What each unit did, if recorded
 }
 
 // IsDraw returns true if the game ended in a draw.
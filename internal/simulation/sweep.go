@@ -0,0 +1,170 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game/army"
+)
+
+// SweepPoint is the outcome of a matchup played with one value of the swept
+// field.
+type SweepPoint struct {
+	Value     int
+	Stats     *MatchupStats
+	Warscroll *WarscrollStats // How the swept warscroll's units did
+}
+
+// Sweep is a sensitivity analysis of a warscroll field: the same matchup
+// played with each of a range of values.
+type Sweep struct {
+	WarscrollID string
+	Field       string
+	Player      int // Player whose roster fields the warscroll (1 if both do)
+	Confidence  float64
+	Points      []SweepPoint
+}
+
+// RunSweep plays games games of the matchup for each value, with the
+// warscroll's field (a JSON path as for army.Faction.WithWarscrollField)
+// overridden in every faction that has it. Each value replays the same game
+// seeds, so differences between values come from the change rather than
+// from the dice. A points change may put a roster over its limit; the
+// limit is then raised to the roster's new cost for that value, so the
+// roster is still played.
+func RunSweep(m *Matchup, warscrollID, field string, values []int, games int, seed int64, workers int) (*Sweep, error) {
+	s := &Sweep{WarscrollID: warscrollID, Field: field, Confidence: 0.95}
+	for i := 1; i >= 0; i-- {
+		if fields(m.Rosters[i], warscrollID) {
+			s.Player = i + 1
+		}
+	}
+	if s.Player == 0 {
+		return nil, fmt.Errorf("neither roster fields warscroll '%s'", warscrollID)
+	}
+
+	for _, value := range values {
+		changed := *m
+		for i, f := range m.Factions {
+			if f.GetWarscroll(warscrollID) == nil {
+				continue
+			}
+			patched, err := f.WithWarscrollField(warscrollID, field, value)
+			if err != nil {
+				return nil, err
+			}
+			changed.Factions[i] = patched
+		}
+		for i, r := range m.Rosters {
+			if cost := r.TotalPoints(changed.Factions[i]); cost > r.PointsLimit {
+				raised := *r
+				raised.PointsLimit = cost
+				changed.Rosters[i] = &raised
+			}
+		}
+		stats, err := changed.Run(games, seed, workers)
+		if err != nil {
+			return nil, fmt.Errorf("%s = %d: %w", field, value, err)
+		}
+		s.Points = append(s.Points, SweepPoint{
+			Value:     value,
+			Stats:     stats,
+			Warscroll: UnitTableOf(stats.Results).Get(warscrollID),
+		})
+	}
+	return s, nil
+}
+
+// fields reports whether a roster includes a warscroll.
+func fields(r *army.ArmyRoster, warscrollID string) bool {
+	for _, e := range r.Entries {
+		if e.WarscrollID == warscrollID {
+			return true
+		}
+	}
+	return false
+}
+
+// sweepRow is a SweepPoint from the sweeping player's side.
+type sweepRow struct {
+	value            int
+	games            int
+	winRate          Interval
+	drawRate         float64
+	vp, opponentVP   float64
+	vpLead           float64
+	damagePer100Pts  float64
+	controlPer100Pts float64
+}
+
+func (s *Sweep) rows() []sweepRow {
+	var rows []sweepRow
+	for _, p := range s.Points {
+		me, them := p.Stats.Player1ID, p.Stats.Player2ID
+		if s.Player == 2 {
+			me, them = them, me
+		}
+		r := sweepRow{
+			value:      p.Value,
+			games:      p.Stats.TotalGames,
+			winRate:    p.Stats.WinRateInterval(me, s.Confidence),
+			drawRate:   p.Stats.DrawRate(),
+			vp:         p.Stats.AvgVP(me),
+			opponentVP: p.Stats.AvgVP(them),
+		}
+		r.vpLead = r.vp - r.opponentVP
+		if p.Warscroll != nil {
+			r.damagePer100Pts = p.Warscroll.DamagePer100Points()
+			r.controlPer100Pts = p.Warscroll.ControlVPPer100Points()
+		}
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+// WriteMarkdown writes the sweep as a table followed by a bar chart of the
+// win rate against the value.
+func (s *Sweep) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	pct := func(v float64) string { return fmt.Sprintf("%.1f%%", 100*v) }
+	fmt.Fprintf(&b, "# Sweep of %s %s\n\nPlayer %d's results, with %.0f%% intervals for the win rate.\n\n",
+		s.WarscrollID, s.Field, s.Player, 100*s.Confidence)
+	b.WriteString("| Value | Games | Win rate | Draws | Avg VP | Opponent avg VP | VP lead | Damage/100pts | VP/100pts |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- | --- |\n")
+	rows := s.rows()
+	for _, r := range rows {
+		fmt.Fprintf(&b, "| %d | %d | %s (%s-%s) | %s | %.2f | %.2f | %+.2f | %.1f | %.2f |\n",
+			r.value, r.games, pct(r.winRate.Estimate), pct(r.winRate.Low), pct(r.winRate.High),
+			pct(r.drawRate), r.vp, r.opponentVP, r.vpLead, r.damagePer100Pts, r.controlPer100Pts)
+	}
+
+	const width = 40
+	b.WriteString("\n```\n")
+	for _, r := range rows {
+		bar := int(r.winRate.Estimate*width + 0.5)
+		fmt.Fprintf(&b, "%s = %-3d |%s%s| %s\n", s.Field, r.value,
+			strings.Repeat("#", bar), strings.Repeat(" ", width-bar), pct(r.winRate.Estimate))
+	}
+	b.WriteString("```\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteCSV writes the sweep as CSV with a header row.
+func (s *Sweep) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"value", "games", "win_rate", "win_rate_low", "win_rate_high", "draw_rate",
+		"avg_vp", "opponent_avg_vp", "vp_lead", "damage_per_100pts", "control_vp_per_100pts"})
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+	for _, r := range s.rows() {
+		cw.Write([]string{strconv.Itoa(r.value), strconv.Itoa(r.games),
+			f(r.winRate.Estimate), f(r.winRate.Low), f(r.winRate.High), f(r.drawRate),
+			f(r.vp), f(r.opponentVP), f(r.vpLead), f(r.damagePer100Pts), f(r.controlPer100Pts)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,73 @@
+package simulation
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunSweep_OverridesTheWarscroll(t *testing.T) {
+	m := testMatchup(t)
+	id := m.Rosters[0].Entries[0].WarscrollID
+	original := m.Factions[0].GetWarscroll(id).Points
+
+	s, err := RunSweep(m, id, "points", []int{100, 200}, 3, 9, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Player != 1 || len(s.Points) != 2 {
+		t.Fatalf("expected 2 points for player 1, got %d for player %d", len(s.Points), s.Player)
+	}
+	// Points do not change how the game plays, only what the units cost.
+	a, b := outcomes(s.Points[0].Stats), outcomes(s.Points[1].Stats)
+	for i := range a {
+		a[i].Units, b[i].Units = nil, nil
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("expected the same games for every points value:\n%+v\n%+v", a, b)
+	}
+	if got := s.Points[1].Warscroll.Points; got != 2*s.Points[0].Warscroll.Points {
+		t.Errorf("expected twice the points fielded at 200, got %d and %d", s.Points[0].Warscroll.Points, got)
+	}
+	if m.Factions[0].GetWarscroll(id).Points != original {
+		t.Error("expected the matchup's faction unchanged")
+	}
+
+	var buf bytes.Buffer
+	if err := s.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "| 200 | 3 |") || !strings.Contains(buf.String(), "points = 100 ") {
+		t.Errorf("unexpected report:\n%s", buf.String())
+	}
+}
+
+func TestRunSweep_PlaysRostersPushedOverTheLimit(t *testing.T) {
+	m := testMatchup(t)
+	id := m.Rosters[0].Entries[0].WarscrollID
+	limit := m.Rosters[0].PointsLimit
+
+	s, err := RunSweep(m, id, "points", []int{limit + 1}, 2, 9, 1)
+	if err != nil {
+		t.Fatalf("expected an over-limit roster to be played, got %v", err)
+	}
+	if len(s.Points) != 1 || s.Points[0].Stats.TotalGames != 2 {
+		t.Errorf("expected 2 games at %d points, got %+v", limit+1, s.Points)
+	}
+	if m.Rosters[0].PointsLimit != limit {
+		t.Error("expected the matchup's roster limit unchanged")
+	}
+}
+
+func TestRunSweep_Errors(t *testing.T) {
+	m := testMatchup(t)
+	if _, err := RunSweep(m, "nobody", "points", []int{1}, 1, 1, 1); err == nil {
+		t.Error("expected error for a warscroll neither roster fields")
+	}
+	id := m.Rosters[1].Entries[0].WarscrollID
+	if _, err := RunSweep(m, id, "stats.save", []int{9}, 1, 1, 1); err == nil {
+		t.Error("expected error for an unusable save")
+	}
+}
@@ -0,0 +1,224 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game"
+)
+
+// WarscrollStats is how the units of one warscroll performed, summed over
+// every game they were fielded in.
+type WarscrollStats struct {
+	WarscrollID string
+	Name        string
+
+	Units  int // Units fielded, counting each game separately
+	Points int // Points fielded
+
+	DamageDealt    [game.NumDamageKinds]int
+	DamageTaken    [game.NumDamageKinds]int
+	ModelsSlain    int
+	UnitsDestroyed int
+	ObjectiveTurns int
+	ControlVP      float64
+	TacticsEnabled int
+	Survived       int // Units still alive at the end of their game
+	SurvivalRounds int // Sum of the rounds units were destroyed in, or lasted to
+}
+
+func (s *WarscrollStats) add(u game.UnitRecord, finalRound int) {
+	s.Units++
+	s.Points += u.Points
+	for k := range u.DamageDealt {
+		s.DamageDealt[k] += u.DamageDealt[k]
+		s.DamageTaken[k] += u.DamageTaken[k]
+	}
+	s.ModelsSlain += u.ModelsSlain
+	s.UnitsDestroyed += u.UnitsDestroyed
+	s.ObjectiveTurns += u.ObjectiveTurns
+	s.ControlVP += u.ControlVP
+	s.TacticsEnabled += u.TacticsEnabled
+	if u.DestroyedRound == 0 {
+		s.Survived++
+		s.SurvivalRounds += finalRound
+	} else {
+		s.SurvivalRounds += u.DestroyedRound
+	}
+}
+
+// TotalDealt returns the damage of every kind the units inflicted.
+func (s *WarscrollStats) TotalDealt() int {
+	total := 0
+	for _, d := range s.DamageDealt {
+		total += d
+	}
+	return total
+}
+
+// TotalTaken returns the damage of every kind the units suffered.
+func (s *WarscrollStats) TotalTaken() int {
+	total := 0
+	for _, d := range s.DamageTaken {
+		total += d
+	}
+	return total
+}
+
+// perUnit returns v averaged over the units fielded.
+func (s *WarscrollStats) perUnit(v float64) float64 {
+	if s.Units == 0 {
+		return 0
+	}
+	return v / float64(s.Units)
+}
+
+// per100Points returns v per 100 points fielded.
+func (s *WarscrollStats) per100Points(v float64) float64 {
+	if s.Points == 0 {
+		return 0
+	}
+	return 100 * v / float64(s.Points)
+}
+
+// DamagePer100Points returns the damage inflicted per 100 points fielded.
+func (s *WarscrollStats) DamagePer100Points() float64 {
+	return s.per100Points(float64(s.TotalDealt()))
+}
+
+// ControlVPPer100Points returns the objective VP earned per 100 points
+// fielded.
+func (s *WarscrollStats) ControlVPPer100Points() float64 {
+	return s.per100Points(s.ControlVP)
+}
+
+// SurvivalRate returns the fraction of units alive at the end of their game.
+func (s *WarscrollStats) SurvivalRate() float64 {
+	return s.perUnit(float64(s.Survived))
+}
+
+// AvgSurvivalRound returns the average round units were destroyed in,
+// counting survivors as lasting to their game's final round.
+func (s *WarscrollStats) AvgSurvivalRound() float64 {
+	return s.perUnit(float64(s.SurvivalRounds))
+}
+
+// UnitTable gathers unit telemetry from many games into points efficiency
+// tables keyed by warscroll ID.
+type UnitTable struct {
+	stats map[string]*WarscrollStats
+}
+
+// NewUnitTable returns an empty UnitTable.
+func NewUnitTable() *UnitTable {
+	return &UnitTable{stats: make(map[string]*WarscrollStats)}
+}
+
+// UnitTableOf gathers the unit telemetry of results.
+func UnitTableOf(results []GameResult) *UnitTable {
+	t := NewUnitTable()
+	for _, r := range results {
+		t.AddResult(r)
+	}
+	return t
+}
+
+// AddResult adds a game's unit records. Units not built from a warscroll,
+// such as summoned ones, are keyed by name.
+func (t *UnitTable) AddResult(r GameResult) {
+	for _, u := range r.Units {
+		key := u.WarscrollID
+		if key == "" {
+			key = u.Name
+		}
+		s, ok := t.stats[key]
+		if !ok {
+			s = &WarscrollStats{WarscrollID: key, Name: u.Name}
+			t.stats[key] = s
+		}
+		s.add(u, r.FinalRound)
+	}
+}
+
+// Rows returns the statistics of every warscroll, by warscroll ID.
+func (t *UnitTable) Rows() []*WarscrollStats {
+	rows := make([]*WarscrollStats, 0, len(t.stats))
+	for _, s := range t.stats {
+		rows = append(rows, s)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].WarscrollID < rows[j].WarscrollID })
+	return rows
+}
+
+// Get returns the statistics of a warscroll, or nil if none of its units
+// played.
+func (t *UnitTable) Get(warscrollID string) *WarscrollStats {
+	return t.stats[warscrollID]
+}
+
+// WriteMarkdown writes a points efficiency table: per-unit averages per
+// game, then damage and objective VP per 100 points.
+func (t *UnitTable) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	row := func(cells ...string) { b.WriteString("| " + strings.Join(cells, " | ") + " |\n") }
+	f1 := func(v float64) string { return strconv.FormatFloat(v, 'f', 1, 64) }
+	f2 := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+
+	row("Warscroll", "Units", "Points", "Melee", "Shooting", "Spells", "Other", "Taken", "Slain",
+		"Objective turns", "Control VP", "Tactics", "Survived", "Survival round", "Damage/100pts", "VP/100pts")
+	row(strings.Split(strings.Repeat("---,", 15)+"---", ",")...)
+	for _, s := range t.Rows() {
+		cells := []string{s.Name, strconv.Itoa(s.Units), f1(s.perUnit(float64(s.Points)))}
+		for _, d := range s.DamageDealt {
+			cells = append(cells, f1(s.perUnit(float64(d))))
+		}
+		cells = append(cells,
+			f1(s.perUnit(float64(s.TotalTaken()))),
+			f1(s.perUnit(float64(s.ModelsSlain))),
+			f1(s.perUnit(float64(s.ObjectiveTurns))),
+			f2(s.perUnit(s.ControlVP)),
+			f2(s.perUnit(float64(s.TacticsEnabled))),
+			fmt.Sprintf("%.0f%%", 100*s.SurvivalRate()),
+			f1(s.AvgSurvivalRound()),
+			f1(s.DamagePer100Points()),
+			f2(s.ControlVPPer100Points()))
+		row(cells...)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteCSV writes the totals of every warscroll as CSV with a header row.
+func (t *UnitTable) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"warscroll_id", "name", "units", "points"}
+	for k := game.DamageKind(0); k < game.NumDamageKinds; k++ {
+		header = append(header, "dealt_"+strings.ToLower(k.String()))
+	}
+	for k := game.DamageKind(0); k < game.NumDamageKinds; k++ {
+		header = append(header, "taken_"+strings.ToLower(k.String()))
+	}
+	cw.Write(append(header, "models_slain", "units_destroyed", "objective_turns", "control_vp",
+		"tactics_enabled", "survived", "avg_survival_round", "damage_per_100pts", "control_vp_per_100pts"))
+
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+	for _, s := range t.Rows() {
+		rec := []string{s.WarscrollID, s.Name, strconv.Itoa(s.Units), strconv.Itoa(s.Points)}
+		for _, d := range s.DamageDealt {
+			rec = append(rec, strconv.Itoa(d))
+		}
+		for _, d := range s.DamageTaken {
+			rec = append(rec, strconv.Itoa(d))
+		}
+		cw.Write(append(rec, strconv.Itoa(s.ModelsSlain), strconv.Itoa(s.UnitsDestroyed),
+			strconv.Itoa(s.ObjectiveTurns), f(s.ControlVP), strconv.Itoa(s.TacticsEnabled),
+			strconv.Itoa(s.Survived), f(s.AvgSurvivalRound()), f(s.DamagePer100Points()),
+			f(s.ControlVPPer100Points())))
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,77 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game"
+)
+
+func TestUnitTable_AggregatesByWarscroll(t *testing.T) {
+	warriors := game.UnitRecord{Name: "Warriors", WarscrollID: "warriors", Points: 100, ModelsSlain: 2, ControlVP: 1.5}
+	warriors.DamageDealt[game.DamageMelee] = 6
+	hero := game.UnitRecord{Name: "Hero", WarscrollID: "hero", Points: 150, DestroyedRound: 2}
+	hero.DamageDealt[game.DamageSpells] = 3
+	lost := warriors
+	lost.DestroyedRound = 3
+	lost.ControlVP = 0.5
+
+	table := UnitTableOf([]GameResult{
+		{FinalRound: 5, Units: []game.UnitRecord{warriors, hero}},
+		{FinalRound: 4, Units: []game.UnitRecord{lost, {Name: "Summoned"}}},
+	})
+
+	rows := table.Rows()
+	if len(rows) != 3 || rows[0].WarscrollID != "Summoned" || rows[2].WarscrollID != "warriors" {
+		t.Fatalf("expected Summoned, hero and warriors rows, got %d rows", len(rows))
+	}
+	w := table.Get("warriors")
+	if w.Units != 2 || w.Points != 200 || w.ModelsSlain != 4 || w.TotalDealt() != 12 {
+		t.Errorf("unexpected warriors totals %+v", w)
+	}
+	if !closeTo(w.DamagePer100Points(), 6, 1e-9) || !closeTo(w.ControlVPPer100Points(), 1, 1e-9) {
+		t.Errorf("expected 6 damage and 1 VP per 100 points, got %.2f and %.2f", w.DamagePer100Points(), w.ControlVPPer100Points())
+	}
+	if w.SurvivalRate() != 0.5 || w.AvgSurvivalRound() != 4 {
+		t.Errorf("expected 50%% survival and round 4, got %.2f and %.2f", w.SurvivalRate(), w.AvgSurvivalRound())
+	}
+	if h := table.Get("hero"); h.DamageDealt[game.DamageSpells] != 3 || h.Survived != 0 {
+		t.Errorf("unexpected hero totals %+v", h)
+	}
+
+	var buf bytes.Buffer
+	if err := table.WriteCSV(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rowsCSV, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(rowsCSV) != 4 || rowsCSV[0][4] != "dealt_melee" {
+		t.Errorf("unexpected CSV %v (%v)", rowsCSV, err)
+	}
+	buf.Reset()
+	if err := table.WriteMarkdown(&buf); err != nil || !strings.Contains(buf.String(), "| Warriors | 2 | 100.0 | 6.0 |") {
+		t.Errorf("unexpected Markdown (%v):\n%s", err, buf.String())
+	}
+}
+
+func TestPlay_RecordsUnits(t *testing.T) {
+	m := testMatchup(t)
+	r, err := m.Play(5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	points := 0
+	for _, u := range r.Units {
+		if u.WarscrollID == "" || u.Points == 0 {
+			t.Errorf("expected %s to carry its warscroll and points", u.Name)
+		}
+		if u.OwnerID == 1 {
+			points += u.Points
+		}
+	}
+	if want := m.Rosters[0].TotalPoints(m.Factions[0]); points != want {
+		t.Errorf("expected player 1's units to cost %d points, got %d", want, points)
+	}
+}
@@ -52,6 +52,9 @@ func NewCLIPlayerWithIO(id int, name string, reader io.Reader, writer io.Writer)
 func (p *CLIPlayer) ID() int      { return p.id }
 func (p *CLIPlayer) Name() string { return p.name }
 
+// IsHuman reports that a person plays through the CLI.
+func (p *CLIPlayer) IsHuman() bool { return true }
+
 func (p *CLIPlayer) GetNextCommand(view *game.GameView, currentPhase phase.Phase) interface{} {
 	p.displayHeader(view)
 	p.displayMap(view)
@@ -80,6 +83,30 @@ func (p *CLIPlayer) GetNextCommand(view *game.GameView, currentPhase phase.Phase
 	}
 }
 
+// ChooseStoredDice asks which stored dice to use in place of a roll.
+func (p *CLIPlayer) ChooseStoredDice(view *game.GameView, req game.DiceRequest) []int {
+	need := fmt.Sprintf("%d+ each", req.Target)
+	if req.Kind == game.RollCasting || req.Kind == game.RollCharge {
+		need = fmt.Sprintf("%d total", req.Target)
+	}
+	fmt.Fprintf(p.writer, "  Destiny Dice %v: %d %s dice to roll (need %s).\n", req.Pool, req.Dice, req.Kind, need)
+	fmt.Fprint(p.writer, "  Values to use instead (blank to roll)> ")
+	line, err := p.reader.ReadString('\n')
+	if err != nil {
+		return nil
+	}
+	var values []int
+	for _, f := range strings.Fields(line) {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			fmt.Fprintf(p.writer, "  Ignoring '%s'\n", f)
+			continue
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
 // --- Display functions ---
 
 func (p *CLIPlayer) displayHeader(view *game.GameView) {
@@ -87,6 +114,9 @@ func (p *CLIPlayer) displayHeader(view *game.GameView) {
 	fmt.Fprintf(p.writer, "+------------------------------------------------------------+\n")
 	fmt.Fprintf(p.writer, "|  BATTLE ROUND %-2d                      %18s  |\n", view.BattleRound, view.CurrentPhase)
 	fmt.Fprintf(p.writer, "+------------------------------------------------------------+\n")
+	if dd, ok := view.DestinyDice[p.id]; ok {
+		fmt.Fprintf(p.writer, "  Destiny Dice: %v\n", dd)
+	}
 }
 
 func (p *CLIPlayer) displayMap(view *game.GameView) {
@@ -237,6 +267,9 @@ func (p *CLIPlayer) displayUnitCard(u game.UnitView, showDetails bool) {
 	fmt.Fprintf(p.writer, "  | HP: %s %d/%-3d           |\n", healthBar, u.CurrentWounds, u.MaxWounds)
 	fmt.Fprintf(p.writer, "  | Pos: (%.1f, %.1f)  Move: %d\"  Save: %d+%s\n",
 		u.Position[0], u.Position[1], u.MoveSpeed, u.Save, statusStr)
+	if len(u.Enhancements) > 0 {
+		fmt.Fprintf(p.writer, "  | Enhancements: %s\n", strings.Join(u.Enhancements, ", "))
+	}
 
 	if showDetails && len(u.Weapons) > 0 {
 		fmt.Fprintf(p.writer, "  | Weapons:\n")
@@ -256,6 +289,22 @@ func (p *CLIPlayer) displayUnitCard(u game.UnitView, showDetails bool) {
 	fmt.Fprintf(p.writer, "  +----------------------------------------------+\n")
 }
 
+// displayExplain lists the rules that applied to the most recent action.
+func (p *CLIPlayer) displayExplain(view *game.GameView) {
+	if view.LastResult == "" {
+		fmt.Fprintf(p.writer, "  Nothing has happened yet.\n")
+		return
+	}
+	fmt.Fprintf(p.writer, "\n  Last action: %s\n", view.LastResult)
+	if len(view.LastTrace) == 0 {
+		fmt.Fprintf(p.writer, "    No rules applied.\n")
+		return
+	}
+	for _, a := range view.LastTrace {
+		fmt.Fprintf(p.writer, "    %-40s [%s]\n", a.String(), a.Source)
+	}
+}
+
 func (p *CLIPlayer) displayPrompt(currentPhase phase.Phase) {
 	fmt.Fprintf(p.writer, "\n  Commands:")
 	for _, ct := range currentPhase.AllowedCommands {
@@ -278,7 +327,7 @@ func (p *CLIPlayer) displayPrompt(currentPhase phase.Phase) {
 			fmt.Fprintf(p.writer, " skip")
 		}
 	}
-	fmt.Fprintf(p.writer, " | map | help\n")
+	fmt.Fprintf(p.writer, " | map | explain | help\n")
 }
 
 // --- Parsing ---
@@ -451,6 +500,10 @@ func (p *CLIPlayer) parseCommand(line string, currentPhase phase.Phase, view *ga
 		p.displayMap(view)
 		return nil, fmt.Errorf("")
 
+	case "explain":
+		p.displayExplain(view)
+		return nil, fmt.Errorf("")
+
 	case "help":
 		fmt.Fprintf(p.writer, "\n  Available commands:\n")
 		fmt.Fprintf(p.writer, "    move <unit_id> <x> <y>       Move unit to position\n")
@@ -462,6 +515,7 @@ func (p *CLIPlayer) parseCommand(line string, currentPhase phase.Phase, view *ga
 		fmt.Fprintf(p.writer, "    charge <unit_id> <target_id> Declare a charge\n")
 		fmt.Fprintf(p.writer, "    skip                         End current phase\n")
 		fmt.Fprintf(p.writer, "    map                          Show battlefield map\n")
+		fmt.Fprintf(p.writer, "    explain                      Show which rules applied to the last action\n")
 		fmt.Fprintf(p.writer, "    help                         Show this help\n")
 		return nil, fmt.Errorf("")
 
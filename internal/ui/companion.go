@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jruiznavarro/wargamestactics/internal/game"
+	"github.com/jruiznavarro/wargamestactics/internal/game/command"
+	"github.com/jruiznavarro/wargamestactics/internal/game/core"
+	"github.com/jruiznavarro/wargamestactics/internal/game/phase"
+)
+
+// CompanionPlayer is a human player in a game played on a physical table,
+// with the engine keeping score. It takes the same commands as CLIPlayer,
+// plus declarations of where units stand, so coordinates are optional:
+//
+//	unit 3 is engaged with 7   (or: 3 engaged 7)
+//	unit 3 on objective 2      (or: 3 objective 2)
+//	unit 3 is clear            (or: 3 clear)
+//
+// Both players of a companion game share one terminal, so they should
+// share one reader.
+type CompanionPlayer struct {
+	*CLIPlayer
+	shownRound int             // Battle round of the last status shown
+	shownPhase phase.PhaseType // Phase of the last status shown
+}
+
+// NewCompanionPlayer creates a companion player reading from reader and
+// writing to writer.
+func NewCompanionPlayer(id int, name string, reader *bufio.Reader, writer io.Writer) *CompanionPlayer {
+	return &CompanionPlayer{CLIPlayer: &CLIPlayer{id: id, name: name, reader: reader, writer: writer}}
+}
+
+func (p *CompanionPlayer) GetNextCommand(view *game.GameView, currentPhase phase.Phase) interface{} {
+	if view.BattleRound != p.shownRound || view.CurrentPhase != p.shownPhase {
+		p.shownRound, p.shownPhase = view.BattleRound, view.CurrentPhase
+		p.displayStatus(view)
+		p.displayPrompt(currentPhase)
+		fmt.Fprintf(p.writer, "  Declare: <id> engaged <enemy> | <id> objective <n> | <id> clear | tactic <card> <tier> | status\n")
+	}
+
+	for {
+		fmt.Fprintf(p.writer, "%s> ", p.name)
+		line, err := p.reader.ReadString('\n')
+		if err != nil && line == "" {
+			return nil
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		cmd, parseErr := p.parseCompanion(line, view)
+		if cmd == nil && parseErr == nil {
+			cmd, parseErr = p.parseCommand(line, currentPhase, view)
+		}
+		if parseErr != nil {
+			if parseErr.Error() != "" {
+				fmt.Fprintf(p.writer, "  Error: %s\n", parseErr)
+			}
+			continue
+		}
+		return cmd
+	}
+}
+
+// declarationFiller is dropped from declarations so they can be typed as
+// sentences.
+var declarationFiller = map[string]bool{"unit": true, "is": true, "with": true, "on": true}
+
+// parseCompanion parses the commands CompanionPlayer adds to CLIPlayer's.
+// It returns nil, nil for anything else.
+func (p *CompanionPlayer) parseCompanion(line string, view *game.GameView) (interface{}, error) {
+	var parts []string
+	for _, f := range strings.Fields(strings.ToLower(line)) {
+		if !declarationFiller[f] {
+			parts = append(parts, f)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	switch parts[0] {
+	case "status":
+		p.displayStatus(view)
+		return nil, fmt.Errorf("")
+	case "tactic":
+		return p.parseTactic(parts)
+	case "tactics":
+		p.displayTactics(view)
+		return nil, fmt.Errorf("")
+	}
+
+	unitID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, nil
+	}
+	cmd := &command.DeclareCommand{OwnerID: p.id, UnitID: core.UnitID(unitID)}
+	switch {
+	case len(parts) == 3 && parts[1] == "engaged":
+		enemyID, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid unit ID: %s", parts[2])
+		}
+		cmd.EngagedWith = core.UnitID(enemyID)
+	case len(parts) == 3 && parts[1] == "objective":
+		objID, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid objective: %s", parts[2])
+		}
+		cmd.Objective = objID
+	case len(parts) == 2 && parts[1] == "clear":
+	default:
+		return nil, fmt.Errorf("usage: <unit_id> engaged <enemy_id> | <unit_id> objective <n> | <unit_id> clear")
+	}
+	return cmd, nil
+}
+
+func (p *CompanionPlayer) parseTactic(parts []string) (interface{}, error) {
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("usage: tactic <card 1-6> <affray|strike|domination>")
+	}
+	card, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid card: %s", parts[1])
+	}
+	tier := -1
+	for t := game.TierAffray; t <= game.TierDomination; t++ {
+		if strings.EqualFold(t.String(), parts[2]) || parts[2] == strconv.Itoa(int(t)) {
+			tier = int(t)
+		}
+	}
+	if tier < 0 {
+		return nil, fmt.Errorf("invalid tier: %s (use affray, strike or domination)", parts[2])
+	}
+	return &command.TacticCommand{OwnerID: p.id, Card: card, Tier: tier}, nil
+}
+
+// displayStatus shows the score and every unit's state, without the map.
+func (p *CompanionPlayer) displayStatus(view *game.GameView) {
+	fmt.Fprintf(p.writer, "\n== Round %d, %s: %s ==\n", view.BattleRound, view.CurrentPhase, p.name)
+	for _, id := range []int{1, 2} {
+		line := fmt.Sprintf("  Player %d: %d CP, %d VP", id, view.CommandPoints[id], view.VictoryPoints[id])
+		if bt := view.BattleTactics[id]; bt != nil && bt.ActiveTactic != nil {
+			state := "in progress"
+			if bt.ActiveTactic.Completed {
+				state = "completed"
+			}
+			line += fmt.Sprintf(", tactic %s (%s, %s)", bt.ActiveTactic.TacticName, bt.ActiveTactic.Tier, state)
+		}
+		fmt.Fprintln(p.writer, line)
+	}
+
+	onObjective := make(map[int]int)
+	for _, o := range view.Objectives {
+		for _, units := range view.Units {
+			for _, u := range units {
+				if dist(u.Position, o.Position) <= o.Radius {
+					onObjective[u.ID] = o.ID
+				}
+			}
+		}
+	}
+	for _, id := range []int{1, 2} {
+		units := append([]game.UnitView(nil), view.Units[id]...)
+		sort.Slice(units, func(i, j int) bool { return units[i].ID < units[j].ID })
+		for _, u := range units {
+			var flags []string
+			if u.IsEngaged {
+				flags = append(flags, "engaged")
+			}
+			if o, ok := onObjective[u.ID]; ok {
+				flags = append(flags, fmt.Sprintf("on objective %d", o))
+			}
+			status := ""
+			if len(flags) > 0 {
+				status = " [" + strings.Join(flags, ", ") + "]"
+			}
+			fmt.Fprintf(p.writer, "  P%d [%d] %-24s %d/%d models, %d/%d wounds%s\n",
+				id, u.ID, u.Name, u.AliveModels, u.TotalModels, u.CurrentWounds, u.MaxWounds, status)
+		}
+	}
+}
+
+func (p *CompanionPlayer) displayTactics(view *game.GameView) {
+	bt := view.BattleTactics[p.id]
+	if bt == nil {
+		fmt.Fprintf(p.writer, "  No battle tactics in this game.\n")
+		return
+	}
+	for _, c := range bt.AvailableCards {
+		fmt.Fprintf(p.writer, "  %d %s\n", c.CardID, c.CardName)
+		for _, t := range c.Tiers {
+			fmt.Fprintf(p.writer, "      %-10s %s: %s\n", t.Tier, t.Name, t.Description)
+		}
+	}
+}
+
+func dist(a, b [2]float64) float64 {
+	return core.Distance(core.Position{X: a[0], Y: a[1]}, core.Position{X: b[0], Y: b[1]})
+}
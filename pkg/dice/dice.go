@@ -1,22 +1,150 @@
 package dice
 
-import "math/rand"
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+)
 
-// Roller provides deterministic dice rolling using a seeded RNG.
+// Streams the game draws its dice from. Each stream is an independent
+// generator derived from the game seed, so an extra roll in one part of the
+// game does not shift the rolls made in another.
+const (
+	StreamCombat   = "combat"   // Attacks, damage and mortal wounds
+	StreamCharge   = "charge"   // Charge rolls and re-rolls
+	StreamMagic    = "magic"    // Casting, unbinding, chanting and spell effects
+	StreamPriority = "priority" // Priority roll-offs and seizing the initiative
+)
+
+// Roller provides deterministic dice rolling from a Source. Rollers created
+// with NewRoller use a PCG generator and hand out named sub-streams with
+// Stream; their state, streams included, can be saved with MarshalBinary
+// and restored with UnmarshalBinary.
 type Roller struct {
-	rng *rand.Rand
+	src     Source
+	seed    uint64
+	streams map[string]*Roller
+	derived bool // Stream gives each name its own generator
 }
 
 // NewRoller creates a new Roller with the given seed.
 func NewRoller(seed int64) *Roller {
 	return &Roller{
-		rng: rand.New(rand.NewSource(seed)),
+		src:     NewPCG(uint64(seed), streamKey("")),
+		seed:    uint64(seed),
+		derived: true,
+	}
+}
+
+// NewRollerFromSource creates a Roller that rolls every die, on every
+// stream, from src. Tests and tools use it to supply their own dice.
+func NewRollerFromSource(src Source) *Roller {
+	return &Roller{src: src}
+}
+
+// Stream returns the sub-stream with the given name, creating it on first
+// use. A Roller created with NewRollerFromSource returns itself.
+func (r *Roller) Stream(name string) *Roller {
+	if !r.derived || name == "" {
+		return r
+	}
+	if s, ok := r.streams[name]; ok {
+		return s
+	}
+	if r.streams == nil {
+		r.streams = make(map[string]*Roller)
+	}
+	s := &Roller{src: NewPCG(r.seed, streamKey(name))}
+	r.streams[name] = s
+	return s
+}
+
+// streamKey derives the second PCG seed word of a stream from its name.
+func streamKey(name string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return h.Sum64()
+}
+
+// MarshalBinary saves the state of the roller and of every stream created
+// so far. It fails if the roller's Source cannot be saved.
+func (r *Roller) MarshalBinary() ([]byte, error) {
+	if !r.derived {
+		return nil, errors.New("dice: roller source cannot be saved")
+	}
+	names := make([]string, 0, len(r.streams))
+	for name := range r.streams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := binary.BigEndian.AppendUint64(nil, r.seed)
+	for _, name := range append([]string{""}, names...) {
+		state, err := r.Stream(name).src.(*PCG).MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(name)))
+		buf = append(buf, name...)
+		buf = binary.AppendUvarint(buf, uint64(len(state)))
+		buf = append(buf, state...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary restores a state saved by MarshalBinary, replacing the
+// roller's seed, generator and streams.
+func (r *Roller) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("dice: roller state too short")
+	}
+	restored := &Roller{seed: binary.BigEndian.Uint64(data), derived: true}
+	data = data[8:]
+	for len(data) > 0 {
+		name, rest, err := readChunk(data)
+		if err != nil {
+			return err
+		}
+		state, rest, err := readChunk(rest)
+		if err != nil {
+			return err
+		}
+		data = rest
+
+		src := &PCG{}
+		if err := src.UnmarshalBinary(state); err != nil {
+			return fmt.Errorf("dice: stream %q: %w", name, err)
+		}
+		if len(name) == 0 {
+			restored.src = src
+			continue
+		}
+		if restored.streams == nil {
+			restored.streams = make(map[string]*Roller)
+		}
+		restored.streams[string(name)] = &Roller{src: src}
+	}
+	if restored.src == nil {
+		return errors.New("dice: roller state has no main stream")
+	}
+	*r = *restored
+	return nil
+}
+
+// readChunk reads a length-prefixed byte string.
+func readChunk(data []byte) (chunk, rest []byte, err error) {
+	n, k := binary.Uvarint(data)
+	if k <= 0 || uint64(len(data)-k) < n {
+		return nil, nil, errors.New("dice: malformed roller state")
 	}
+	return data[k : k+int(n)], data[k+int(n):], nil
 }
 
 // RollD6 returns a random number between 1 and 6.
 func (r *Roller) RollD6() int {
-	return r.rng.Intn(6) + 1
+	return r.src.Roll(6)
 }
 
 // Roll2D6 returns the sum of two D6 rolls.
@@ -26,7 +154,7 @@ func (r *Roller) Roll2D6() int {
 
 // RollD3 returns a random number between 1 and 3.
 func (r *Roller) RollD3() int {
-	return r.rng.Intn(3) + 1
+	return r.src.Roll(3)
 }
 
 // RollMultipleD6 rolls n D6s and returns all results.
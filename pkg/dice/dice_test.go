@@ -118,3 +118,75 @@ func TestDifferentSeeds_DifferentResults(t *testing.T) {
 		t.Fatal("different seeds should produce different sequences")
 	}
 }
+
+func TestStream_Independent(t *testing.T) {
+	r1 := NewRoller(42)
+	r2 := NewRoller(42)
+
+	// An extra roll on one stream must not shift the rolls on another.
+	r2.Stream(StreamCharge).RollD6()
+	r2.RollD6()
+
+	for i := 0; i < 100; i++ {
+		a := r1.Stream(StreamCombat).RollD6()
+		b := r2.Stream(StreamCombat).RollD6()
+		if a != b {
+			t.Fatalf("combat roll %d: got %d and %d after an extra charge roll", i, a, b)
+		}
+	}
+	if r1.Stream(StreamCombat) != r1.Stream(StreamCombat) {
+		t.Error("expected Stream to return the same roller for the same name")
+	}
+}
+
+func TestRoller_MarshalRoundTrip(t *testing.T) {
+	r := NewRoller(7)
+	r.RollD6()
+	r.Stream(StreamMagic).Roll2D6()
+
+	data, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	restored := &Roller{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+
+	for _, name := range []string{"", StreamMagic, StreamPriority} {
+		for i := 0; i < 20; i++ {
+			a := r.Stream(name).RollD6()
+			b := restored.Stream(name).RollD6()
+			if a != b {
+				t.Fatalf("stream %q roll %d: got %d after restoring, want %d", name, i, b, a)
+			}
+		}
+	}
+
+	if err := restored.UnmarshalBinary(data[:5]); err == nil {
+		t.Error("expected error for truncated state")
+	}
+}
+
+type fixedSource []int
+
+func (f *fixedSource) Roll(sides int) int {
+	v := (*f)[0]
+	*f = (*f)[1:]
+	return v
+}
+
+func TestNewRollerFromSource(t *testing.T) {
+	src := &fixedSource{1, 6, 3}
+	r := NewRollerFromSource(src)
+
+	if r.Stream(StreamCombat) != r {
+		t.Error("expected streams of a source roller to share its source")
+	}
+	if got := r.RollMultipleD6(3); got[0] != 1 || got[1] != 6 || got[2] != 3 {
+		t.Errorf("expected scripted rolls [1 6 3], got %v", got)
+	}
+	if _, err := r.MarshalBinary(); err == nil {
+		t.Error("expected error saving a roller with an injected source")
+	}
+}
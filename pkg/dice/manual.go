@@ -0,0 +1,94 @@
+package dice
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Manual is a Source that asks a person for the results of physical dice,
+// so the engine can be played with real dice. The results of a whole roll
+// can be typed on one line; re-rolls are asked for separately. Invalid
+// results are asked for again. If the input ends, Roll panics with a
+// *RollError.
+type Manual struct {
+	label
+	in      *bufio.Reader
+	out     io.Writer
+	pending []string // Results typed ahead for the current roll
+	held    []string // Pending results kept aside during a re-roll
+}
+
+// NewManual creates a manual source that prompts on out and reads results
+// from in. If in is a *bufio.Reader it is read directly, so it can be shared
+// with other prompts reading the same input.
+func NewManual(in io.Reader, out io.Writer) *Manual {
+	return &Manual{in: bufio.NewReader(in), out: out}
+}
+
+// Remind shows which rules modify the next roll.
+func (m *Manual) Remind(note string) {
+	fmt.Fprintf(m.out, "  Remember: %s\n", note)
+}
+
+// Label names the next die. Results typed ahead are kept aside while a
+// re-roll is asked for, and dropped when a new roll starts.
+func (m *Manual) Label(what string, i, n int) {
+	switch {
+	case i == 0:
+		m.held, m.pending = m.pending, nil
+	case i == 1:
+		m.dropPending()
+	}
+	m.label.Label(what, i, n)
+}
+
+func (m *Manual) dropPending() {
+	if len(m.pending) > 0 {
+		fmt.Fprintf(m.out, "  ignoring unused results %v\n", m.pending)
+		m.pending = nil
+	}
+}
+
+// Roll asks for the result of one die.
+func (m *Manual) Roll(sides int) int {
+	reroll := m.what != "" && m.i == 0
+	if m.what == "" {
+		m.dropPending()
+	}
+	v := m.read(sides)
+	if reroll {
+		m.dropPending()
+		m.pending, m.held = m.held, nil
+	}
+	m.rolled()
+	return v
+}
+
+// read returns the next valid result, prompting for more when none are
+// pending.
+func (m *Manual) read(sides int) int {
+	for {
+		if len(m.pending) == 0 {
+			fmt.Fprintf(m.out, "%s (D%d): ", m.describe(), sides)
+			line, err := m.in.ReadString('\n')
+			if err != nil && line == "" {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				panic(&RollError{Label: m.describe(), Err: err})
+			}
+			m.pending = strings.Fields(line)
+			continue
+		}
+		field := m.pending[0]
+		m.pending = m.pending[1:]
+		if v, err := strconv.Atoi(field); err == nil && v >= 1 && v <= sides {
+			return v
+		}
+		fmt.Fprintf(m.out, "  %q is not a D%d result\n", field, sides)
+		m.pending = nil
+	}
+}
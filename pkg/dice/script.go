@@ -0,0 +1,183 @@
+package dice
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Labeler is implemented by sources that want to know what the dice they
+// roll are for, such as Script and Manual. Roller.Label passes labels on to
+// them.
+type Labeler interface {
+	// Label names the next die: die i of the n dice of a what roll, e.g.
+	// ("hit", 1, 5), or a re-roll if i is 0. Dice rolled after it without a
+	// new label, including after a re-roll, are the following dice of the
+	// same roll.
+	Label(what string, i, n int)
+}
+
+// Label names the next die rolled by r: die i of the n dice of a what roll,
+// or a re-roll if i is 0. It does nothing unless r's source implements
+// Labeler.
+func (r *Roller) Label(what string, i, n int) {
+	if l, ok := r.src.(Labeler); ok {
+		l.Label(what, i, n)
+	}
+}
+
+// Reminder is implemented by sources that show the person rolling which
+// rules modify the next roll, such as Manual.
+type Reminder interface {
+	Remind(note string)
+}
+
+// Reminds reports whether r's source takes reminders, so callers only build
+// them when they will be shown.
+func (r *Roller) Reminds() bool {
+	_, ok := r.src.(Reminder)
+	return ok
+}
+
+// Remind passes note, e.g. "hit: +1 hit (All-out Attack)", to r's source if
+// it takes reminders.
+func (r *Roller) Remind(note string) {
+	if rm, ok := r.src.(Reminder); ok {
+		rm.Remind(note)
+	}
+}
+
+// ErrScriptExhausted is reported when a Script has no values left.
+var ErrScriptExhausted = errors.New("script exhausted")
+
+// RollError reports a die a Source could not supply. Source.Roll cannot
+// return an error, so Script and Manual panic with a *RollError; use
+// CatchRollError to turn it back into an error.
+type RollError struct {
+	Label string // What the die was for, e.g. "hit roll, die 3 of 5"
+	Err   error
+}
+
+func (e *RollError) Error() string {
+	return fmt.Sprintf("dice: %s: %v", e.Label, e.Err)
+}
+
+func (e *RollError) Unwrap() error {
+	return e.Err
+}
+
+// CatchRollError recovers a *RollError panic into *err. Other panics are
+// re-raised. Call it deferred:
+//
+//	defer dice.CatchRollError(&err)
+func CatchRollError(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if re, ok := r.(*RollError); ok {
+		*err = re
+		return
+	}
+	panic(r)
+}
+
+// label tracks what the next die is for, for Labeler sources.
+type label struct {
+	what   string
+	i, n   int
+	resume int // Die of the roll that follows a re-roll
+}
+
+func (l *label) Label(what string, i, n int) {
+	l.resume = 0
+	if i == 0 && what == l.what && l.i > 0 {
+		l.resume = l.i
+	}
+	l.what, l.i, l.n = what, i, n
+}
+
+// rolled moves the label on to the following die of the roll, or clears it
+// once every die of the roll has been rolled.
+func (l *label) rolled() {
+	switch {
+	case l.i == 0 && l.resume > 0:
+		l.i, l.resume = l.resume, 0
+	case l.i > 0 && l.i < l.n:
+		l.i++
+	default:
+		*l = label{}
+	}
+}
+
+// describe describes the next die, e.g. "hit roll, die 3 of 5".
+func (l *label) describe() string {
+	switch {
+	case l.what == "":
+		return "roll"
+	case l.i == 0:
+		return l.what + " re-roll"
+	case l.n <= 1:
+		return l.what + " roll"
+	default:
+		return fmt.Sprintf("%s roll, die %d of %d", l.what, l.i, l.n)
+	}
+}
+
+// Script is a Source that returns an explicit sequence of values, so tests
+// can play out an exact sequence of rolls. Values added with Expect must be
+// rolled for what they were added for. Rolling past the end of the script, or a value
+// that does not fit the die or the label, panics with a *RollError.
+type Script struct {
+	label
+	values []scripted
+	log    []string
+}
+
+type scripted struct {
+	value int
+	what  string
+}
+
+// NewScript creates a script that returns values in order, whatever they
+// are rolled for.
+func NewScript(values ...int) *Script {
+	return (&Script{}).Expect("", values...)
+}
+
+// Expect adds values that must be rolled for what, as named by
+// Roller.Label, e.g. "hit"; re-rolls count as the roll they re-roll. An
+// empty what accepts any roll. Returns s so calls can be chained.
+func (s *Script) Expect(what string, values ...int) *Script {
+	for _, v := range values {
+		s.values = append(s.values, scripted{value: v, what: what})
+	}
+	return s
+}
+
+// Roll returns the next value of the script.
+func (s *Script) Roll(sides int) int {
+	if len(s.values) == 0 {
+		panic(&RollError{Label: s.describe(), Err: ErrScriptExhausted})
+	}
+	v := s.values[0]
+	if v.what != "" && v.what != s.what {
+		panic(&RollError{Label: s.describe(), Err: fmt.Errorf("script expects a %s roll", v.what)})
+	}
+	if v.value < 1 || v.value > sides {
+		panic(&RollError{Label: s.describe(), Err: fmt.Errorf("scripted %d is not a D%d result", v.value, sides)})
+	}
+	s.values = s.values[1:]
+	s.log = append(s.log, fmt.Sprintf("%s: %d", s.describe(), v.value))
+	s.label.rolled()
+	return v.value
+}
+
+// Remaining returns the number of values not yet rolled.
+func (s *Script) Remaining() int {
+	return len(s.values)
+}
+
+// Rolled returns a line per die rolled so far, e.g. "hit roll, die 1 of 2: 6".
+func (s *Script) Rolled() []string {
+	return s.log
+}
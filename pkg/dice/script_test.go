@@ -0,0 +1,94 @@
+package dice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// rollErr runs fn and returns the RollError it panics with, if any.
+func rollErr(fn func()) (err error) {
+	defer CatchRollError(&err)
+	fn()
+	return nil
+}
+
+func TestScript_LabelsAndErrors(t *testing.T) {
+	script := NewScript().Expect("charge", 4, 5).Expect("", 2)
+	r := NewRollerFromSource(script)
+
+	r.Label("charge", 1, 2)
+	if got := r.Roll2D6(); got != 9 {
+		t.Fatalf("expected scripted 2D6 of 9, got %d", got)
+	}
+	r.Label("ward", 0, 1)
+	if got := r.RollD3(); got != 2 {
+		t.Fatalf("expected scripted D3 of 2, got %d", got)
+	}
+	want := []string{"charge roll, die 1 of 2: 4", "charge roll, die 2 of 2: 5", "ward re-roll: 2"}
+	if got := script.Rolled(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("expected rolls %q, got %q", want, got)
+	}
+
+	err := rollErr(func() { r.RollD6() })
+	if !errors.Is(err, ErrScriptExhausted) || !strings.Contains(err.Error(), "dice: roll:") {
+		t.Errorf("expected exhausted error for an unlabelled roll, got %v", err)
+	}
+
+	r = NewRollerFromSource(NewScript().Expect("hit", 6))
+	r.Label("save", 1, 1)
+	if err := rollErr(func() { r.RollD6() }); err == nil || !strings.Contains(err.Error(), "expects a hit roll") {
+		t.Errorf("expected label mismatch error, got %v", err)
+	}
+
+	r = NewRollerFromSource(NewScript(4))
+	if err := rollErr(func() { r.RollD3() }); err == nil || !strings.Contains(err.Error(), "not a D3 result") {
+		t.Errorf("expected out of range error, got %v", err)
+	}
+}
+
+func TestCatchRollError_RepanicsOtherPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("expected other panics to pass through, got %v", r)
+		}
+	}()
+	rollErr(func() { panic("boom") })
+}
+
+func TestManual_ReadsResults(t *testing.T) {
+	var out strings.Builder
+	in := strings.NewReader("7\n1 4\n5\n2 6\n3\n")
+	r := NewRollerFromSource(NewManual(in, &out))
+
+	r.Label("hit", 1, 2)
+	first := r.RollD6() // 7 is rejected and asked for again
+	r.Label("hit", 0, 2)
+	reroll := r.RollD6() // Asked for separately; the typed-ahead 4 is kept
+	second := r.RollD6()
+	if first != 1 || reroll != 5 || second != 4 {
+		t.Errorf("expected 1, re-roll 5, then 4, got %d, %d, %d", first, reroll, second)
+	}
+	if got := r.RollD6(); got != 2 {
+		t.Errorf("expected 2, got %d", got)
+	}
+	if !r.Reminds() || NewRoller(1).Reminds() {
+		t.Error("expected only the manual source to take reminders")
+	}
+	r.Remind("save: -1 save (Mystic Shield)")
+	r.Label("save", 1, 1) // A new roll drops the unused 6
+	if got := r.RollD6(); got != 3 {
+		t.Errorf("expected 3, got %d", got)
+	}
+	if err := rollErr(func() { r.RollD6() }); err == nil {
+		t.Error("expected error when input ends")
+	}
+
+	prompts := out.String()
+	for _, want := range []string{"hit roll, die 1 of 2 (D6): ", `"7" is not a D6 result`, "hit re-roll (D6): ", "ignoring unused results [6]",
+		"Remember: save: -1 save (Mystic Shield)\n", "save roll (D6): "} {
+		if !strings.Contains(prompts, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, prompts)
+		}
+	}
+}
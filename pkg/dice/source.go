@@ -0,0 +1,41 @@
+package dice
+
+import "math/rand/v2"
+
+// Source supplies the dice a Roller rolls.
+type Source interface {
+	// Roll returns a value between 1 and sides.
+	Roll(sides int) int
+}
+
+// PCG is a Source backed by a PCG generator. Its state can be saved with
+// MarshalBinary and restored with UnmarshalBinary.
+type PCG struct {
+	pcg rand.PCG
+	rng *rand.Rand
+}
+
+// NewPCG creates a PCG source seeded with the two seed words.
+func NewPCG(seed1, seed2 uint64) *PCG {
+	p := &PCG{}
+	p.pcg.Seed(seed1, seed2)
+	return p
+}
+
+// Roll returns a value between 1 and sides.
+func (p *PCG) Roll(sides int) int {
+	if p.rng == nil {
+		p.rng = rand.New(&p.pcg)
+	}
+	return p.rng.IntN(sides) + 1
+}
+
+// MarshalBinary returns the generator's state.
+func (p *PCG) MarshalBinary() ([]byte, error) {
+	return p.pcg.MarshalBinary()
+}
+
+// UnmarshalBinary restores a state returned by MarshalBinary.
+func (p *PCG) UnmarshalBinary(data []byte) error {
+	return p.pcg.UnmarshalBinary(data)
+}